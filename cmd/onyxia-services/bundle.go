@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/kube"
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/adapters/helm"
+	"github.com/onyxia-datalab/onyxia-backend/services/adapters/k8s"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+	"sigs.k8s.io/yaml"
+)
+
+// registryPasswordEnv holds the registry password of import-catalogs, kept
+// out of the command line.
+const registryPasswordEnv = "ONYXIA_REGISTRY_PASSWORD"
+
+// runExportCatalogs exports the configured catalogs into a bundle, for
+// mirroring them into an air-gapped region.
+func runExportCatalogs(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export-catalogs", flag.ContinueOnError)
+	out := fs.String("out", "catalogs.tgz", "bundle file to write")
+	catalogs := fs.String("catalogs", "", "comma-separated ids of the catalogs to export (default: all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	userReader, _ := usercontext.NewUserContext()
+	bootstrap.InitLogger(userReader)
+
+	cfg, err := env.New()
+	if err != nil {
+		return fmt.Errorf("failed to load environment: %w", err)
+	}
+
+	var ids []string
+	if *catalogs != "" {
+		ids = strings.Split(*catalogs, ",")
+	}
+
+	secrets, err := catalogSecretReader(cfg.CatalogsConfig)
+	if err != nil {
+		return err
+	}
+	pkgRepo, err := helm.NewPackageRepository(cfg.CatalogsConfig, "", 0, secrets)
+	if err != nil {
+		return fmt.Errorf("failed to setup package repository: %w", err)
+	}
+
+	// Written next to its destination and renamed once complete, so a failed
+	// export never leaves a truncated bundle.
+	f, err := os.CreateTemp(filepath.Dir(*out), filepath.Base(*out)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := pkgRepo.ExportBundle(ctx, f, ids); err != nil {
+		f.Close()
+		return fmt.Errorf("export failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), *out)
+}
+
+// runImportCatalogs loads a bundle into a directory or an OCI registry and
+// prints the configuration of the imported catalogs.
+func runImportCatalogs(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import-catalogs", flag.ContinueOnError)
+	in := fs.String("bundle", "catalogs.tgz", "bundle file to read")
+	target := fs.String("target", "", "directory, or oci:// registry location, to import into")
+	username := fs.String("username", "", "registry username; the password is read from "+registryPasswordEnv)
+	plainHTTP := fs.Bool("plain-http", false, "use plain HTTP to reach the registry")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *target == "" {
+		return errors.New("-target is required")
+	}
+
+	userReader, _ := usercontext.NewUserContext()
+	bootstrap.InitLogger(userReader)
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	catalogs, err := helm.ImportBundle(ctx, f, helm.ImportOptions{
+		Target:    *target,
+		Username:  *username,
+		Password:  os.Getenv(registryPasswordEnv),
+		PlainHTTP: *plainHTTP,
+	})
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	out, err := yaml.Marshal(map[string]any{"catalogs": catalogs})
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// catalogSecretReader connects to Kubernetes only when a catalog keeps its
+// credentials in a Secret, so exports run anywhere otherwise.
+func catalogSecretReader(catalogs []env.CatalogConfig) (ports.CatalogSecretReader, error) {
+	for _, c := range catalogs {
+		if c.Credentials == nil || c.Credentials.Secret == nil {
+			continue
+		}
+		client, err := kube.NewClient("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Kubernetes client: %w", err)
+		}
+		return k8s.NewCatalogSecretReader(client.Clientset()), nil
+	}
+	return nil, nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -24,6 +26,26 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if len(os.Args) > 1 {
+		var run func(context.Context, []string) error
+		switch os.Args[1] {
+		case "export-catalogs":
+			run = runExportCatalogs
+		case "import-catalogs":
+			run = runImportCatalogs
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q, expected export-catalogs or import-catalogs\n", os.Args[1])
+			os.Exit(2)
+		}
+		if err := run(ctx, os.Args[2:]); errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		} else if err != nil {
+			slog.Error("command failed", slog.String("command", os.Args[1]), slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	app, err := bootstrap.NewApplication(ctx)
 	if err != nil {
 		slog.Error("failed to initialize application",
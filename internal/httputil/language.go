@@ -0,0 +1,87 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type languagesKey struct{}
+
+// Languages negotiates the language of responses. The `lang` query parameter
+// takes precedence over the Accept-Language header. When either is present,
+// the requested languages, best first and followed by defaultLanguage, are
+// stored in the request context; see LanguagesFromContext.
+func Languages(defaultLanguage string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var langs []string
+			if lang := strings.TrimSpace(r.URL.Query().Get("lang")); lang != "" {
+				langs = []string{lang}
+			} else {
+				langs = ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+			}
+
+			if len(langs) > 0 {
+				if defaultLanguage != "" {
+					langs = append(langs, defaultLanguage)
+				}
+				r = r.WithContext(WithLanguages(r.Context(), langs))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithLanguages returns a copy of ctx carrying the negotiated languages.
+func WithLanguages(ctx context.Context, langs []string) context.Context {
+	return context.WithValue(ctx, languagesKey{}, langs)
+}
+
+// LanguagesFromContext returns the negotiated languages, best first, or nil
+// when the client did not ask for any.
+func LanguagesFromContext(ctx context.Context) []string {
+	langs, _ := ctx.Value(languagesKey{}).([]string)
+	return langs
+}
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language header,
+// by decreasing quality. The wildcard and tags with q=0 are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, t.tag)
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func negotiatedLanguages(r *http.Request) []string {
+	var langs []string
+	handler := Languages("en")(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		langs = LanguagesFromContext(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	return langs
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t,
+		[]string{"fr-CH", "fr", "de", "en"},
+		ParseAcceptLanguage("en;q=0.5, fr-CH, de;q=0.7, fr;q=0.9, *;q=0.1"),
+	)
+	assert.Equal(t, []string{"nl"}, ParseAcceptLanguage("nl, it;q=0, es;q=bogus"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestLanguages_NoPreference(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://example.com/api", nil)
+	assert.Nil(t, negotiatedLanguages(req))
+}
+
+func TestLanguages_AcceptLanguage(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://example.com/api", nil)
+	req.Header.Set("Accept-Language", "fr;q=0.8, de")
+	assert.Equal(t, []string{"de", "fr", "en"}, negotiatedLanguages(req))
+}
+
+func TestLanguages_QueryTakesPrecedence(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://example.com/api?lang=zh-CN", nil)
+	req.Header.Set("Accept-Language", "fr")
+	assert.Equal(t, []string{"zh-CN", "en"}, negotiatedLanguages(req))
+}
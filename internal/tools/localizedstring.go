@@ -1,6 +1,10 @@
 package tools
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 type LocalizedStringType string
 
@@ -76,3 +80,60 @@ func (s LocalizedString) GetMulti() (MultiLangString, bool) {
 func (s LocalizedString) Type() LocalizedStringType {
 	return s.typ
 }
+
+// Localize returns the best translation for langs, given best first. A
+// language matches a translation of the same language with or without a
+// region, so "fr-CA" falls back to "fr" and "zh" matches "zh-CN". When no
+// language matches, the first translation in key order is returned.
+func (s LocalizedString) Localize(langs []string) string {
+	if s.IsPlain() {
+		return s.plain
+	}
+	if len(s.multi) == 0 {
+		return ""
+	}
+
+	for _, lang := range langs {
+		if v, ok := s.lookup(lang); ok {
+			return v
+		}
+	}
+
+	keys := make([]string, 0, len(s.multi))
+	for k := range s.multi {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return s.multi[keys[0]]
+}
+
+func (s LocalizedString) lookup(lang string) (string, bool) {
+	for k, v := range s.multi {
+		if strings.EqualFold(k, lang) {
+			return v, true
+		}
+	}
+
+	base := baseLanguage(lang)
+	if v, ok := s.multi[base]; ok {
+		return v, true
+	}
+	// Deterministic among several regional variants of the same language.
+	var (
+		best  string
+		found bool
+	)
+	for k := range s.multi {
+		if strings.EqualFold(baseLanguage(k), base) && (!found || k < best) {
+			best, found = k, true
+		}
+	}
+	return s.multi[best], found
+}
+
+// baseLanguage returns the primary subtag of a language tag, lower-cased.
+func baseLanguage(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	base, _, _ = strings.Cut(base, "_")
+	return strings.ToLower(base)
+}
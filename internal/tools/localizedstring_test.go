@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalizedString_Localize(t *testing.T) {
+	s, err := NewLocalizedString(MultiLangString{
+		"en":    "Development environments",
+		"fr":    "Environnements de développement",
+		"zh-CN": "开发环境",
+		"pt-PT": "Ambientes de desenvolvimento",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Environnements de développement", s.Localize([]string{"fr", "en"}))
+	assert.Equal(t, "开发环境", s.Localize([]string{"ZH-cn"}))
+	// Falls back to the base language, then to a regional variant.
+	assert.Equal(t, "Environnements de développement", s.Localize([]string{"fr-CA"}))
+	assert.Equal(t, "Ambientes de desenvolvimento", s.Localize([]string{"pt-BR"}))
+	// Then to the next requested language.
+	assert.Equal(t, "Development environments", s.Localize([]string{"de", "en"}))
+	// Without any match, the first language in sorted order.
+	assert.Equal(t, "Development environments", s.Localize([]string{"de"}))
+
+	plain, err := NewLocalizedString("Databases")
+	require.NoError(t, err)
+	assert.Equal(t, "Databases", plain.Localize([]string{"fr"}))
+}
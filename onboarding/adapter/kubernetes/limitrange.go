@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const LimitRangeName string = "onyxia-limitrange"
+
+func (s *KubernetesNamespaceService) ApplyLimitRange(
+	ctx context.Context,
+	namespace string,
+	limitRange *domain.LimitRange,
+) (port.LimitRangeApplicationResult, error) {
+	limitRangesClient := s.clientset.CoreV1().LimitRanges(namespace)
+
+	// ✅ If no values are set, return early
+	if limitRange.IsEmpty() {
+		return port.LimitRangeUnchanged, nil
+	}
+
+	desired := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LimitRangeName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"created-by": "onyxia",
+			},
+		},
+		Spec: v1.LimitRangeSpec{
+			Limits: []v1.LimitRangeItem{convertLimitRangeToItem(*limitRange)},
+		},
+	}
+
+	existing, err := limitRangesClient.Get(ctx, LimitRangeName, metav1.GetOptions{})
+
+	if err == nil {
+		if ignore, ok := existing.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
+			return port.LimitRangeIgnored, nil
+		}
+
+		if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+			return port.LimitRangeUnchanged, nil
+		}
+
+		existing.Spec = desired.Spec
+		if _, err := limitRangesClient.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to update limit range: %w", err)
+		}
+		return port.LimitRangeUpdated, nil
+	}
+
+	if errors.IsNotFound(err) {
+		if _, err := limitRangesClient.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create limit range: %w", err)
+		}
+		return port.LimitRangeCreated, nil
+	}
+
+	return "", fmt.Errorf("unexpected error checking for existing limit range: %w", err)
+}
+
+func convertLimitRangeToItem(limitRange domain.LimitRange) v1.LimitRangeItem {
+	return v1.LimitRangeItem{
+		Type:           v1.LimitTypeContainer,
+		Default:        convertToResourceList(limitRange.DefaultLimit),
+		DefaultRequest: convertToResourceList(limitRange.DefaultRequest),
+		Max:            convertToResourceList(limitRange.Max),
+		Min:            convertToResourceList(limitRange.Min),
+	}
+}
+
+// convertToResourceList leaves unset values nil, as the API server returns
+// them, so that applied limit ranges compare equal.
+func convertToResourceList(values map[string]resource.Quantity) v1.ResourceList {
+	if len(values) == 0 {
+		return nil
+	}
+	list := make(v1.ResourceList, len(values))
+	for name, quantity := range values {
+		list[v1.ResourceName(name)] = quantity
+	}
+	return list
+}
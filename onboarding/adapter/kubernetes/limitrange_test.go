@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var testLimitRange = &domain.LimitRange{
+	DefaultLimit: map[string]resource.Quantity{
+		"cpu":    resource.MustParse("1"),
+		"memory": resource.MustParse("2Gi"),
+	},
+	DefaultRequest: map[string]resource.Quantity{
+		"cpu":    resource.MustParse("100m"),
+		"memory": resource.MustParse("512Mi"),
+	},
+}
+
+// ✅ Test: Create Limit Range Successfully
+func TestApplyLimitRangeCreated(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(context.Background(), "test-namespace", testLimitRange)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.LimitRangeCreated, result)
+
+	lr, err := clientset.CoreV1().
+		LimitRanges("test-namespace").
+		Get(context.Background(), LimitRangeName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, lr.Spec.Limits, 1)
+	item := lr.Spec.Limits[0]
+	assert.Equal(t, v1.LimitTypeContainer, item.Type)
+	assert.True(t, item.Default.Memory().Equal(resource.MustParse("2Gi")))
+	assert.True(t, item.DefaultRequest.Cpu().Equal(resource.MustParse("100m")))
+	assert.Nil(t, item.Max)
+	assert.Equal(t, "onyxia", lr.Labels["created-by"])
+}
+
+// ✅ Test: Applying Twice Is Idempotent
+func TestApplyLimitRangeUnchanged(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	_, err := service.ApplyLimitRange(context.Background(), "test-namespace", testLimitRange)
+	require.NoError(t, err)
+	result, err := service.ApplyLimitRange(context.Background(), "test-namespace", testLimitRange)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.LimitRangeUnchanged, result)
+}
+
+// ✅ Test: Limit Range Updated
+func TestApplyLimitRangeUpdated(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+	_, err := service.ApplyLimitRange(context.Background(), "test-namespace", testLimitRange)
+	require.NoError(t, err)
+
+	bigger := &domain.LimitRange{DefaultLimit: map[string]resource.Quantity{
+		"cpu":    resource.MustParse("2"),
+		"memory": resource.MustParse("4Gi"),
+	}}
+	result, err := service.ApplyLimitRange(context.Background(), "test-namespace", bigger)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.LimitRangeUpdated, result)
+}
+
+// ✅ Test: Limit Range Ignored Due To Annotation
+func TestApplyLimitRangeIgnored(t *testing.T) {
+	clientset := fake.NewClientset(&v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        LimitRangeName,
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{IgnoreQuotaAnnotation: "true"},
+		},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(context.Background(), "test-namespace", testLimitRange)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.LimitRangeIgnored, result)
+}
+
+// ✅ Test: Empty Limit Range Is Not Applied
+func TestApplyLimitRangeEmpty(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(context.Background(), "test-namespace", &domain.LimitRange{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.LimitRangeUnchanged, result)
+	assert.Empty(t, clientset.Actions())
+}
+
+// ❌ Test: Failure When Creating Limit Range
+func TestApplyLimitRangeFailureCreate(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor(
+		"create",
+		"limitranges",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("create error")
+		},
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	_, err := service.ApplyLimitRange(context.Background(), "test-namespace", testLimitRange)
+
+	assert.ErrorContains(t, err, "failed to create limit range")
+}
@@ -96,15 +96,11 @@ func (s *KubernetesNamespaceService) ApplyResourceQuotas(
 ) (port.QuotaApplicationResult, error) {
 	quotasClient := s.clientset.CoreV1().ResourceQuotas(namespace)
 
-	hardLimits, err := convertQuotaToResourceMap(*quota)
+	hardLimits := convertQuotaToResourceMap(*quota)
 
-	if err != nil {
-		return "", fmt.Errorf("error converting quota to ResourceQuota: %w", err)
-	}
-
-	// ✅ If no valid quotas exist, return early
+	// ✅ If no valid quotas exist, drop the quota Onyxia applied before
 	if len(hardLimits) == 0 {
-		return port.QuotaUnchanged, nil
+		return s.deleteResourceQuota(ctx, namespace)
 	}
 
 	resourceQuota := &v1.ResourceQuota{
@@ -160,6 +156,39 @@ func (s *KubernetesNamespaceService) ApplyResourceQuotas(
 	)
 }
 
+// deleteResourceQuota removes the quota created by Onyxia, if any, unless it
+// is marked as ignored.
+func (s *KubernetesNamespaceService) deleteResourceQuota(
+	ctx context.Context,
+	namespace string,
+) (port.QuotaApplicationResult, error) {
+	quotasClient := s.clientset.CoreV1().ResourceQuotas(namespace)
+
+	existingQuota, err := quotasClient.Get(ctx, QuotaName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return port.QuotaUnchanged, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf(
+			"unexpected error checking for existing quota: %w",
+			err,
+		)
+	}
+
+	if ignore, ok := existingQuota.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
+		return port.QuotaIgnored, nil
+	}
+	if existingQuota.Labels["created-by"] != "onyxia" {
+		return port.QuotaUnchanged, nil
+	}
+
+	err = quotasClient.Delete(ctx, QuotaName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to delete resource quota: %w", err)
+	}
+	return port.QuotaDeleted, nil
+}
+
 func quotasAreDifferent(existing, newQuota *v1.ResourceQuota) bool {
 	if len(existing.Spec.Hard) != len(newQuota.Spec.Hard) {
 		return true
@@ -175,31 +204,10 @@ func quotasAreDifferent(existing, newQuota *v1.ResourceQuota) bool {
 	return false
 }
 
-func convertQuotaToResourceMap(quota domain.Quota) (map[v1.ResourceName]resource.Quantity, error) {
-	quotaEntries := map[v1.ResourceName]string{
-		v1.ResourceRequestsMemory:                  quota.MemoryRequest,
-		v1.ResourceRequestsCPU:                     quota.CPURequest,
-		v1.ResourceLimitsMemory:                    quota.MemoryLimit,
-		v1.ResourceLimitsCPU:                       quota.CPULimit,
-		v1.ResourceRequestsStorage:                 quota.StorageRequest,
-		v1.ResourceName("count/pods"):              quota.MaxPods,
-		v1.ResourceRequestsEphemeralStorage:        quota.EphemeralStorageRequest,
-		v1.ResourceLimitsEphemeralStorage:          quota.EphemeralStorageLimit,
-		v1.ResourceName("requests.nvidia.com/gpu"): quota.GPURequest,
-		v1.ResourceName("limits.nvidia.com/gpu"):   quota.GPULimit,
+func convertQuotaToResourceMap(quota domain.Quota) map[v1.ResourceName]resource.Quantity {
+	hardLimits := make(map[v1.ResourceName]resource.Quantity, len(quota))
+	for name, quantity := range quota {
+		hardLimits[v1.ResourceName(name)] = quantity
 	}
-
-	// ✅ Filter out empty values and create a new immutable map
-	hardLimits := make(map[v1.ResourceName]resource.Quantity, len(quotaEntries))
-	for key, value := range quotaEntries {
-		if value != "" {
-			quantity, err := resource.ParseQuantity(value)
-			if err != nil {
-				return nil, err
-			}
-			hardLimits[key] = quantity
-		}
-	}
-
-	return hardLimits, nil
+	return hardLimits
 }
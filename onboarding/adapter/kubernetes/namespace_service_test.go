@@ -154,7 +154,10 @@ func TestApplyResourceQuotasSuccess(t *testing.T) {
 	clientset := fake.NewClientset()
 	service := NewKubernetesNamespaceService(clientset)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi", CPURequest: "10"}
+	quota := &domain.Quota{
+		"requests.memory": resource.MustParse("10Gi"),
+		"requests.cpu":    resource.MustParse("10"),
+	}
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -174,7 +177,7 @@ func TestApplyResourceQuotasUnchangedQuota(t *testing.T) {
 	})
 	service := NewKubernetesNamespaceService(clientset)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"} // Same values as existing
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")} // Same values as existing
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -195,7 +198,7 @@ func TestApplyResourceQuotasIgnoredQuota(t *testing.T) {
 	})
 	service := NewKubernetesNamespaceService(clientset)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"}
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")}
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -216,7 +219,7 @@ func TestApplyResourceQuotasFailureCheck(t *testing.T) {
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"}
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")}
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -238,7 +241,7 @@ func TestApplyResourceQuotasFailureCreate(t *testing.T) {
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"}
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")}
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -275,7 +278,7 @@ func TestApplyResourceQuotasQuotaUpdated(t *testing.T) {
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"} // 👈 Updated quota
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")} // 👈 Updated quota
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -295,7 +298,7 @@ func TestApplyResourceQuotasUnexpectedGetError(t *testing.T) {
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"}
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")}
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -325,7 +328,7 @@ func TestApplyResourceQuotasFailureUpdate(t *testing.T) {
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"} // 👈 Updated quota
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")} // 👈 Updated quota
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -338,7 +341,7 @@ func TestApplyResourceQuotasEmptyQuota(t *testing.T) {
 	clientset := fake.NewClientset()
 	service := NewKubernetesNamespaceService(clientset)
 
-	quota := &domain.Quota{} // 👈 Empty quota should return "QuotaUnchanged"
+	quota := &domain.Quota{} // 👈 Empty quota without existing quota should return "QuotaUnchanged"
 
 	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 
@@ -346,27 +349,89 @@ func TestApplyResourceQuotasEmptyQuota(t *testing.T) {
 	assert.Equal(t, port.QuotaUnchanged, result)
 }
 
-func TestApplyResourceQuotasFailureConvertQuota(t *testing.T) {
-	clientset := fake.NewClientset()
-	service := NewKubernetesNamespaceService(clientset)
+// ✅ Test: Empty Quota Deletes the Quota Created by Onyxia
+func TestApplyResourceQuotasEmptyQuotaDeletes(t *testing.T) {
+	existing := func(labels, annotations map[string]string) *v1.ResourceQuota {
+		return &v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        QuotaName,
+				Namespace:   "test-namespace",
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Spec: v1.ResourceQuotaSpec{
+				Hard: map[v1.ResourceName]resource.Quantity{
+					v1.ResourceRequestsMemory: resource.MustParse("5Gi"),
+				},
+			},
+		}
+	}
+	onyxia := map[string]string{"created-by": "onyxia"}
+
+	for name, tc := range map[string]struct {
+		quota  *v1.ResourceQuota
+		result port.QuotaApplicationResult
+		kept   bool
+	}{
+		"created by onyxia": {quota: existing(onyxia, nil), result: port.QuotaDeleted},
+		"ignored": {
+			quota:  existing(onyxia, map[string]string{IgnoreQuotaAnnotation: "true"}),
+			result: port.QuotaIgnored,
+			kept:   true,
+		},
+		"created by someone else": {quota: existing(nil, nil), result: port.QuotaUnchanged, kept: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clientset := fake.NewClientset(tc.quota)
+			service := NewKubernetesNamespaceService(clientset)
+
+			result, err := service.ApplyResourceQuotas(
+				context.Background(),
+				"test-namespace",
+				&domain.Quota{},
+			)
 
-	// Simulate a quota that causes conversion failure
-	quota := &domain.Quota{
-		MemoryRequest: "invalid", // ❌ This will fail conversion
+			assert.NoError(t, err)
+			assert.Equal(t, tc.result, result)
+			_, err = clientset.CoreV1().
+				ResourceQuotas("test-namespace").
+				Get(context.Background(), QuotaName, metav1.GetOptions{})
+			assert.Equal(t, tc.kept, err == nil)
+		})
 	}
+}
 
-	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
+// ❌ Test: Failure When Deleting a Quota
+func TestApplyResourceQuotasFailureDelete(t *testing.T) {
+	clientset := fake.NewClientset(&v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QuotaName,
+			Namespace: "test-namespace",
+			Labels:    map[string]string{"created-by": "onyxia"},
+		},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor(
+		"delete",
+		"resourcequotas",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to delete quota")
+		},
+	)
+
+	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", &domain.Quota{})
 
 	assert.Error(t, err)
 	assert.Equal(t, port.QuotaApplicationResult(""), result)
-	assert.Contains(t, err.Error(), "error converting quota to ResourceQuota")
+	assert.Contains(t, err.Error(), "failed to delete quota")
 }
 
 func TestApplyResourceQuotasLabelOnCreate(t *testing.T) {
 	clientset := fake.NewClientset()
 	service := NewKubernetesNamespaceService(clientset)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"}
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")}
 
 	clientset.PrependReactor("create", "resourcequotas",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
@@ -412,7 +477,7 @@ func TestApplyResourceQuotasLabelOnUpdate(t *testing.T) {
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"}
+	quota := &domain.Quota{"requests.memory": resource.MustParse("10Gi")}
 	_, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
 	assert.NoError(t, err)
 }
@@ -461,15 +526,3 @@ func TestQuotasAreDifferentExtraKeyInExistingQuota(t *testing.T) {
 	result := quotasAreDifferent(existing, newQuota)
 	assert.True(t, result, "Expected quotas to be different due to missing key in new quota")
 }
-
-func TestConvertQuotaToResourceMapInvalidQuantity(t *testing.T) {
-	quota := domain.Quota{
-		MemoryRequest: "invalid-quantity",
-	}
-
-	result, err := convertQuotaToResourceMap(quota)
-
-	assert.Nil(t, result)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "quantities must match")
-}
@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseNetworkPolicySpec decodes the rendered spec of a policy, rejecting
+// unknown fields.
+func ParseNetworkPolicySpec(policy *domain.NetworkPolicy) (networkingv1.NetworkPolicySpec, error) {
+	var spec networkingv1.NetworkPolicySpec
+	if err := yaml.UnmarshalStrict([]byte(policy.Spec), &spec); err != nil {
+		return networkingv1.NetworkPolicySpec{}, fmt.Errorf(
+			"invalid spec of network policy %q: %w", policy.Name, err,
+		)
+	}
+	return spec, nil
+}
+
+func (s *KubernetesNamespaceService) ApplyNetworkPolicy(
+	ctx context.Context,
+	namespace string,
+	policy *domain.NetworkPolicy,
+) (port.NetworkPolicyApplicationResult, error) {
+	policiesClient := s.clientset.NetworkingV1().NetworkPolicies(namespace)
+
+	spec, err := ParseNetworkPolicySpec(policy)
+	if err != nil {
+		return "", err
+	}
+	defaultNetworkPolicySpec(&spec)
+
+	desired := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policy.Name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"created-by": "onyxia",
+			},
+		},
+		Spec: spec,
+	}
+
+	existing, err := policiesClient.Get(ctx, policy.Name, metav1.GetOptions{})
+
+	if err == nil {
+		if ignore, ok := existing.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
+			return port.NetworkPolicyIgnored, nil
+		}
+
+		if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+			return port.NetworkPolicyUnchanged, nil
+		}
+
+		existing.Spec = desired.Spec
+		if _, err := policiesClient.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to update network policy %q: %w", policy.Name, err)
+		}
+		return port.NetworkPolicyUpdated, nil
+	}
+
+	if errors.IsNotFound(err) {
+		if _, err := policiesClient.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create network policy %q: %w", policy.Name, err)
+		}
+		return port.NetworkPolicyCreated, nil
+	}
+
+	return "", fmt.Errorf(
+		"unexpected error checking for existing network policy %q: %w",
+		policy.Name,
+		err,
+	)
+}
+
+func (s *KubernetesNamespaceService) PruneNetworkPolicies(
+	ctx context.Context,
+	namespace string,
+	keep []string,
+) ([]string, error) {
+	policiesClient := s.clientset.NetworkingV1().NetworkPolicies(namespace)
+
+	policies, err := policiesClient.List(ctx, metav1.ListOptions{LabelSelector: "created-by=onyxia"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network policies: %w", err)
+	}
+
+	var deleted []string
+	for _, policy := range policies.Items {
+		if slices.Contains(keep, policy.Name) {
+			continue
+		}
+		if ignore, ok := policy.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
+			continue
+		}
+		if err := policiesClient.Delete(ctx, policy.Name, metav1.DeleteOptions{}); err != nil &&
+			!errors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete network policy %q: %w", policy.Name, err)
+		}
+		deleted = append(deleted, policy.Name)
+	}
+	return deleted, nil
+}
+
+// defaultNetworkPolicySpec sets the defaults the API server sets, so that
+// specs read back compare equal to the rendered ones.
+func defaultNetworkPolicySpec(spec *networkingv1.NetworkPolicySpec) {
+	if len(spec.PolicyTypes) == 0 {
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		if len(spec.Egress) > 0 {
+			spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		}
+	}
+
+	tcp := v1.ProtocolTCP
+	for i := range spec.Ingress {
+		for j := range spec.Ingress[i].Ports {
+			if spec.Ingress[i].Ports[j].Protocol == nil {
+				spec.Ingress[i].Ports[j].Protocol = &tcp
+			}
+		}
+	}
+	for i := range spec.Egress {
+		for j := range spec.Egress[i].Ports {
+			if spec.Egress[i].Ports[j].Protocol == nil {
+				spec.Egress[i].Ports[j].Protocol = &tcp
+			}
+		}
+	}
+}
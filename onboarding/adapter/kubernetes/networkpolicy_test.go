@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var denyPolicy = &domain.NetworkPolicy{
+	Name: "deny-by-default",
+	Spec: `
+podSelector: {}
+ingress:
+  - from:
+      - podSelector: {}
+    ports:
+      - port: 8080
+`,
+}
+
+// ✅ Test: Create Network Policy Successfully
+func TestApplyNetworkPolicyCreated(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", denyPolicy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.NetworkPolicyCreated, result)
+
+	np, err := clientset.NetworkingV1().
+		NetworkPolicies("test-namespace").
+		Get(context.Background(), "deny-by-default", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, np.Spec.PolicyTypes)
+	assert.Equal(t, v1.ProtocolTCP, *np.Spec.Ingress[0].Ports[0].Protocol)
+	assert.Equal(t, "onyxia", np.Labels["created-by"])
+}
+
+// ✅ Test: Applying Twice Is Idempotent
+func TestApplyNetworkPolicyUnchanged(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	_, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", denyPolicy)
+	require.NoError(t, err)
+	result, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", denyPolicy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.NetworkPolicyUnchanged, result)
+}
+
+// ✅ Test: Network Policy Updated
+func TestApplyNetworkPolicyUpdated(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+	_, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", denyPolicy)
+	require.NoError(t, err)
+
+	denyAll := &domain.NetworkPolicy{Name: "deny-by-default", Spec: "podSelector: {}\n"}
+	result, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", denyAll)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.NetworkPolicyUpdated, result)
+}
+
+// ✅ Test: Network Policy Ignored Due To Annotation
+func TestApplyNetworkPolicyIgnored(t *testing.T) {
+	clientset := fake.NewClientset(&networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "deny-by-default",
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{IgnoreQuotaAnnotation: "true"},
+		},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", denyPolicy)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.NetworkPolicyIgnored, result)
+}
+
+// ❌ Test: Invalid Spec
+func TestApplyNetworkPolicyInvalidSpec(t *testing.T) {
+	service := NewKubernetesNamespaceService(fake.NewClientset())
+
+	_, err := service.ApplyNetworkPolicy(context.Background(), "test-namespace", &domain.NetworkPolicy{
+		Name: "broken",
+		Spec: "podSelectr: {}\n",
+	})
+
+	assert.ErrorContains(t, err, `invalid spec of network policy "broken"`)
+}
+
+// ✅ Test: Prune Deletes Only Stale Onyxia Policies
+func TestPruneNetworkPolicies(t *testing.T) {
+	policy := func(name string, labels, annotations map[string]string) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test-namespace",
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+	onyxia := map[string]string{"created-by": "onyxia"}
+	clientset := fake.NewClientset(
+		policy("deny-by-default", onyxia, nil),
+		policy("allow-removed", onyxia, nil),
+		policy("pinned", onyxia, map[string]string{IgnoreQuotaAnnotation: "true"}),
+		policy("user-made", nil, nil),
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	deleted, err := service.PruneNetworkPolicies(
+		context.Background(),
+		"test-namespace",
+		[]string{"deny-by-default"},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"allow-removed"}, deleted)
+	remaining, err := clientset.NetworkingV1().
+		NetworkPolicies("test-namespace").
+		List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	names := make([]string, 0, len(remaining.Items))
+	for _, p := range remaining.Items {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"deny-by-default", "pinned", "user-made"}, names)
+}
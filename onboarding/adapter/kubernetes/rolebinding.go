@@ -0,0 +1,82 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const RoleBindingName string = "onyxia-rolebinding"
+
+func (s *KubernetesNamespaceService) ApplyRoleBinding(
+	ctx context.Context,
+	namespace string,
+	binding *domain.RoleBinding,
+) (port.RoleBindingApplicationResult, error) {
+	bindingsClient := s.clientset.RbacV1().RoleBindings(namespace)
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RoleBindingName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"created-by": "onyxia",
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     binding.ClusterRole,
+		},
+		Subjects: []rbacv1.Subject{{
+			APIGroup: rbacv1.GroupName,
+			Kind:     string(binding.SubjectKind),
+			Name:     binding.SubjectName,
+		}},
+	}
+
+	existing, err := bindingsClient.Get(ctx, RoleBindingName, metav1.GetOptions{})
+
+	if err == nil {
+		if ignore, ok := existing.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
+			return port.RoleBindingIgnored, nil
+		}
+
+		if equality.Semantic.DeepEqual(existing.RoleRef, roleBinding.RoleRef) {
+			if equality.Semantic.DeepEqual(existing.Subjects, roleBinding.Subjects) {
+				return port.RoleBindingUnchanged, nil
+			}
+
+			existing.Subjects = roleBinding.Subjects
+			if _, err := bindingsClient.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+				return "", fmt.Errorf("failed to update role binding: %w", err)
+			}
+			return port.RoleBindingUpdated, nil
+		}
+
+		// The role of a binding is immutable: replace the binding.
+		if err := bindingsClient.Delete(ctx, RoleBindingName, metav1.DeleteOptions{}); err != nil &&
+			!errors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to delete role binding: %w", err)
+		}
+		if _, err := bindingsClient.Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to recreate role binding: %w", err)
+		}
+		return port.RoleBindingUpdated, nil
+	}
+
+	if errors.IsNotFound(err) {
+		if _, err := bindingsClient.Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create role binding: %w", err)
+		}
+		return port.RoleBindingCreated, nil
+	}
+
+	return "", fmt.Errorf("unexpected error checking for existing role binding: %w", err)
+}
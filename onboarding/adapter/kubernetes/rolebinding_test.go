@@ -0,0 +1,138 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var userBinding = &domain.RoleBinding{
+	ClusterRole: "admin",
+	SubjectKind: domain.SubjectUser,
+	SubjectName: "oidc:alice",
+}
+
+func getRoleBinding(t *testing.T, clientset *fake.Clientset) *rbacv1.RoleBinding {
+	t.Helper()
+	rb, err := clientset.RbacV1().
+		RoleBindings("test-namespace").
+		Get(context.Background(), RoleBindingName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return rb
+}
+
+// ✅ Test: Create Role Binding Successfully
+func TestApplyRoleBindingCreated(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.RoleBindingCreated, result)
+
+	rb := getRoleBinding(t, clientset)
+	assert.Equal(t, "admin", rb.RoleRef.Name)
+	assert.Equal(t, "ClusterRole", rb.RoleRef.Kind)
+	assert.Equal(t, []rbacv1.Subject{{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "User",
+		Name:     "oidc:alice",
+	}}, rb.Subjects)
+	assert.Equal(t, "onyxia", rb.Labels["created-by"])
+}
+
+// ✅ Test: Applying Twice Is Idempotent
+func TestApplyRoleBindingUnchanged(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	_, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+	require.NoError(t, err)
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.RoleBindingUnchanged, result)
+}
+
+// ✅ Test: Subjects Are Updated In Place
+func TestApplyRoleBindingSubjectUpdated(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+	_, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+	require.NoError(t, err)
+
+	group := &domain.RoleBinding{
+		ClusterRole: "admin",
+		SubjectKind: domain.SubjectGroup,
+		SubjectName: "oidc:team",
+	}
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", group)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.RoleBindingUpdated, result)
+	assert.Equal(t, "Group", getRoleBinding(t, clientset).Subjects[0].Kind)
+}
+
+// ✅ Test: A Changed ClusterRole Replaces The Binding
+func TestApplyRoleBindingRoleChanged(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+	_, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+	require.NoError(t, err)
+
+	edit := *userBinding
+	edit.ClusterRole = "edit"
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", &edit)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.RoleBindingUpdated, result)
+	assert.Equal(t, "edit", getRoleBinding(t, clientset).RoleRef.Name)
+}
+
+// ✅ Test: Role Binding Ignored Due To Annotation
+func TestApplyRoleBindingIgnored(t *testing.T) {
+	clientset := fake.NewClientset(&rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        RoleBindingName,
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{IgnoreQuotaAnnotation: "true"},
+		},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+
+	assert.NoError(t, err)
+	assert.Equal(t, port.RoleBindingIgnored, result)
+	assert.Equal(t, "view", getRoleBinding(t, clientset).RoleRef.Name)
+}
+
+// ❌ Test: Failure When Creating Role Binding
+func TestApplyRoleBindingFailureCreate(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor(
+		"create",
+		"rolebindings",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("create error")
+		},
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", userBinding)
+
+	assert.ErrorContains(t, err, "failed to create role binding")
+	assert.Empty(t, result)
+}
@@ -1,27 +1,39 @@
 package route
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/adapter/kubernetes"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/api/controller"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/bootstrap"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/usecase"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func SetupOnboardingController(
 	app *bootstrap.Application,
-) *controller.OnboardingController {
+) (*controller.OnboardingController, error) {
+	networkPolicies, err := convertBootstrapNetworkPoliciesToDomain(app.Env.Onboarding.NetworkPolicies)
+	if err != nil {
+		return nil, err
+	}
+
 	namespaceCreator := kubernetes.NewKubernetesNamespaceService(app.K8sClient.Clientset())
 
-	envQuotas := app.Env.Onboarding.Quotas
+	quotas, err := convertBootstrapQuotasToDomain(app.Env.Onboarding.Quotas)
+	if err != nil {
+		return nil, err
+	}
 
-	rolesDomainQuotas := func() map[string]domain.Quota {
-		result := make(map[string]domain.Quota)
-		for key, q := range envQuotas.Roles {
-			result[key] = convertBootstrapQuotaToDomain(q)
-		}
-		return result
-	}()
+	limitRanges, err := convertBootstrapLimitRangesToDomain(app.Env.Onboarding.LimitRange)
+	if err != nil {
+		return nil, err
+	}
 
 	onboardingUsecase := usecase.NewOnboardingUsecase(
 		namespaceCreator,
@@ -38,33 +50,226 @@ func SetupOnboardingController(
 				}(app.Env.Onboarding.Annotation.Dynamic),
 			},
 		},
-		domain.Quotas{
-			Enabled:      envQuotas.Enabled,
-			Default:      convertBootstrapQuotaToDomain(envQuotas.Default),
-			UserEnabled:  envQuotas.UserEnabled,
-			User:         convertBootstrapQuotaToDomain(envQuotas.User),
-			Roles:        rolesDomainQuotas,
-			GroupEnabled: envQuotas.GroupEnabled,
-			Group:        convertBootstrapQuotaToDomain(envQuotas.Group),
-		},
+		quotas,
+		limitRanges,
+		networkPolicies,
+		domain.RoleBindings(app.Env.Onboarding.RoleBinding),
 		app.UserContextReader,
 	)
 
-	return controller.NewOnboardingController(onboardingUsecase, app.UserContextReader)
+	return controller.NewOnboardingController(onboardingUsecase, app.UserContextReader), nil
+
+}
+
+func convertBootstrapQuotasToDomain(q bootstrap.Quotas) (domain.Quotas, error) {
+	quotas := domain.Quotas{
+		Enabled:      q.Enabled,
+		UserEnabled:  q.UserEnabled,
+		GroupEnabled: q.GroupEnabled,
+		Roles:        make(map[string]domain.Quota, len(q.Roles)),
+		RoleStrategy: domain.RoleStrategy(q.RoleStrategy),
+		RolePriority: q.RolePriority,
+	}
+
+	switch quotas.RoleStrategy {
+	case "":
+		quotas.RoleStrategy = domain.RoleStrategyFirst
+	case domain.RoleStrategyFirst, domain.RoleStrategyPriority, domain.RoleStrategyMax:
+	default:
+		return domain.Quotas{}, fmt.Errorf(
+			"invalid quota role strategy %q, expected first, priority or max",
+			q.RoleStrategy,
+		)
+	}
+
+	var err error
+	if quotas.Default, err = convertBootstrapQuotaToDomain(q.Default); err != nil {
+		return domain.Quotas{}, fmt.Errorf("default quota: %w", err)
+	}
+	if quotas.User, err = convertBootstrapQuotaToDomain(q.User); err != nil {
+		return domain.Quotas{}, fmt.Errorf("user quota: %w", err)
+	}
+	if quotas.Group, err = convertBootstrapQuotaToDomain(q.Group); err != nil {
+		return domain.Quotas{}, fmt.Errorf("group quota: %w", err)
+	}
+	for i, g := range q.Groups {
+		group, err := convertBootstrapGroupQuotaToDomain(g)
+		if err != nil {
+			return domain.Quotas{}, fmt.Errorf("group quota %d: %w", i, err)
+		}
+		quotas.Groups = append(quotas.Groups, group)
+	}
+	for i, c := range q.Claims {
+		claim, err := convertBootstrapClaimQuotaToDomain(c)
+		if err != nil {
+			return domain.Quotas{}, fmt.Errorf("claim quota %d: %w", i, err)
+		}
+		quotas.Claims = append(quotas.Claims, claim)
+	}
+	for role, rq := range q.Roles {
+		if quotas.Roles[role], err = convertBootstrapQuotaToDomain(rq); err != nil {
+			return domain.Quotas{}, fmt.Errorf("quota of role %q: %w", role, err)
+		}
+	}
+
+	if quotas.RoleStrategy == domain.RoleStrategyPriority && len(quotas.RolePriority) == 0 {
+		return domain.Quotas{}, errors.New("quota role strategy priority requires rolePriority")
+	}
+	for _, role := range quotas.RolePriority {
+		if _, ok := quotas.Roles[role]; !ok {
+			return domain.Quotas{}, fmt.Errorf("rolePriority: %q is not a role of roles", role)
+		}
+	}
+	return quotas, nil
+}
+
+func convertBootstrapGroupQuotaToDomain(g bootstrap.GroupQuota) (domain.GroupQuota, error) {
+	if (g.Name == "") == (g.Match == "") {
+		return domain.GroupQuota{}, errors.New("exactly one of name and match must be set")
+	}
+
+	group := domain.GroupQuota{Name: g.Name}
+	if g.Match != "" {
+		match, err := regexp.Compile(g.Match)
+		if err != nil {
+			return domain.GroupQuota{}, fmt.Errorf("invalid match: %w", err)
+		}
+		group.Match = match
+	}
+
+	quota, err := convertBootstrapQuotaToDomain(g.Quota)
+	if err != nil {
+		return domain.GroupQuota{}, err
+	}
+	group.Quota = quota
+	return group, nil
+}
+
+func convertBootstrapClaimQuotaToDomain(c bootstrap.ClaimQuota) (domain.ClaimQuota, error) {
+	if c.Attribute == "" || c.Resource == "" {
+		return domain.ClaimQuota{}, errors.New("attribute and resource are required")
+	}
+
+	claim := domain.ClaimQuota{Attribute: c.Attribute, Resource: c.Resource}
+	for _, bound := range []struct {
+		value string
+		dest  **resource.Quantity
+	}{
+		{c.Min, &claim.Min},
+		{c.Max, &claim.Max},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(bound.value)
+		if err != nil {
+			return domain.ClaimQuota{}, fmt.Errorf("invalid bound %q: %w", bound.value, err)
+		}
+		*bound.dest = &quantity
+	}
+	if claim.Min != nil && claim.Max != nil && claim.Min.Cmp(*claim.Max) > 0 {
+		return domain.ClaimQuota{}, fmt.Errorf("min %s is greater than max %s", c.Min, c.Max)
+	}
+	return claim, nil
+}
+
+func convertBootstrapQuotaToDomain(q bootstrap.Quota) (domain.Quota, error) {
+	return domain.ParseQuota(q)
+}
 
+func convertBootstrapLimitRangesToDomain(l bootstrap.LimitRanges) (domain.LimitRanges, error) {
+	limitRanges := domain.LimitRanges{
+		Enabled:      l.Enabled,
+		UserEnabled:  l.UserEnabled,
+		GroupEnabled: l.GroupEnabled,
+		Roles:        make(map[string]domain.LimitRange, len(l.Roles)),
+	}
+
+	var err error
+	if limitRanges.Default, err = convertBootstrapLimitRangeToDomain(l.Default); err != nil {
+		return domain.LimitRanges{}, fmt.Errorf("default limit range: %w", err)
+	}
+	if limitRanges.User, err = convertBootstrapLimitRangeToDomain(l.User); err != nil {
+		return domain.LimitRanges{}, fmt.Errorf("user limit range: %w", err)
+	}
+	if limitRanges.Group, err = convertBootstrapLimitRangeToDomain(l.Group); err != nil {
+		return domain.LimitRanges{}, fmt.Errorf("group limit range: %w", err)
+	}
+	for role, r := range l.Roles {
+		if limitRanges.Roles[role], err = convertBootstrapLimitRangeToDomain(r); err != nil {
+			return domain.LimitRanges{}, fmt.Errorf("limit range of role %q: %w", role, err)
+		}
+	}
+	return limitRanges, nil
 }
 
-func convertBootstrapQuotaToDomain(q bootstrap.Quota) domain.Quota {
-	return domain.Quota{
-		MemoryRequest:           q.RequestsMemory,
-		CPURequest:              q.RequestsCPU,
-		MemoryLimit:             q.LimitsMemory,
-		CPULimit:                q.LimitsCPU,
-		StorageRequest:          q.RequestsStorage,
-		MaxPods:                 q.CountPods,
-		EphemeralStorageRequest: q.RequestsEphemeralStorage,
-		EphemeralStorageLimit:   q.LimitsEphemeralStorage,
-		GPURequest:              q.RequestsGPU,
-		GPULimit:                q.LimitsGPU,
+func convertBootstrapLimitRangeToDomain(l bootstrap.LimitRange) (domain.LimitRange, error) {
+	var limitRange domain.LimitRange
+	for _, field := range []struct {
+		name   string
+		values map[string]string
+		dest   *map[string]resource.Quantity
+	}{
+		{"defaultLimit", l.DefaultLimit, &limitRange.DefaultLimit},
+		{"defaultRequest", l.DefaultRequest, &limitRange.DefaultRequest},
+		{"max", l.Max, &limitRange.Max},
+		{"min", l.Min, &limitRange.Min},
+	} {
+		quantities, err := domain.ParseQuota(field.values)
+		if err != nil {
+			return domain.LimitRange{}, fmt.Errorf("%s: %w", field.name, err)
+		}
+		if len(quantities) > 0 {
+			*field.dest = quantities
+		}
+	}
+	return limitRange, nil
+}
+
+func convertBootstrapNetworkPoliciesToDomain(
+	n bootstrap.NetworkPolicies,
+) (domain.NetworkPolicies, error) {
+	templates := make([]domain.NetworkPolicyTemplate, 0, len(n.Templates))
+	seen := make(map[string]struct{}, len(n.Templates))
+	for _, t := range n.Templates {
+		if _, dup := seen[t.Name]; dup {
+			return domain.NetworkPolicies{}, fmt.Errorf("duplicate network policy %q", t.Name)
+		}
+		if errs := validation.IsDNS1123Subdomain(t.Name); len(errs) > 0 {
+			return domain.NetworkPolicies{}, fmt.Errorf(
+				"invalid network policy name %q: %s", t.Name, strings.Join(errs, ", "),
+			)
+		}
+		seen[t.Name] = struct{}{}
+
+		tmpl, err := domain.NewNetworkPolicyTemplate(t.Name, t.Spec)
+		if err != nil {
+			return domain.NetworkPolicies{}, err
+		}
+		if err := checkNetworkPolicyTemplate(tmpl); err != nil {
+			return domain.NetworkPolicies{}, err
+		}
+		templates = append(templates, tmpl)
+	}
+	return domain.NetworkPolicies{Enabled: n.Enabled, Templates: templates}, nil
+}
+
+// networkPolicySamples are rendered at startup, so that templates that do not
+// produce a valid spec are reported before the first login.
+var networkPolicySamples = []domain.NetworkPolicyData{
+	{Namespace: "user-jdoe", User: "jdoe"},
+	{Namespace: "projet-team", User: "jdoe", Group: "team"},
+}
+
+func checkNetworkPolicyTemplate(tmpl domain.NetworkPolicyTemplate) error {
+	for _, data := range networkPolicySamples {
+		policy, err := tmpl.Render(data)
+		if err != nil {
+			return err
+		}
+		if _, err := kubernetes.ParseNetworkPolicySpec(policy); err != nil {
+			return err
+		}
 	}
+	return nil
 }
@@ -6,36 +6,202 @@ import (
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/bootstrap"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestConvertBootstrapQuotaToDomain(t *testing.T) {
 	bootstrapQuota := bootstrap.Quota{
-		RequestsMemory:           "512Mi",
-		RequestsCPU:              "250m",
-		LimitsMemory:             "1Gi",
-		LimitsCPU:                "500m",
-		RequestsStorage:          "10Gi",
-		CountPods:                "20",
-		RequestsEphemeralStorage: "5Gi",
-		LimitsEphemeralStorage:   "10Gi",
-		RequestsGPU:              "1",
-		LimitsGPU:                "2",
+		"requests.memory":              "512Mi",
+		"limits.cpu":                   "500m",
+		"count/pods":                   "20",
+		"requests.nvidia.com/gpu":      "1",
+		"requests.amd.com/gpu":         "2",
+		"count/services.loadbalancers": "0",
+		"persistentvolumeclaims":       "5",
+		"fast-ssd.storageclass.storage.k8s.io/requests.storage": "50Gi",
+		"limits.memory": "",
 	}
 
-	expectedDomainQuota := domain.Quota{
-		MemoryRequest:           bootstrapQuota.RequestsMemory,
-		CPURequest:              bootstrapQuota.RequestsCPU,
-		MemoryLimit:             bootstrapQuota.LimitsMemory,
-		CPULimit:                bootstrapQuota.LimitsCPU,
-		StorageRequest:          bootstrapQuota.RequestsStorage,
-		MaxPods:                 bootstrapQuota.CountPods,
-		EphemeralStorageRequest: bootstrapQuota.RequestsEphemeralStorage,
-		EphemeralStorageLimit:   bootstrapQuota.LimitsEphemeralStorage,
-		GPURequest:              bootstrapQuota.RequestsGPU,
-		GPULimit:                bootstrapQuota.LimitsGPU,
+	result, err := convertBootstrapQuotaToDomain(bootstrapQuota)
+
+	require.NoError(t, err)
+	assert.Len(t, result, 8, "Empty values should be skipped")
+	for name, value := range bootstrapQuota {
+		if value == "" {
+			continue
+		}
+		quantity := result[name]
+		assert.True(t, resource.MustParse(value).Equal(quantity), name)
 	}
+}
+
+func TestConvertBootstrapQuotasToDomainInvalidQuantity(t *testing.T) {
+	_, err := convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Roles: map[string]bootstrap.Quota{"admin": {"requests.memory": "lots"}},
+	})
+
+	assert.ErrorContains(t, err, `quota of role "admin"`)
+	assert.ErrorContains(t, err, "requests.memory")
+}
+
+func TestConvertBootstrapLimitRangesToDomain(t *testing.T) {
+	limitRanges, err := convertBootstrapLimitRangesToDomain(bootstrap.LimitRanges{
+		Enabled: true,
+		Default: bootstrap.LimitRange{
+			DefaultLimit: map[string]string{"cpu": "1", "memory": ""},
+			Max:          map[string]string{"memory": "8Gi"},
+		},
+		Roles: map[string]bootstrap.LimitRange{"admin": {Min: map[string]string{"cpu": "100m"}}},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, limitRanges.Enabled)
+	assert.True(t, resource.MustParse("1").Equal(limitRanges.Default.DefaultLimit["cpu"]))
+	assert.Len(t, limitRanges.Default.DefaultLimit, 1, "Empty values should be skipped")
+	assert.True(t, resource.MustParse("8Gi").Equal(limitRanges.Default.Max["memory"]))
+	assert.Nil(t, limitRanges.Default.Min, "Unset fields should stay nil")
+	assert.True(t, limitRanges.User.IsEmpty())
+	assert.True(t, resource.MustParse("100m").Equal(limitRanges.Roles["admin"].Min["cpu"]))
+}
+
+func TestConvertBootstrapLimitRangesToDomainInvalidQuantity(t *testing.T) {
+	_, err := convertBootstrapLimitRangesToDomain(bootstrap.LimitRanges{
+		Roles: map[string]bootstrap.LimitRange{"admin": {Max: map[string]string{"cpu": "lots"}}},
+	})
+
+	assert.ErrorContains(t, err, `limit range of role "admin": max`)
+	assert.ErrorContains(t, err, "cpu")
+}
+
+func TestConvertBootstrapNetworkPoliciesToDomain(t *testing.T) {
+	policies, err := convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Enabled: true,
+		Templates: []bootstrap.NetworkPolicyTemplate{
+			{Name: "deny-by-default", Spec: "podSelector: {}"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, policies.Enabled)
+	assert.Len(t, policies.Templates, 1)
+
+	_, err = convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Templates: []bootstrap.NetworkPolicyTemplate{{Name: "broken", Spec: "{{ .Namespace"}},
+	})
+	assert.ErrorContains(t, err, `network policy "broken"`)
+
+	_, err = convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Templates: []bootstrap.NetworkPolicyTemplate{
+			{Name: "same", Spec: "podSelector: {}"},
+			{Name: "same", Spec: "podSelector: {}"},
+		},
+	})
+	assert.ErrorContains(t, err, "duplicate")
+
+	for _, name := range []string{"", "Deny_All", "-deny"} {
+		_, err = convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+			Templates: []bootstrap.NetworkPolicyTemplate{{Name: name, Spec: "podSelector: {}"}},
+		})
+		assert.ErrorContains(t, err, "invalid network policy name", name)
+	}
+
+	_, err = convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Templates: []bootstrap.NetworkPolicyTemplate{
+			{Name: "unquoted", Spec: "podSelector:\n  matchLabels:\n    owner: {{ .User }}"},
+		},
+	})
+	assert.ErrorContains(t, err, "must be piped through quote")
+
+	_, err = convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Templates: []bootstrap.NetworkPolicyTemplate{
+			{Name: "typo", Spec: "podSelectr: {}"},
+		},
+	})
+	assert.ErrorContains(t, err, `invalid spec of network policy "typo"`)
+
+	_, err = convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Templates: []bootstrap.NetworkPolicyTemplate{
+			{Name: "missing", Spec: "podSelector:\n  matchLabels:\n    owner: {{ .Owner | quote }}"},
+		},
+	})
+	assert.ErrorContains(t, err, `network policy "missing"`)
+}
+
+func TestConvertBootstrapQuotasToDomainStrategyAndGroups(t *testing.T) {
+	quotas, err := convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Groups: []bootstrap.GroupQuota{
+			{Name: "team-ml", Quota: bootstrap.Quota{"requests.memory": "64Gi"}},
+			{Match: "^team-", Quota: bootstrap.Quota{"requests.memory": "32Gi"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleStrategyFirst, quotas.RoleStrategy, "Defaults to the first strategy")
+	require.Len(t, quotas.Groups, 2)
+	assert.True(t, quotas.Groups[1].Matches("team-web"))
+
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{RoleStrategy: "min"})
+	assert.ErrorContains(t, err, "invalid quota role strategy")
+
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Groups: []bootstrap.GroupQuota{{Name: "a", Match: "^a$"}},
+	})
+	assert.ErrorContains(t, err, "exactly one of name and match")
+
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Groups: []bootstrap.GroupQuota{{Match: "("}},
+	})
+	assert.ErrorContains(t, err, "invalid match")
+}
+
+func TestConvertBootstrapQuotasToDomainRolePriority(t *testing.T) {
+	roles := map[string]bootstrap.Quota{
+		"admin":    {"requests.memory": "16Gi"},
+		"gpu-user": {"requests.memory": "8Gi"},
+	}
+
+	quotas, err := convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Roles:        roles,
+		RoleStrategy: "priority",
+		RolePriority: []string{"admin", "gpu-user"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "gpu-user"}, quotas.RolePriority)
+
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{Roles: roles, RoleStrategy: "priority"})
+	assert.ErrorContains(t, err, "requires rolePriority")
+
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Roles:        roles,
+		RoleStrategy: "priority",
+		RolePriority: []string{"admin", "Admins"},
+	})
+	assert.ErrorContains(t, err, `"Admins" is not a role of roles`)
+}
+
+func TestConvertBootstrapQuotasToDomainClaims(t *testing.T) {
+	quotas, err := convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Claims: []bootstrap.ClaimQuota{
+			{Attribute: "quota_cpu", Resource: "requests.cpu", Min: "1", Max: "8"},
+			{Attribute: "quota_memory", Resource: "requests.memory"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, quotas.Claims, 2)
+	assert.Equal(t, "8", quotas.Claims[0].Max.String())
+	assert.Nil(t, quotas.Claims[1].Min)
+
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Claims: []bootstrap.ClaimQuota{{Attribute: "quota_cpu"}},
+	})
+	assert.ErrorContains(t, err, "attribute and resource are required")
 
-	result := convertBootstrapQuotaToDomain(bootstrapQuota)
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Claims: []bootstrap.ClaimQuota{{Attribute: "a", Resource: "requests.cpu", Min: "x"}},
+	})
+	assert.ErrorContains(t, err, "invalid bound")
 
-	assert.Equal(t, expectedDomainQuota, result, "Quota conversion should correctly map all fields")
+	_, err = convertBootstrapQuotasToDomain(bootstrap.Quotas{
+		Claims: []bootstrap.ClaimQuota{{Attribute: "a", Resource: "requests.cpu", Min: "8", Max: "1"}},
+	})
+	assert.ErrorContains(t, err, "greater than max")
 }
@@ -23,7 +23,11 @@ func Setup(ctx context.Context, app *bootstrap.Application) (http.Handler, error
 		return nil, fmt.Errorf("failed to initialize OIDC middleware: %w", err)
 	}
 
-	onboardingController := SetupOnboardingController(app)
+	onboardingController, err := SetupOnboardingController(app)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup onboarding controller: %w", err)
+	}
 
 	handler := NewHandler(onboardingController)
 
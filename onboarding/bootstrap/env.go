@@ -26,17 +26,25 @@ type Security struct {
 	CORSAllowedOrigins []string `mapstructure:"corsAllowedOrigins" json:"corsAllowedOrigins"`
 }
 
-type Quota struct {
-	RequestsMemory           string `mapstructure:"requests.memory"            json:"requests.memory"`
-	RequestsCPU              string `mapstructure:"requests.cpu"               json:"requests.cpu"`
-	LimitsMemory             string `mapstructure:"limits.memory"              json:"limits.memory"`
-	LimitsCPU                string `mapstructure:"limits.cpu"                 json:"limits.cpu"`
-	RequestsStorage          string `mapstructure:"requests.storage"           json:"requests.storage"`
-	CountPods                string `mapstructure:"count/pods"                 json:"count/pods"`
-	RequestsEphemeralStorage string `mapstructure:"requests.ephemeral-storage" json:"requests.ephemeral-storage"`
-	LimitsEphemeralStorage   string `mapstructure:"limits.ephemeral-storage"   json:"limits.ephemeral-storage"`
-	RequestsGPU              string `mapstructure:"requests.nvidia.com/gpu"    json:"requests.nvidia.com/gpu"`
-	LimitsGPU                string `mapstructure:"limits.nvidia.com/gpu"      json:"limits.nvidia.com/gpu"`
+// Quota maps ResourceQuota resource names, such as "requests.memory",
+// "count/pods" or "requests.nvidia.com/gpu", to quantities.
+type Quota map[string]string
+
+// GroupQuota applies to the group namespaces whose group is Name, or matches
+// the Match regex.
+type GroupQuota struct {
+	Name  string `mapstructure:"name"  json:"name"`
+	Match string `mapstructure:"match" json:"match"`
+	Quota Quota  `mapstructure:"quota" json:"quota"`
+}
+
+// ClaimQuota sets Resource of user quotas from the Attribute claim, bounded by
+// Min and Max when set.
+type ClaimQuota struct {
+	Attribute string `mapstructure:"attribute" json:"attribute"`
+	Resource  string `mapstructure:"resource"  json:"resource"`
+	Min       string `mapstructure:"min"       json:"min"`
+	Max       string `mapstructure:"max"       json:"max"`
 }
 
 type Quotas struct {
@@ -46,7 +54,48 @@ type Quotas struct {
 	User         Quota            `mapstructure:"user"         json:"user"`
 	GroupEnabled bool             `mapstructure:"groupEnabled" json:"groupEnabled"`
 	Group        Quota            `mapstructure:"group"        json:"group"`
+	Groups       []GroupQuota     `mapstructure:"groups"       json:"groups"`
 	Roles        map[string]Quota `mapstructure:"roles"        json:"roles"`
+	RoleStrategy string           `mapstructure:"roleStrategy" json:"roleStrategy"`
+	RolePriority []string         `mapstructure:"rolePriority" json:"rolePriority"`
+	Claims       []ClaimQuota     `mapstructure:"claims"       json:"claims"`
+}
+
+// LimitRange values map resource names to quantities.
+type LimitRange struct {
+	DefaultLimit   map[string]string `mapstructure:"defaultLimit"   json:"defaultLimit"`
+	DefaultRequest map[string]string `mapstructure:"defaultRequest" json:"defaultRequest"`
+	Max            map[string]string `mapstructure:"max"            json:"max"`
+	Min            map[string]string `mapstructure:"min"            json:"min"`
+}
+
+type LimitRanges struct {
+	Enabled      bool                  `mapstructure:"enabled"      json:"enabled"`
+	Default      LimitRange            `mapstructure:"default"      json:"default"`
+	UserEnabled  bool                  `mapstructure:"userEnabled"  json:"userEnabled"`
+	User         LimitRange            `mapstructure:"user"         json:"user"`
+	GroupEnabled bool                  `mapstructure:"groupEnabled" json:"groupEnabled"`
+	Group        LimitRange            `mapstructure:"group"        json:"group"`
+	Roles        map[string]LimitRange `mapstructure:"roles"        json:"roles"`
+}
+
+// NetworkPolicyTemplate holds the spec of a NetworkPolicy as a YAML
+// text/template, rendered with the namespace, user and group.
+type NetworkPolicyTemplate struct {
+	Name string `mapstructure:"name" json:"name"`
+	Spec string `mapstructure:"spec" json:"spec"`
+}
+
+type NetworkPolicies struct {
+	Enabled   bool                    `mapstructure:"enabled"   json:"enabled"`
+	Templates []NetworkPolicyTemplate `mapstructure:"templates" json:"templates"`
+}
+
+type RoleBinding struct {
+	Enabled        bool   `mapstructure:"enabled"        json:"enabled"`
+	ClusterRole    string `mapstructure:"clusterRole"    json:"clusterRole"`
+	UsernamePrefix string `mapstructure:"usernamePrefix" json:"usernamePrefix"`
+	GroupPrefix    string `mapstructure:"groupPrefix"    json:"groupPrefix"`
 }
 
 type Annotation struct {
@@ -63,6 +112,9 @@ type Onboarding struct {
 	GroupNamespacePrefix string            `mapstructure:"groupNamespacePrefix" json:"groupNamespacePrefix"`
 	Annotation           Annotation        `mapstructure:"annotations"          json:"annotations"`
 	Quotas               Quotas            `mapstructure:"quotas"               json:"quotas"`
+	LimitRange           LimitRanges       `mapstructure:"limitRange"           json:"limitRange"`
+	NetworkPolicies      NetworkPolicies   `mapstructure:"networkPolicies"      json:"networkPolicies"`
+	RoleBinding          RoleBinding       `mapstructure:"roleBinding"          json:"roleBinding"`
 }
 
 type Env struct {
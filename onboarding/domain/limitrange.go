@@ -0,0 +1,29 @@
+package domain
+
+import "k8s.io/apimachinery/pkg/api/resource"
+
+// LimitRange gives the containers of a namespace default requests and limits,
+// and bounds them. Each field maps a resource name, such as "cpu" or
+// "memory", to a quantity.
+type LimitRange struct {
+	DefaultLimit   map[string]resource.Quantity
+	DefaultRequest map[string]resource.Quantity
+	Max            map[string]resource.Quantity
+	Min            map[string]resource.Quantity
+}
+
+// IsEmpty reports whether l sets no value.
+func (l LimitRange) IsEmpty() bool {
+	return len(l.DefaultLimit) == 0 && len(l.DefaultRequest) == 0 &&
+		len(l.Max) == 0 && len(l.Min) == 0
+}
+
+type LimitRanges struct {
+	Enabled      bool
+	Default      LimitRange
+	UserEnabled  bool
+	User         LimitRange
+	GroupEnabled bool
+	Group        LimitRange
+	Roles        map[string]LimitRange
+}
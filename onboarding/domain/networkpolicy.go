@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"text/template"
+	"text/template/parse"
+)
+
+type NetworkPolicies struct {
+	Enabled   bool
+	Templates []NetworkPolicyTemplate
+}
+
+// NetworkPolicyTemplate renders the spec of a NetworkPolicy, as YAML. The
+// template is a text/template executed with a NetworkPolicyData. User and
+// group names come from the token, so apart from the namespace, values must
+// be printed through the quote function, which renders a YAML string.
+type NetworkPolicyTemplate struct {
+	Name string
+	spec *template.Template
+}
+
+// NetworkPolicyData holds the values substituted in a NetworkPolicyTemplate.
+type NetworkPolicyData struct {
+	Namespace string
+	User      string
+	// Group is empty in user namespaces.
+	Group string
+}
+
+// NetworkPolicy is a rendered NetworkPolicyTemplate.
+type NetworkPolicy struct {
+	Name string
+	// Spec is the YAML spec of the policy.
+	Spec string
+}
+
+var networkPolicyFuncs = template.FuncMap{"quote": quote}
+
+// quote renders s as a double-quoted YAML string.
+func quote(s string) (string, error) {
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+func NewNetworkPolicyTemplate(name, spec string) (NetworkPolicyTemplate, error) {
+	if name == "" {
+		return NetworkPolicyTemplate{}, errors.New("network policy name is required")
+	}
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(networkPolicyFuncs).
+		Parse(spec)
+	if err != nil {
+		return NetworkPolicyTemplate{}, fmt.Errorf("network policy %q: %w", name, err)
+	}
+	for _, t := range tmpl.Templates() {
+		if err := checkQuoted(t.Root); err != nil {
+			return NetworkPolicyTemplate{}, fmt.Errorf("network policy %q: %w", name, err)
+		}
+	}
+	return NetworkPolicyTemplate{Name: name, spec: tmpl}, nil
+}
+
+// checkQuoted fails on actions printing anything but the namespace without
+// piping it through quote.
+func checkQuoted(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkQuoted(child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) == 0 && !quotedPipe(n.Pipe) {
+			return fmt.Errorf("%s must be piped through quote", n)
+		}
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode)
+	}
+	return nil
+}
+
+func checkBranch(n *parse.BranchNode) error {
+	if err := checkQuoted(n.List); err != nil {
+		return err
+	}
+	return checkQuoted(n.ElseList)
+}
+
+// quotedPipe reports whether a pipeline ends with quote, or prints the
+// namespace, which is a valid DNS label.
+func quotedPipe(p *parse.PipeNode) bool {
+	if len(p.Cmds) == 0 {
+		return true
+	}
+	last := p.Cmds[len(p.Cmds)-1]
+	if id, ok := last.Args[0].(*parse.IdentifierNode); ok && id.Ident == "quote" {
+		return true
+	}
+	if len(p.Cmds) > 1 || len(last.Args) > 1 {
+		return false
+	}
+	switch arg := last.Args[0].(type) {
+	case *parse.FieldNode:
+		return slices.Equal(arg.Ident, []string{"Namespace"})
+	case *parse.VariableNode:
+		return slices.Equal(arg.Ident, []string{"$", "Namespace"})
+	}
+	return false
+}
+
+func (t NetworkPolicyTemplate) Render(data NetworkPolicyData) (*NetworkPolicy, error) {
+	var spec bytes.Buffer
+	if err := t.spec.Execute(&spec, data); err != nil {
+		return nil, fmt.Errorf("network policy %q: %w", t.Name, err)
+	}
+	return &NetworkPolicy{Name: t.Name, Spec: spec.String()}, nil
+}
@@ -1,16 +1,116 @@
 package domain
 
-type Quota struct {
-	MemoryRequest           string
-	CPURequest              string
-	MemoryLimit             string
-	CPULimit                string
-	StorageRequest          string
-	MaxPods                 string
-	EphemeralStorageRequest string
-	EphemeralStorageLimit   string
-	GPURequest              string
-	GPULimit                string
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Quota maps ResourceQuota resource names, such as "requests.memory" or
+// "count/pods", to their hard limit.
+type Quota map[string]resource.Quantity
+
+// ParseQuota parses the quantities of a quota.
+func ParseQuota(values map[string]string) (Quota, error) {
+	quota := make(Quota, len(values))
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q for %s: %w", value, name, err)
+		}
+		quota[name] = quantity
+	}
+	return quota, nil
+}
+
+// String lists the resources of q sorted by name.
+func (q Quota) String() string {
+	names := make([]string, 0, len(q))
+	for name := range q {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := q[name]
+		parts = append(parts, name+"="+quantity.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// Max returns, for every resource of both q and other, the greatest
+// quantity. A resource missing from either quota is unlimited there, so it
+// is left out.
+func (q Quota) Max(other Quota) Quota {
+	out := make(Quota, min(len(q), len(other)))
+	for name, quantity := range q {
+		o, ok := other[name]
+		if !ok {
+			continue
+		}
+		if o.Cmp(quantity) > 0 {
+			quantity = o
+		}
+		out[name] = quantity
+	}
+	return out
+}
+
+// RoleStrategy picks the quota of a user holding several roles of Quotas.Roles.
+type RoleStrategy string
+
+const (
+	// RoleStrategyFirst picks the first matching role in token order.
+	RoleStrategyFirst RoleStrategy = "first"
+	// RoleStrategyPriority picks the first matching role of
+	// Quotas.RolePriority, then the first one in token order.
+	RoleStrategyPriority RoleStrategy = "priority"
+	// RoleStrategyMax takes the greatest quantity of every resource across
+	// all matching roles. Resources unset in one of them are unlimited.
+	RoleStrategyMax RoleStrategy = "max"
+)
+
+// GroupQuota is the quota of the group namespaces whose group is Name, or
+// matches Match.
+type GroupQuota struct {
+	Name  string
+	Match *regexp.Regexp
+	Quota Quota
+}
+
+// Matches reports whether g applies to group.
+func (g GroupQuota) Matches(group string) bool {
+	if g.Match != nil {
+		return g.Match.MatchString(group)
+	}
+	return g.Name == group
+}
+
+// ClaimQuota sets a resource of user quotas from a user attribute, such as
+// an OIDC claim, bounded by Min and Max when set.
+type ClaimQuota struct {
+	Attribute string
+	Resource  string
+	Min       *resource.Quantity
+	Max       *resource.Quantity
+}
+
+// Clamp bounds quantity by c.Min and c.Max.
+func (c ClaimQuota) Clamp(quantity resource.Quantity) resource.Quantity {
+	if c.Min != nil && quantity.Cmp(*c.Min) < 0 {
+		return c.Min.DeepCopy()
+	}
+	if c.Max != nil && quantity.Cmp(*c.Max) > 0 {
+		return c.Max.DeepCopy()
+	}
+	return quantity
 }
 
 type Quotas struct {
@@ -20,5 +120,11 @@ type Quotas struct {
 	User         Quota
 	GroupEnabled bool
 	Group        Quota
+	// Groups are tried in order before Group.
+	Groups       []GroupQuota
 	Roles        map[string]Quota
+	RoleStrategy RoleStrategy
+	RolePriority []string
+	// Claims override the resources of the quota of user namespaces.
+	Claims []ClaimQuota
 }
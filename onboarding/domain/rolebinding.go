@@ -0,0 +1,27 @@
+package domain
+
+// RoleBindings configures the RoleBinding granting the owner of a namespace
+// a ClusterRole in it: the user in a user namespace, the OIDC group in a
+// group namespace.
+type RoleBindings struct {
+	Enabled     bool
+	ClusterRole string
+	// UsernamePrefix and GroupPrefix are prepended to subject names, to
+	// match the prefixes the API server gives to OIDC users and groups.
+	UsernamePrefix string
+	GroupPrefix    string
+}
+
+type SubjectKind string
+
+const (
+	SubjectUser  SubjectKind = "User"
+	SubjectGroup SubjectKind = "Group"
+)
+
+// RoleBinding grants a ClusterRole to a single subject.
+type RoleBinding struct {
+	ClusterRole string
+	SubjectKind SubjectKind
+	SubjectName string
+}
@@ -8,6 +8,9 @@ import (
 
 type NamespaceCreationResult string
 type QuotaApplicationResult string
+type RoleBindingApplicationResult string
+type LimitRangeApplicationResult string
+type NetworkPolicyApplicationResult string
 
 const (
 	NamespaceCreated            NamespaceCreationResult = "created"
@@ -20,6 +23,28 @@ const (
 	QuotaUpdated   QuotaApplicationResult = "updated"
 	QuotaUnchanged QuotaApplicationResult = "unchanged"
 	QuotaIgnored   QuotaApplicationResult = "ignored"
+	QuotaDeleted   QuotaApplicationResult = "deleted"
+)
+
+const (
+	RoleBindingCreated   RoleBindingApplicationResult = "created"
+	RoleBindingUpdated   RoleBindingApplicationResult = "updated"
+	RoleBindingUnchanged RoleBindingApplicationResult = "unchanged"
+	RoleBindingIgnored   RoleBindingApplicationResult = "ignored"
+)
+
+const (
+	LimitRangeCreated   LimitRangeApplicationResult = "created"
+	LimitRangeUpdated   LimitRangeApplicationResult = "updated"
+	LimitRangeUnchanged LimitRangeApplicationResult = "unchanged"
+	LimitRangeIgnored   LimitRangeApplicationResult = "ignored"
+)
+
+const (
+	NetworkPolicyCreated   NetworkPolicyApplicationResult = "created"
+	NetworkPolicyUpdated   NetworkPolicyApplicationResult = "updated"
+	NetworkPolicyUnchanged NetworkPolicyApplicationResult = "unchanged"
+	NetworkPolicyIgnored   NetworkPolicyApplicationResult = "ignored"
 )
 
 type NamespaceService interface {
@@ -34,4 +59,26 @@ type NamespaceService interface {
 		namespace string,
 		quota *domain.Quota,
 	) (QuotaApplicationResult, error)
+	ApplyLimitRange(
+		ctx context.Context,
+		namespace string,
+		limitRange *domain.LimitRange,
+	) (LimitRangeApplicationResult, error)
+	ApplyNetworkPolicy(
+		ctx context.Context,
+		namespace string,
+		policy *domain.NetworkPolicy,
+	) (NetworkPolicyApplicationResult, error)
+	// PruneNetworkPolicies deletes the network policies Onyxia created in
+	// namespace whose name is not in keep, and returns the deleted names.
+	PruneNetworkPolicies(
+		ctx context.Context,
+		namespace string,
+		keep []string,
+	) ([]string, error)
+	ApplyRoleBinding(
+		ctx context.Context,
+		namespace string,
+		binding *domain.RoleBinding,
+	) (RoleBindingApplicationResult, error)
 }
@@ -44,6 +44,43 @@ func (m *MockNamespaceService) ApplyResourceQuotas(
 	return args.Get(0).(port.QuotaApplicationResult), args.Error(1)
 }
 
+func (m *MockNamespaceService) ApplyLimitRange(
+	ctx context.Context,
+	namespace string,
+	limitRange *domain.LimitRange,
+) (port.LimitRangeApplicationResult, error) {
+	args := m.Called(ctx, namespace, limitRange)
+	return args.Get(0).(port.LimitRangeApplicationResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) ApplyNetworkPolicy(
+	ctx context.Context,
+	namespace string,
+	policy *domain.NetworkPolicy,
+) (port.NetworkPolicyApplicationResult, error) {
+	args := m.Called(ctx, namespace, policy)
+	return args.Get(0).(port.NetworkPolicyApplicationResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) PruneNetworkPolicies(
+	ctx context.Context,
+	namespace string,
+	keep []string,
+) ([]string, error) {
+	args := m.Called(ctx, namespace, keep)
+	deleted, _ := args.Get(0).([]string)
+	return deleted, args.Error(1)
+}
+
+func (m *MockNamespaceService) ApplyRoleBinding(
+	ctx context.Context,
+	namespace string,
+	binding *domain.RoleBinding,
+) (port.RoleBindingApplicationResult, error) {
+	args := m.Called(ctx, namespace, binding)
+	return args.Get(0).(port.RoleBindingApplicationResult), args.Error(1)
+}
+
 // ---------- Usercontext helpers ----------
 
 var defaultTestUser = &usercontext.User{
@@ -77,6 +114,9 @@ func setupUsecase(
 			},
 		},
 		quotas,
+		domain.LimitRanges{},
+		domain.NetworkPolicies{},
+		domain.RoleBindings{},
 		reader,
 	)
 }
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+)
+
+func (s *onboardingUsecase) applyLimitRange(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if !s.limitRanges.Enabled {
+		return nil
+	}
+
+	limitRange := s.getLimitRange(ctx, req, namespace)
+
+	result, err := s.namespaceService.ApplyLimitRange(ctx, namespace, limitRange)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to apply limit range",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to apply limit range to namespace (%s): %w", namespace, err)
+	}
+
+	switch result {
+	case port.LimitRangeCreated:
+		slog.InfoContext(ctx, "Limit range created",
+			slog.String("namespace", namespace),
+		)
+	case port.LimitRangeUpdated:
+		slog.InfoContext(ctx, "Limit range updated",
+			slog.String("namespace", namespace),
+		)
+	case port.LimitRangeUnchanged:
+		slog.InfoContext(ctx, "Limit range already up-to-date",
+			slog.String("namespace", namespace),
+		)
+	case port.LimitRangeIgnored:
+		slog.WarnContext(ctx, "Limit range ignored due to annotation",
+			slog.String("namespace", namespace),
+		)
+	}
+
+	return nil
+}
+
+// getLimitRange picks the limit range of a namespace the way getQuota picks
+// its quota.
+func (s *onboardingUsecase) getLimitRange(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+	namespace string,
+) *domain.LimitRange {
+	if req.Group != nil {
+		if s.limitRanges.GroupEnabled {
+			return &s.limitRanges.Group
+		}
+		return &s.limitRanges.Default
+	}
+
+	for _, role := range req.UserRoles {
+		if limitRange, exists := s.limitRanges.Roles[role]; exists {
+			slog.InfoContext(ctx, "Applying role-based limit range",
+				slog.String("namespace", namespace),
+				slog.String("role", role),
+			)
+			return &limitRange
+		}
+	}
+
+	if s.limitRanges.UserEnabled {
+		return &s.limitRanges.User
+	}
+	return &s.limitRanges.Default
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var testLimitRanges = domain.LimitRanges{
+	Enabled:      true,
+	Default:      domain.LimitRange{DefaultLimit: map[string]resource.Quantity{"cpu": resource.MustParse("1")}},
+	UserEnabled:  true,
+	User:         domain.LimitRange{DefaultLimit: map[string]resource.Quantity{"cpu": resource.MustParse("2")}},
+	GroupEnabled: true,
+	Group:        domain.LimitRange{DefaultLimit: map[string]resource.Quantity{"cpu": resource.MustParse("4")}},
+	Roles: map[string]domain.LimitRange{
+		"role1": {DefaultLimit: map[string]resource.Quantity{"cpu": resource.MustParse("8")}},
+	},
+}
+
+// ✅ The user limit range applies to user namespaces.
+func TestApplyLimitRangeUser(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.limitRanges = testLimitRanges
+
+	mockService.On("ApplyLimitRange", mock.Anything, userNamespace, &usecase.limitRanges.User).
+		Return(port.LimitRangeCreated, nil)
+
+	err := usecase.applyLimitRange(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyLimitRange", mock.Anything, userNamespace, &usecase.limitRanges.User)
+}
+
+// ✅ Role limit ranges take precedence over the user one.
+func TestApplyLimitRangeRole(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.limitRanges = testLimitRanges
+
+	expected := testLimitRanges.Roles["role1"]
+	mockService.On("ApplyLimitRange", mock.Anything, userNamespace, &expected).
+		Return(port.LimitRangeUpdated, nil)
+
+	err := usecase.applyLimitRange(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName, UserRoles: []string{"role1"}},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyLimitRange", mock.Anything, userNamespace, &expected)
+}
+
+// ✅ The group limit range applies to group namespaces.
+func TestApplyLimitRangeGroup(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.limitRanges = testLimitRanges
+
+	mockService.On("ApplyLimitRange", mock.Anything, groupNamespace, &usecase.limitRanges.Group).
+		Return(port.LimitRangeUnchanged, nil)
+
+	groupName := testGroupName
+	err := usecase.applyLimitRange(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyLimitRange", mock.Anything, groupNamespace, &usecase.limitRanges.Group)
+}
+
+// ✅ Nothing is applied when limit ranges are disabled.
+func TestApplyLimitRangeDisabled(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	err := usecase.applyLimitRange(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyLimitRange")
+}
+
+// ❌ Failures are reported.
+func TestApplyLimitRangeFails(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.limitRanges = testLimitRanges
+
+	mockService.On("ApplyLimitRange", mock.Anything, userNamespace, mock.Anything).
+		Return(port.LimitRangeApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyLimitRange(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.ErrorContains(t, err, "failed to apply limit range")
+}
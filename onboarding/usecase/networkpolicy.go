@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+)
+
+func (s *onboardingUsecase) applyNetworkPolicies(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if !s.networkPolicies.Enabled {
+		return nil
+	}
+
+	data := domain.NetworkPolicyData{Namespace: namespace, User: req.UserName}
+	if req.Group != nil {
+		data.Group = *req.Group
+	}
+
+	names := make([]string, 0, len(s.networkPolicies.Templates))
+	for _, tmpl := range s.networkPolicies.Templates {
+		names = append(names, tmpl.Name)
+		policy, err := tmpl.Render(data)
+		if err != nil {
+			return fmt.Errorf("failed to render network policy for namespace (%s): %w", namespace, err)
+		}
+
+		result, err := s.namespaceService.ApplyNetworkPolicy(ctx, namespace, policy)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to apply network policy",
+				slog.String("namespace", namespace),
+				slog.String("policy", policy.Name),
+				slog.Any("error", err),
+			)
+			return fmt.Errorf("failed to apply network policy to namespace (%s): %w", namespace, err)
+		}
+
+		switch result {
+		case port.NetworkPolicyCreated:
+			slog.InfoContext(ctx, "Network policy created",
+				slog.String("namespace", namespace),
+				slog.String("policy", policy.Name),
+			)
+		case port.NetworkPolicyUpdated:
+			slog.InfoContext(ctx, "Network policy updated",
+				slog.String("namespace", namespace),
+				slog.String("policy", policy.Name),
+			)
+		case port.NetworkPolicyUnchanged:
+			slog.InfoContext(ctx, "Network policy already up-to-date",
+				slog.String("namespace", namespace),
+				slog.String("policy", policy.Name),
+			)
+		case port.NetworkPolicyIgnored:
+			slog.WarnContext(ctx, "Network policy ignored due to annotation",
+				slog.String("namespace", namespace),
+				slog.String("policy", policy.Name),
+			)
+		}
+	}
+
+	// Policies whose template was removed from the configuration go too.
+	deleted, err := s.namespaceService.PruneNetworkPolicies(ctx, namespace, names)
+	for _, name := range deleted {
+		slog.InfoContext(ctx, "Network policy deleted",
+			slog.String("namespace", namespace),
+			slog.String("policy", name),
+		)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to prune network policies",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to prune network policies of namespace (%s): %w", namespace, err)
+	}
+
+	return nil
+}
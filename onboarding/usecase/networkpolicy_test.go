@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testNetworkPolicies(t *testing.T) domain.NetworkPolicies {
+	t.Helper()
+	tmpl, err := domain.NewNetworkPolicyTemplate(
+		"allow-same-namespace",
+		"namespace: {{ .Namespace }}\ngroup: {{ .Group | quote }}\nuser: {{ quote .User }}\n",
+	)
+	require.NoError(t, err)
+	return domain.NetworkPolicies{Enabled: true, Templates: []domain.NetworkPolicyTemplate{tmpl}}
+}
+
+// ✅ Placeholders are substituted in group namespaces.
+func TestApplyNetworkPoliciesGroup(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies(t)
+
+	expected := &domain.NetworkPolicy{
+		Name: "allow-same-namespace",
+		Spec: "namespace: " + groupNamespace + "\ngroup: \"" + testGroupName + "\"\nuser: \"" + testUserName + "\"\n",
+	}
+	mockService.On("ApplyNetworkPolicy", mock.Anything, groupNamespace, expected).
+		Return(port.NetworkPolicyCreated, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, groupNamespace, []string{"allow-same-namespace"}).
+		Return([]string(nil), nil)
+
+	groupName := testGroupName
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyNetworkPolicy", mock.Anything, groupNamespace, expected)
+}
+
+// ✅ The group is empty in user namespaces.
+func TestApplyNetworkPoliciesUser(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies(t)
+
+	expected := &domain.NetworkPolicy{
+		Name: "allow-same-namespace",
+		Spec: "namespace: " + userNamespace + "\ngroup: \"\"\nuser: \"" + testUserName + "\"\n",
+	}
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, expected).
+		Return(port.NetworkPolicyUnchanged, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace, []string{"allow-same-namespace"}).
+		Return([]string(nil), nil)
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyNetworkPolicy", mock.Anything, userNamespace, expected)
+}
+
+// ✅ Names from the token cannot inject YAML.
+func TestApplyNetworkPoliciesQuotesNames(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies(t)
+
+	expected := &domain.NetworkPolicy{
+		Name: "allow-same-namespace",
+		Spec: "namespace: " + userNamespace + "\ngroup: \"\"\nuser: \"x\\nipBlock: {cidr: 0.0.0.0/0}\"\n",
+	}
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, expected).
+		Return(port.NetworkPolicyCreated, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace, []string{"allow-same-namespace"}).
+		Return([]string(nil), nil)
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: "x\nipBlock: {cidr: 0.0.0.0/0}"},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyNetworkPolicy", mock.Anything, userNamespace, expected)
+}
+
+// ❌ Templates printing names without quote are refused.
+func TestNewNetworkPolicyTemplateRequiresQuote(t *testing.T) {
+	for _, spec := range []string{
+		"user: {{ .User }}",
+		"group: {{ if .Group }}{{ .Group }}{{ end }}",
+		"user: {{ $u := .User }}{{ $u }}",
+		"user: {{ .User | printf \"%s\" }}",
+	} {
+		_, err := domain.NewNetworkPolicyTemplate("unquoted", spec)
+		assert.ErrorContains(t, err, "must be piped through quote", spec)
+	}
+
+	_, err := domain.NewNetworkPolicyTemplate(
+		"quoted",
+		"ns: {{ $.Namespace }}\nuser: {{ .User | printf \"u-%s\" | quote }}{{ with .Group }}\ngroup: {{ quote . }}{{ end }}",
+	)
+	assert.NoError(t, err)
+}
+
+// ✅ Nothing is applied when network policies are disabled.
+func TestApplyNetworkPoliciesDisabled(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyNetworkPolicy")
+	mockService.AssertNotCalled(t, "PruneNetworkPolicies")
+}
+
+// ❌ Failures are reported.
+func TestApplyNetworkPoliciesFails(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies(t)
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, mock.Anything).
+		Return(port.NetworkPolicyApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.ErrorContains(t, err, "failed to apply network policy")
+	mockService.AssertNotCalled(t, "PruneNetworkPolicies")
+}
+
+// ✅ Policies no longer configured are pruned.
+func TestApplyNetworkPoliciesPrunes(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies(t)
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, mock.Anything).
+		Return(port.NetworkPolicyUnchanged, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace, []string{"allow-same-namespace"}).
+		Return([]string{"allow-removed"}, nil)
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+// ✅ Removing every template prunes every policy.
+func TestApplyNetworkPoliciesPrunesAll(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = domain.NetworkPolicies{Enabled: true}
+
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace, []string{}).
+		Return([]string{"allow-same-namespace"}, nil)
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+// ❌ Prune failures are reported.
+func TestApplyNetworkPoliciesPruneFails(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies(t)
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, mock.Anything).
+		Return(port.NetworkPolicyUnchanged, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace, mock.Anything).
+		Return([]string(nil), errors.New("forbidden"))
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.ErrorContains(t, err, "failed to prune network policies")
+}
@@ -12,6 +12,9 @@ type onboardingUsecase struct {
 	namespaceService  port.NamespaceService
 	namespace         domain.Namespace
 	quotas            domain.Quotas
+	limitRanges       domain.LimitRanges
+	networkPolicies   domain.NetworkPolicies
+	roleBindings      domain.RoleBindings
 	userContextReader usercontext.Reader
 }
 
@@ -19,6 +22,9 @@ func NewOnboardingUsecase(
 	namespaceService port.NamespaceService,
 	namespace domain.Namespace,
 	quotas domain.Quotas,
+	limitRanges domain.LimitRanges,
+	networkPolicies domain.NetworkPolicies,
+	roleBindings domain.RoleBindings,
 	userContextReader usercontext.Reader,
 
 ) *onboardingUsecase {
@@ -26,6 +32,9 @@ func NewOnboardingUsecase(
 		namespaceService:  namespaceService,
 		namespace:         namespace,
 		quotas:            quotas,
+		limitRanges:       limitRanges,
+		networkPolicies:   networkPolicies,
+		roleBindings:      roleBindings,
 		userContextReader: userContextReader,
 	}
 }
@@ -37,6 +46,18 @@ func (s *onboardingUsecase) Onboard(ctx context.Context, req domain.OnboardingRe
 		return err
 	}
 
+	if err := s.applyRoleBinding(ctx, namespace, req); err != nil {
+		return err
+	}
+
+	if err := s.applyNetworkPolicies(ctx, namespace, req); err != nil {
+		return err
+	}
+
+	if err := s.applyLimitRange(ctx, namespace, req); err != nil {
+		return err
+	}
+
 	if err := s.applyQuotas(ctx, namespace, req); err != nil {
 		return err
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // ✅ Test `Onboard` Success (Namespace & Quota Applied)
@@ -17,7 +18,7 @@ func TestOnboardSuccess(t *testing.T) {
 	quotas := domain.Quotas{
 		Enabled:      true,
 		GroupEnabled: true,
-		Group:        domain.Quota{MemoryRequest: "12Gi"},
+		Group:        domain.Quota{"requests.memory": resource.MustParse("12Gi")},
 	}
 	usecase := setupUsecase(mockService, quotas)
 
@@ -76,7 +77,7 @@ func TestOnboardCreateNamespaceFails(t *testing.T) {
 // ❌ Test `Onboard` (Quota Application Fails)
 func TestOnboardApplyResourceQuotasFails(t *testing.T) {
 	mockService := new(MockNamespaceService)
-	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{MemoryRequest: "10Gi"}}
+	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")}}
 	usecase := setupUsecase(mockService, quotas)
 
 	mockService.On("CreateNamespace", mock.Anything, defaultNamespace).
@@ -102,7 +103,7 @@ func TestOnboardApplyResourceQuotasFails(t *testing.T) {
 // ✅ Test `Onboard` Success (Namespace Already Exists)
 func TestOnboardNamespaceAlreadyExists(t *testing.T) {
 	mockService := new(MockNamespaceService)
-	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{MemoryRequest: "10Gi"}}
+	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")}}
 	usecase := setupUsecase(mockService, quotas)
 
 	mockService.On("CreateNamespace", mock.Anything, defaultNamespace).
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
 
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func (s *onboardingUsecase) applyQuotas(
@@ -52,6 +56,10 @@ func (s *onboardingUsecase) applyQuotas(
 		slog.WarnContext(ctx, "Quota ignored due to annotation",
 			slog.String("namespace", namespace),
 		)
+	case port.QuotaDeleted:
+		slog.InfoContext(ctx, "Resource quota deleted, no resource is limited",
+			slog.String("namespace", namespace),
+		)
 	}
 
 	return nil
@@ -65,7 +73,66 @@ func (s *onboardingUsecase) getQuota(
 	if req.Group != nil {
 		return s.getGroupQuota(ctx, req, namespace)
 	}
-	return s.getUserQuota(ctx, req, namespace)
+	return s.withClaimQuotas(ctx, s.getUserQuota(ctx, req, namespace), namespace)
+}
+
+// withClaimQuotas overrides the resources of quota set by the claims of the
+// user.
+func (s *onboardingUsecase) withClaimQuotas(
+	ctx context.Context,
+	quota *domain.Quota,
+	namespace string,
+) *domain.Quota {
+	if len(s.quotas.Claims) == 0 {
+		return quota
+	}
+	attributes, ok := s.userContextReader.GetAttributes(ctx)
+	if !ok {
+		return quota
+	}
+
+	var result domain.Quota
+	for _, claim := range s.quotas.Claims {
+		value, ok := attributes[claim.Attribute]
+		if !ok || value == nil {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(claimString(value))
+		if err != nil {
+			slog.WarnContext(ctx, "Ignoring invalid quota claim",
+				slog.String("namespace", namespace),
+				slog.String("attribute", claim.Attribute),
+				slog.Any("value", value),
+			)
+			continue
+		}
+
+		if result == nil {
+			result = maps.Clone(*quota)
+			if result == nil {
+				result = domain.Quota{}
+			}
+		}
+		result[claim.Resource] = claim.Clamp(quantity)
+		slog.InfoContext(ctx, "Applying quota claim",
+			slog.String("namespace", namespace),
+			slog.String("attribute", claim.Attribute),
+			slog.String("resource", claim.Resource),
+		)
+	}
+
+	if result == nil {
+		return quota
+	}
+	return &result
+}
+
+// claimString formats a claim value as a quantity.
+func claimString(value any) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
 }
 
 func (s *onboardingUsecase) getGroupQuota(
@@ -73,6 +140,16 @@ func (s *onboardingUsecase) getGroupQuota(
 	req domain.OnboardingRequest,
 	namespace string,
 ) *domain.Quota {
+	for i, g := range s.quotas.Groups {
+		if g.Matches(*req.Group) {
+			slog.InfoContext(ctx, "Applying group-specific quota",
+				slog.String("namespace", namespace),
+				slog.String("group", *req.Group),
+			)
+			return &s.quotas.Groups[i].Quota
+		}
+	}
+
 	if s.quotas.GroupEnabled {
 		slog.InfoContext(ctx, "Applying group quota",
 			slog.String("namespace", namespace),
@@ -88,14 +165,8 @@ func (s *onboardingUsecase) getUserQuota(
 	req domain.OnboardingRequest,
 	namespace string,
 ) *domain.Quota {
-	for _, role := range req.UserRoles {
-		if quota, exists := s.quotas.Roles[role]; exists {
-			slog.InfoContext(ctx, "Applying role-based user quota",
-				slog.String("namespace", namespace),
-				slog.String("role", role),
-			)
-			return &quota
-		}
+	if quota := s.getRoleQuota(ctx, req, namespace); quota != nil {
+		return quota
 	}
 
 	if s.quotas.UserEnabled {
@@ -110,3 +181,54 @@ func (s *onboardingUsecase) getUserQuota(
 	)
 	return &s.quotas.Default
 }
+
+// getRoleQuota returns the quota given by the roles of the user according to
+// the role strategy, or nil when none of them has a quota.
+func (s *onboardingUsecase) getRoleQuota(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+	namespace string,
+) *domain.Quota {
+	var roles []string
+	for _, role := range req.UserRoles {
+		if _, exists := s.quotas.Roles[role]; exists && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+
+	switch s.quotas.RoleStrategy {
+	case domain.RoleStrategyMax:
+		quota := maps.Clone(s.quotas.Roles[roles[0]])
+		for _, role := range roles[1:] {
+			quota = quota.Max(s.quotas.Roles[role])
+		}
+		slog.InfoContext(ctx, "Applying maximum of role-based user quotas",
+			slog.String("namespace", namespace),
+			slog.Any("roles", roles),
+		)
+		return &quota
+
+	case domain.RoleStrategyPriority:
+		slices.SortStableFunc(roles, func(a, b string) int {
+			return rolePriority(s.quotas.RolePriority, a) - rolePriority(s.quotas.RolePriority, b)
+		})
+	}
+
+	quota := s.quotas.Roles[roles[0]]
+	slog.InfoContext(ctx, "Applying role-based user quota",
+		slog.String("namespace", namespace),
+		slog.String("role", roles[0]),
+	)
+	return &quota
+}
+
+// rolePriority ranks role in priorities; unlisted roles come last.
+func rolePriority(priorities []string, role string) int {
+	if i := slices.Index(priorities, role); i >= 0 {
+		return i
+	}
+	return len(priorities)
+}
@@ -3,19 +3,22 @@ package usecase
 import (
 	"context"
 	"errors"
+	"regexp"
 	"testing"
 
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
 	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestApplyQuotasSuccess(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -42,7 +45,7 @@ func TestApplyQuotasAlreadyUpToDate(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -84,7 +87,7 @@ func TestApplyQuotasQuotaUpdated(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -111,7 +114,7 @@ func TestApplyQuotasQuotaIgnored(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -137,7 +140,7 @@ func TestApplyQuotasFailure(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -164,7 +167,7 @@ func TestGetQuotaGroupQuota(t *testing.T) {
 	quotas := domain.Quotas{
 		Enabled:      true,
 		GroupEnabled: true,
-		Group:        domain.Quota{MemoryRequest: "12Gi"},
+		Group:        domain.Quota{"requests.memory": resource.MustParse("12Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -180,9 +183,9 @@ func TestGetGroupQuotaFallbackToDefault(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled:      true,
-		GroupEnabled: false,                               // ❌ Group quotas disabled
-		Default:      domain.Quota{MemoryRequest: "10Gi"}, // ✅ Default quota exists
-		Group:        domain.Quota{MemoryRequest: "20Gi"}, // 🚨 Should not be used
+		GroupEnabled: false,                                                       // ❌ Group quotas disabled
+		Default:      domain.Quota{"requests.memory": resource.MustParse("10Gi")}, // ✅ Default quota exists
+		Group:        domain.Quota{"requests.memory": resource.MustParse("20Gi")}, // 🚨 Should not be used
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -205,7 +208,7 @@ func TestGetQuotaUserQuota(t *testing.T) {
 	quotas := domain.Quotas{
 		Enabled:     true,
 		UserEnabled: true,
-		User:        domain.Quota{MemoryRequest: "11Gi"},
+		User:        domain.Quota{"requests.memory": resource.MustParse("11Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -220,7 +223,7 @@ func TestGetQuotaDefaultQuota(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -236,7 +239,7 @@ func TestGetQuotaRoleQuota(t *testing.T) {
 	quotas := domain.Quotas{
 		Enabled: true,
 		Roles: map[string]domain.Quota{
-			"admin": {MemoryRequest: "16Gi"},
+			"admin": {"requests.memory": resource.MustParse("16Gi")},
 		},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
@@ -257,8 +260,8 @@ func TestGetQuotaRoleQuotaAppliesFirstMatch(t *testing.T) {
 	quotas := domain.Quotas{
 		Enabled: true,
 		Roles: map[string]domain.Quota{
-			"admin":     {MemoryRequest: "16Gi"},
-			"developer": {MemoryRequest: "14Gi"},
+			"admin":     {"requests.memory": resource.MustParse("16Gi")},
+			"developer": {"requests.memory": resource.MustParse("14Gi")},
 		},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
@@ -279,10 +282,10 @@ func TestGetQuotaUserQuotaWhenNoRoleMatches(t *testing.T) {
 	quotas := domain.Quotas{
 		Enabled:     true,
 		UserEnabled: true,
-		User:        domain.Quota{MemoryRequest: "12Gi"},
+		User:        domain.Quota{"requests.memory": resource.MustParse("12Gi")},
 		Roles: map[string]domain.Quota{
-			"admin":     {MemoryRequest: "16Gi"},
-			"developer": {MemoryRequest: "14Gi"},
+			"admin":     {"requests.memory": resource.MustParse("16Gi")},
+			"developer": {"requests.memory": resource.MustParse("14Gi")},
 		},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
@@ -302,8 +305,8 @@ func TestGetQuotaDefaultQuotaWhenNoRoleAndUserQuotaDisabled(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
 		Enabled: true,
-		Default: domain.Quota{MemoryRequest: "10Gi"},
-		User:    domain.Quota{MemoryRequest: "12Gi"},
+		Default: domain.Quota{"requests.memory": resource.MustParse("10Gi")},
+		User:    domain.Quota{"requests.memory": resource.MustParse("12Gi")},
 	}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
@@ -317,3 +320,210 @@ func TestGetQuotaDefaultQuotaWhenNoRoleAndUserQuotaDisabled(t *testing.T) {
 	expectedQuota := quotas.Default
 	assert.Equal(t, &expectedQuota, quota, "Expected default quota when no role/user quota applies")
 }
+
+func roleQuotas(strategy domain.RoleStrategy, priority ...string) domain.Quotas {
+	return domain.Quotas{
+		Enabled: true,
+		Roles: map[string]domain.Quota{
+			"admin": {
+				"requests.memory": resource.MustParse("16Gi"),
+				"requests.cpu":    resource.MustParse("4"),
+			},
+			"gpu-user": {
+				"requests.memory":         resource.MustParse("8Gi"),
+				"requests.nvidia.com/gpu": resource.MustParse("1"),
+			},
+		},
+		RoleStrategy: strategy,
+		RolePriority: priority,
+	}
+}
+
+// ✅ First strategy: the first matching role in token order wins.
+func TestGetUserQuotaRoleStrategyFirst(t *testing.T) {
+	quotas := roleQuotas(domain.RoleStrategyFirst)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"gpu-user", "admin"},
+	}
+
+	quota := usecase.getUserQuota(context.Background(), req, userNamespace)
+
+	assert.Equal(t, quotas.Roles["gpu-user"], *quota)
+}
+
+// ✅ Priority strategy: the listed order wins over token order.
+func TestGetUserQuotaRoleStrategyPriority(t *testing.T) {
+	quotas := roleQuotas(domain.RoleStrategyPriority, "admin", "gpu-user")
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+
+	for _, roles := range [][]string{{"gpu-user", "admin"}, {"admin", "gpu-user"}} {
+		req := domain.OnboardingRequest{UserName: testUserName, UserRoles: roles}
+
+		quota := usecase.getUserQuota(context.Background(), req, userNamespace)
+
+		assert.Equal(t, quotas.Roles["admin"], *quota, roles)
+	}
+}
+
+// ✅ Priority strategy: unlisted roles come after listed ones.
+func TestGetUserQuotaRoleStrategyPriorityUnlisted(t *testing.T) {
+	quotas := roleQuotas(domain.RoleStrategyPriority, "gpu-user")
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"admin", "gpu-user"},
+	}
+
+	quota := usecase.getUserQuota(context.Background(), req, userNamespace)
+
+	assert.Equal(t, quotas.Roles["gpu-user"], *quota)
+}
+
+// ✅ Max strategy: every resource takes its greatest quantity.
+func TestGetUserQuotaRoleStrategyMax(t *testing.T) {
+	quotas := roleQuotas(domain.RoleStrategyMax)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"gpu-user", "admin", "unknown"},
+	}
+
+	quota := usecase.getUserQuota(context.Background(), req, userNamespace)
+
+	assert.Equal(t, "requests.memory=16Gi", quota.String(), "Resources unset in a role are unlimited")
+	assert.Len(t, quotas.Roles["admin"], 2, "Role quotas must not be modified")
+}
+
+// ✅ Max strategy: roles sharing no resource leave every resource unlimited,
+// so an empty quota is applied.
+func TestApplyQuotasRoleStrategyMaxDisjointRoles(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := roleQuotas(domain.RoleStrategyMax)
+	quotas.Roles["gpu-user"] = domain.Quota{"requests.nvidia.com/gpu": resource.MustParse("1")}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &domain.Quota{}).
+		Return(port.QuotaDeleted, nil)
+
+	err := usecase.applyQuotas(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName, UserRoles: []string{"admin", "gpu-user"}},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+// ✅ Max strategy: a single matching role gets its own quota, as a copy.
+func TestGetUserQuotaRoleStrategyMaxSingleRole(t *testing.T) {
+	quotas := roleQuotas(domain.RoleStrategyMax)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+
+	req := domain.OnboardingRequest{UserName: testUserName, UserRoles: []string{"admin"}}
+
+	quota := usecase.getUserQuota(context.Background(), req, userNamespace)
+
+	assert.Equal(t, quotas.Roles["admin"], *quota)
+	delete(*quota, "requests.cpu")
+	assert.Len(t, quotas.Roles["admin"], 2, "Role quotas must not be modified")
+}
+
+// ✅ Group quotas match by name or regex, in order, before the group quota.
+func TestGetGroupQuotaGroups(t *testing.T) {
+	quotas := domain.Quotas{
+		Enabled:      true,
+		GroupEnabled: true,
+		Group:        domain.Quota{"requests.memory": resource.MustParse("10Gi")},
+		Groups: []domain.GroupQuota{
+			{Name: "team-ml", Quota: domain.Quota{"requests.memory": resource.MustParse("64Gi")}},
+			{
+				Match: regexp.MustCompile("^team-"),
+				Quota: domain.Quota{"requests.memory": resource.MustParse("32Gi")},
+			},
+		},
+	}
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+
+	for group, expected := range map[string]*domain.Quota{
+		"team-ml":  &quotas.Groups[0].Quota,
+		"team-web": &quotas.Groups[1].Quota,
+		"other":    &quotas.Group,
+	} {
+		req := domain.OnboardingRequest{UserName: testUserName, Group: &group}
+
+		quota := usecase.getGroupQuota(context.Background(), req, groupNamespace)
+
+		assert.Equal(t, expected, quota, group)
+	}
+}
+
+// ✅ Claims override the resources of the user quota, clamped to their bounds.
+func TestGetQuotaClaimQuotas(t *testing.T) {
+	minCPU, maxCPU := resource.MustParse("1"), resource.MustParse("8")
+	quotas := domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{
+			"requests.cpu":    resource.MustParse("2"),
+			"requests.memory": resource.MustParse("10Gi"),
+		},
+		Claims: []domain.ClaimQuota{
+			{Attribute: "quota_cpu", Resource: "requests.cpu", Min: &minCPU, Max: &maxCPU},
+			{Attribute: "quota_memory", Resource: "requests.memory"},
+			{Attribute: "quota_gpu", Resource: "requests.nvidia.com/gpu"},
+		},
+	}
+	ctx, reader, _ := usercontext.NewTestUserContext(&usercontext.User{
+		Attributes: map[string]any{
+			"quota_cpu":    float64(16),
+			"quota_memory": "32Gi",
+			"quota_gpu":    "lots",
+		},
+	})
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+	usecase.userContextReader = reader
+
+	quota := usecase.getQuota(ctx, domain.OnboardingRequest{UserName: testUserName}, userNamespace)
+
+	assert.Equal(t, "requests.cpu=8 requests.memory=32Gi", quota.String())
+	assert.Equal(
+		t,
+		"requests.cpu=2 requests.memory=10Gi",
+		quotas.Default.String(),
+		"Default quota must not be modified",
+	)
+}
+
+// ✅ Claims do not apply to group namespaces nor to users without them.
+func TestGetQuotaClaimQuotasNotApplied(t *testing.T) {
+	minCPU := resource.MustParse("4")
+	quotas := domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{"requests.cpu": resource.MustParse("2")},
+		Claims: []domain.ClaimQuota{
+			{Attribute: "quota_cpu", Resource: "requests.cpu", Min: &minCPU},
+		},
+	}
+	ctx, reader, _ := usercontext.NewTestUserContext(&usercontext.User{
+		Attributes: map[string]any{"quota_cpu": "1"},
+	})
+	usecase := setupPrivateUsecase(new(MockNamespaceService), quotas)
+	usecase.userContextReader = reader
+
+	group := "team"
+	quota := usecase.getQuota(ctx, domain.OnboardingRequest{UserName: testUserName, Group: &group}, groupNamespace)
+	assert.Equal(t, &quotas.Default, quota)
+
+	quota = usecase.getQuota(ctx, domain.OnboardingRequest{UserName: testUserName}, userNamespace)
+	assert.Equal(t, "requests.cpu=4", quota.String(), "Claim is clamped to min")
+
+	ctx, reader, _ = usercontext.NewTestUserContext(&usercontext.User{Username: testUserName})
+	usecase.userContextReader = reader
+	quota = usecase.getQuota(ctx, domain.OnboardingRequest{UserName: testUserName}, userNamespace)
+	assert.Equal(t, &quotas.Default, quota)
+}
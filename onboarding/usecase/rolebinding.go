@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+)
+
+func (s *onboardingUsecase) applyRoleBinding(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if !s.roleBindings.Enabled {
+		return nil
+	}
+
+	binding := s.getRoleBinding(req)
+
+	result, err := s.namespaceService.ApplyRoleBinding(ctx, namespace, binding)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to apply role binding",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to apply role binding to namespace (%s): %w", namespace, err)
+	}
+
+	switch result {
+	case port.RoleBindingCreated:
+		slog.InfoContext(ctx, "Role binding created",
+			slog.String("namespace", namespace),
+			slog.String("subject", binding.SubjectName),
+		)
+	case port.RoleBindingUpdated:
+		slog.InfoContext(ctx, "Role binding updated",
+			slog.String("namespace", namespace),
+			slog.String("subject", binding.SubjectName),
+		)
+	case port.RoleBindingUnchanged:
+		slog.InfoContext(ctx, "Role binding already up-to-date",
+			slog.String("namespace", namespace),
+		)
+	case port.RoleBindingIgnored:
+		slog.WarnContext(ctx, "Role binding ignored due to annotation",
+			slog.String("namespace", namespace),
+		)
+	}
+
+	return nil
+}
+
+func (s *onboardingUsecase) getRoleBinding(req domain.OnboardingRequest) *domain.RoleBinding {
+	if req.Group != nil {
+		return &domain.RoleBinding{
+			ClusterRole: s.roleBindings.ClusterRole,
+			SubjectKind: domain.SubjectGroup,
+			SubjectName: s.roleBindings.GroupPrefix + *req.Group,
+		}
+	}
+	return &domain.RoleBinding{
+		ClusterRole: s.roleBindings.ClusterRole,
+		SubjectKind: domain.SubjectUser,
+		SubjectName: s.roleBindings.UsernamePrefix + req.UserName,
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/domain"
+	"github.com/onyxia-datalab/onyxia-backend/onboarding/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testRoleBindings = domain.RoleBindings{
+	Enabled:        true,
+	ClusterRole:    "admin",
+	UsernamePrefix: "oidc:",
+	GroupPrefix:    "oidc:",
+}
+
+// ✅ The user is bound in a user namespace.
+func TestApplyRoleBindingUser(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.roleBindings = testRoleBindings
+
+	expected := &domain.RoleBinding{
+		ClusterRole: "admin",
+		SubjectKind: domain.SubjectUser,
+		SubjectName: "oidc:" + testUserName,
+	}
+	mockService.On("ApplyRoleBinding", mock.Anything, userNamespace, expected).
+		Return(port.RoleBindingCreated, nil)
+
+	err := usecase.applyRoleBinding(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyRoleBinding", mock.Anything, userNamespace, expected)
+}
+
+// ✅ The OIDC group is bound in a group namespace.
+func TestApplyRoleBindingGroup(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.roleBindings = testRoleBindings
+
+	expected := &domain.RoleBinding{
+		ClusterRole: "admin",
+		SubjectKind: domain.SubjectGroup,
+		SubjectName: "oidc:" + testGroupName,
+	}
+	mockService.On("ApplyRoleBinding", mock.Anything, groupNamespace, expected).
+		Return(port.RoleBindingUnchanged, nil)
+
+	groupName := testGroupName
+	err := usecase.applyRoleBinding(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyRoleBinding", mock.Anything, groupNamespace, expected)
+}
+
+// ✅ Nothing is bound when role bindings are disabled.
+func TestApplyRoleBindingDisabled(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	err := usecase.applyRoleBinding(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyRoleBinding")
+}
+
+// ❌ Failures are reported.
+func TestApplyRoleBindingFails(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.roleBindings = testRoleBindings
+
+	mockService.On("ApplyRoleBinding", mock.Anything, userNamespace, mock.Anything).
+		Return(port.RoleBindingApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyRoleBinding(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.ErrorContains(t, err, "failed to apply role binding")
+}
@@ -0,0 +1,512 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"helm.sh/helm/v4/pkg/chart/loader"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartloader "helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// A bundle is a gzipped tarball holding bundleManifestName first, then for
+// each catalog a directory named after its id with the chart archives of the
+// exported versions and an index.yaml referencing them.
+const (
+	bundleManifestName = "bundle.json"
+	bundleFormat       = 1
+)
+
+// BundleCatalog describes a catalog of a bundle. Its fields are named after
+// the catalog configuration, so an imported catalog can be configured as is.
+// Access and deprecation policies and package overrides are carried over, so
+// the mirror offers the packages to the same users, presented the same way.
+type BundleCatalog struct {
+	ID          string            `json:"id"`
+	Type        env.CatalogType   `json:"type,omitempty"`
+	Location    string            `json:"location,omitempty"`
+	Name        map[string]string `json:"name,omitempty"`
+	Description map[string]string `json:"description,omitempty"`
+	Maintainer  string            `json:"maintainer,omitempty"`
+	Status      env.CatalogStatus `json:"status,omitempty"`
+	Highlighted []string          `json:"highlightedCharts,omitempty"`
+	Excluded    []string          `json:"excludedCharts,omitempty"`
+
+	Restrictions        []env.Restriction        `json:"restrictions,omitempty"`
+	PackageRestrictions []env.PackageRestriction `json:"packageRestrictions,omitempty"`
+	PackageOverrides    []env.PackageOverride    `json:"packageOverrides,omitempty"`
+	Visible             env.CatalogVisibility    `json:"visible"`
+	// Deprecated versions kept by existingOnly are exported, the mode keeps
+	// them from being installed on the mirror.
+	DeprecatedCharts env.DeprecatedChartsMode `json:"deprecatedCharts,omitempty"`
+	// Provenance files are exported along with the charts. The keyring is
+	// expected at the same path on the mirror.
+	Verify  env.VerifyMode `json:"verify,omitempty"`
+	Keyring *string        `json:"keyring,omitempty"`
+
+	// Versions are filtered on export, the mirror exposes all of them.
+	MultipleServicesMode env.MultipleServicesMode `json:"multipleServicesMode,omitempty"`
+	// Exported versions of each package, newest first.
+	Packages []env.OCIPackage `json:"packages,omitempty"`
+}
+
+type bundleManifest struct {
+	Format   int             `json:"format"`
+	Created  time.Time       `json:"created"`
+	Catalogs []BundleCatalog `json:"catalogs"`
+}
+
+// ExportBundle writes the catalogs given by id, or every catalog when none is
+// given, to w as a bundle. Only the versions the catalog exposes are
+// exported, after multipleServicesMode, version filters, deprecation and
+// provenance policies. Charts are streamed one at a time; on error, what was
+// written to w is not a valid bundle.
+func (h *HelmPackageRepository) ExportBundle(
+	ctx context.Context,
+	w io.Writer,
+	catalogIDs []string,
+) error {
+	cfgs, err := h.bundleCatalogs(catalogIDs)
+	if err != nil {
+		return err
+	}
+
+	manifest := bundleManifest{Format: bundleFormat, Created: time.Now().UTC()}
+	for _, cfg := range cfgs {
+		catalog, err := h.bundleCatalog(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("catalog %q: %w", cfg.ID, err)
+		}
+		manifest.Catalogs = append(manifest.Catalogs, catalog)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, bundleManifestName, data, manifest.Created); err != nil {
+		return err
+	}
+	for i, catalog := range manifest.Catalogs {
+		if err := h.exportCatalog(ctx, tw, cfgs[i], catalog, manifest.Created); err != nil {
+			return fmt.Errorf("catalog %q: %w", catalog.ID, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (h *HelmPackageRepository) bundleCatalogs(ids []string) ([]env.CatalogConfig, error) {
+	if len(ids) == 0 {
+		cfgs := make([]env.CatalogConfig, 0, len(h.catalogs))
+		for _, cfg := range h.catalogs {
+			cfgs = append(cfgs, cfg)
+		}
+		// Deterministic bundles for identical catalogs.
+		sort.Slice(cfgs, func(i, j int) bool { return cfgs[i].ID < cfgs[j].ID })
+		return cfgs, nil
+	}
+
+	cfgs := make([]env.CatalogConfig, 0, len(ids))
+	for _, id := range ids {
+		cfg, ok := h.catalogs[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, id)
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	return cfgs, nil
+}
+
+// bundleCatalog lists the packages and versions of a catalog to export.
+func (h *HelmPackageRepository) bundleCatalog(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+) (BundleCatalog, error) {
+	catalog := BundleCatalog{
+		ID:          cfg.ID,
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Maintainer:  cfg.Maintainer,
+		Status:      cfg.Status,
+		Highlighted: cfg.Highlighted,
+		Excluded:    cfg.Excluded,
+
+		Restrictions:        cfg.Restrictions,
+		PackageRestrictions: cfg.PackageRestrictions,
+		PackageOverrides:    cfg.PackageOverrides,
+		Visible:             cfg.Visible,
+		DeprecatedCharts:    cfg.DeprecatedCharts,
+		Verify:              cfg.Verify,
+		Keyring:             cfg.Keyring,
+	}
+
+	pkgs, err := h.ListPackages(ctx, cfg.ID)
+	if err != nil {
+		return BundleCatalog{}, err
+	}
+	for _, p := range pkgs {
+		ref, err := h.GetPackage(ctx, cfg.ID, p.Name)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return BundleCatalog{}, err
+		}
+
+		pkg := env.OCIPackage{Name: p.Name}
+		for _, v := range ref.Versions {
+			pkg.Versions = append(pkg.Versions, v.Version)
+		}
+		if len(pkg.Versions) > 0 {
+			catalog.Packages = append(catalog.Packages, pkg)
+		}
+	}
+	sort.Slice(catalog.Packages, func(i, j int) bool {
+		return catalog.Packages[i].Name < catalog.Packages[j].Name
+	})
+	return catalog, nil
+}
+
+// exportCatalog writes the chart archives of catalog, then an index.yaml
+// referencing them.
+func (h *HelmPackageRepository) exportCatalog(
+	ctx context.Context,
+	tw *tar.Writer,
+	cfg env.CatalogConfig,
+	catalog BundleCatalog,
+	modTime time.Time,
+) error {
+	idx := repo.NewIndexFile()
+	charts := 0
+	for _, pkg := range catalog.Packages {
+		for _, version := range pkg.Versions {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			data, err := h.chartArchive(ctx, cfg, pkg.Name, version)
+			if err != nil {
+				return fmt.Errorf("chart %s %s: %w", pkg.Name, version, err)
+			}
+			md, err := archiveMetadata(data)
+			if err != nil {
+				return fmt.Errorf("chart %s %s: %w", pkg.Name, version, err)
+			}
+			digest, err := provenance.Digest(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+
+			name := chartArchiveName(pkg.Name, version)
+			if err := idx.MustAdd(md, name, "", digest); err != nil {
+				return fmt.Errorf("indexing %s: %w", name, err)
+			}
+			// Provenance files come first, OCI imports push them with the chart.
+			if cfg.Verify != "" {
+				if prov, ok := h.bundleProvenance(ctx, cfg, pkg.Name, version); ok {
+					if err := writeTarFile(tw, path.Join(catalog.ID, name+".prov"), prov, modTime); err != nil {
+						return err
+					}
+				}
+			}
+			if err := writeTarFile(tw, path.Join(catalog.ID, name), data, modTime); err != nil {
+				return err
+			}
+			charts++
+		}
+	}
+
+	idx.SortEntries()
+	index, err := yaml.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, path.Join(catalog.ID, "index.yaml"), index, modTime); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Catalog exported",
+		slog.String("catalog", catalog.ID),
+		slog.Int("packages", len(catalog.Packages)),
+		slog.Int("charts", charts),
+	)
+	return nil
+}
+
+// bundleProvenance returns the provenance file of a chart version. Unsigned
+// versions are only exported in warn mode, where they stay unverified on the
+// mirror.
+func (h *HelmPackageRepository) bundleProvenance(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
+) ([]byte, bool) {
+	src, err := h.locateChart(ctx, cfg, name, version)
+	if err == nil {
+		var prov []byte
+		if prov, err = h.provenanceFile(ctx, cfg, src); err == nil {
+			return prov, true
+		}
+	}
+	slog.WarnContext(ctx, "Chart exported without provenance",
+		slog.String("catalog", cfg.ID),
+		slog.String("package", name),
+		slog.String("version", version),
+		slog.Any("error", err),
+	)
+	return nil, false
+}
+
+// chartArchive returns the packaged archive of a chart version. Unpacked
+// charts of directory catalogs are packaged on the fly.
+func (h *HelmPackageRepository) chartArchive(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
+) ([]byte, error) {
+	src, err := h.locateChart(ctx, cfg, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if !src.local {
+		opts, err := h.getterOptions(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return h.fetch(src.url, opts...)
+	}
+
+	info, err := os.Stat(src.url)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return os.ReadFile(src.url)
+	}
+
+	ch, err := chartloader.Load(src.url)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "onyxia-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	archive, err := chartutil.Save(ch, tmp)
+	if err != nil {
+		return nil, fmt.Errorf("packaging chart: %w", err)
+	}
+	return os.ReadFile(archive)
+}
+
+func archiveMetadata(data []byte) (*chartv2.Metadata, error) {
+	raw, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	ch, ok := raw.(*chartv2.Chart)
+	if !ok || ch.Metadata == nil {
+		return nil, fmt.Errorf("unexpected chart type %T", raw)
+	}
+	return ch.Metadata, nil
+}
+
+func chartArchiveName(name, version string) string {
+	return fmt.Sprintf("%s-%s.tgz", name, version)
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ImportOptions configures where ImportBundle publishes charts.
+type ImportOptions struct {
+	// Target is a local directory, or an oci:// registry location.
+	Target string
+
+	// Registry credentials, for OCI targets.
+	Username  string
+	Password  string
+	PlainHTTP bool
+}
+
+// ImportBundle publishes the charts of a bundle read from r. Each catalog
+// becomes a directory catalog under the target directory, or an OCI catalog
+// under the target registry location. It returns the configuration of the
+// imported catalogs, which expose exactly the exported versions.
+func ImportBundle(ctx context.Context, r io.Reader, opts ImportOptions) ([]BundleCatalog, error) {
+	oci := registry.IsOCI(opts.Target)
+	var push func(catalogID string, data, prov []byte) error
+	if oci {
+		var clientOpts []registry.ClientOption
+		if opts.Username != "" {
+			clientOpts = append(clientOpts, registry.ClientOptBasicAuth(opts.Username, opts.Password))
+		}
+		if opts.PlainHTTP {
+			clientOpts = append(clientOpts, registry.ClientOptPlainHTTP())
+		}
+		client, err := registry.NewClient(clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating registry client: %w", err)
+		}
+		push = func(catalogID string, data, prov []byte) error {
+			md, err := archiveMetadata(data)
+			if err != nil {
+				return err
+			}
+			ref := fmt.Sprintf("%s/%s:%s", ociCatalogLocation(opts.Target, catalogID), md.Name, md.Version)
+			var pushOpts []registry.PushOption
+			if prov != nil {
+				pushOpts = append(pushOpts, registry.PushOptProvData(prov))
+			}
+			_, err = client.Push(data, ref, pushOpts...)
+			return err
+		}
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	manifest, err := readBundleManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	catalogs := make(map[string]bool, len(manifest.Catalogs))
+	for _, c := range manifest.Catalogs {
+		catalogs[c.ID] = true
+	}
+	// Provenance files precede their chart.
+	provs := make(map[string][]byte)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		catalogID, file, ok := strings.Cut(hdr.Name, "/")
+		if !ok || !catalogs[catalogID] || file != path.Base(file) || file == ".." {
+			return nil, fmt.Errorf("unexpected bundle entry %q", hdr.Name)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+
+		switch {
+		case oci && strings.HasSuffix(file, ".tgz"):
+			if err := push(catalogID, data, provs[hdr.Name+".prov"]); err != nil {
+				return nil, fmt.Errorf("pushing %s: %w", hdr.Name, err)
+			}
+		case oci && strings.HasSuffix(file, ".prov"):
+			provs[hdr.Name] = data
+		case oci:
+			// The registry is the index.
+		default:
+			dir := filepath.Join(opts.Target, catalogID)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(dir, file), data, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		slog.DebugContext(ctx, "Bundle entry imported", slog.String("entry", hdr.Name))
+	}
+
+	imported := make([]BundleCatalog, 0, len(manifest.Catalogs))
+	for _, c := range manifest.Catalogs {
+		c.MultipleServicesMode = env.MultipleServicesAll
+		if oci {
+			c.Type = env.CatalogTypeOCI
+			c.Location = ociCatalogLocation(opts.Target, c.ID)
+		} else {
+			location, err := filepath.Abs(filepath.Join(opts.Target, c.ID))
+			if err != nil {
+				return nil, err
+			}
+			c.Type = env.CatalogTypeDirectory
+			c.Location = location
+			// Directory catalogs list the charts they hold.
+			c.Packages = nil
+		}
+		imported = append(imported, c)
+	}
+	return imported, nil
+}
+
+func readBundleManifest(tr *tar.Reader) (bundleManifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return bundleManifest{}, fmt.Errorf("reading bundle: %w", err)
+	}
+	if hdr.Name != bundleManifestName {
+		return bundleManifest{}, fmt.Errorf("not a catalog bundle: first entry is %q", hdr.Name)
+	}
+
+	var manifest bundleManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return bundleManifest{}, fmt.Errorf("parsing %s: %w", bundleManifestName, err)
+	}
+	if manifest.Format != bundleFormat {
+		return bundleManifest{}, fmt.Errorf("unsupported bundle format %d", manifest.Format)
+	}
+	for _, c := range manifest.Catalogs {
+		if c.ID == "" || c.ID != path.Base(c.ID) || c.ID == ".." {
+			return bundleManifest{}, fmt.Errorf("invalid catalog id %q in bundle", c.ID)
+		}
+	}
+	return manifest, nil
+}
+
+func ociCatalogLocation(target, catalogID string) string {
+	return strings.TrimSuffix(target, "/") + "/" + catalogID
+}
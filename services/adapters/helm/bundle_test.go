@@ -0,0 +1,208 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/onyxia-datalab/onyxia-backend/internal/configloader"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/repo/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBundle_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		_, err := chartutil.Save(&chartv2.Chart{Metadata: &chartv2.Metadata{
+			APIVersion: chartv2.APIVersionV2,
+			Name:       "app",
+			Version:    version,
+		}}, dir)
+		require.NoError(t, err)
+	}
+	require.NoError(t, chartutil.SaveDir(&chartv2.Chart{Metadata: &chartv2.Metadata{
+		APIVersion: chartv2.APIVersionV2,
+		Name:       "unpacked",
+		Version:    "0.2.0",
+	}}, dir))
+
+	source := env.CatalogConfig{
+		ID:                   "ide",
+		Type:                 env.CatalogTypeDirectory,
+		Location:             dir,
+		Name:                 map[string]string{"en": "IDE"},
+		MultipleServicesMode: env.MultipleServicesLatest,
+	}
+	exporter, err := NewPackageRepository([]env.CatalogConfig{source}, "", 0, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var bundle bytes.Buffer
+	require.NoError(t, exporter.ExportBundle(ctx, &bundle, nil))
+
+	target := t.TempDir()
+	imported, err := ImportBundle(ctx, &bundle, ImportOptions{Target: target})
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+	assert.Equal(t, env.CatalogTypeDirectory, imported[0].Type)
+	assert.Equal(t, filepath.Join(target, "ide"), imported[0].Location)
+	assert.Equal(t, map[string]string{"en": "IDE"}, imported[0].Name)
+	assert.Equal(t, env.MultipleServicesAll, imported[0].MultipleServicesMode)
+
+	idx, err := repo.LoadIndexFile(filepath.Join(target, "ide", "index.yaml"))
+	require.NoError(t, err)
+	assert.True(t, idx.Has("app", "2.0.0"))
+	assert.False(t, idx.Has("app", "1.1.0"))
+
+	// The mirror exposes exactly the versions the source exposed.
+	mirror := env.CatalogConfig{
+		ID:       imported[0].ID,
+		Type:     imported[0].Type,
+		Location: imported[0].Location,
+	}
+	mirrorRepo, err := NewPackageRepository([]env.CatalogConfig{mirror}, "", 0, nil)
+	require.NoError(t, err)
+	for _, name := range []string{"app", "unpacked"} {
+		want, err := exporter.GetPackage(ctx, source.ID, name)
+		require.NoError(t, err)
+		got, err := mirrorRepo.GetPackage(ctx, mirror.ID, name)
+		require.NoError(t, err)
+		assert.Equal(t, want.VersionNames(), got.VersionNames(), name)
+	}
+}
+
+func TestBundle_RoundTripKeepsPolicies(t *testing.T) {
+	dir := t.TempDir()
+	save := func(name, version string, deprecated bool) string {
+		t.Helper()
+		archive, err := chartutil.Save(&chartv2.Chart{Metadata: &chartv2.Metadata{
+			APIVersion: chartv2.APIVersionV2,
+			Name:       name,
+			Version:    version,
+			Deprecated: deprecated,
+		}}, dir)
+		require.NoError(t, err)
+		return archive
+	}
+	save("app", "1.0.0", true)
+	signed := save("app", "2.0.0", false)
+	save("internal", "1.0.0", false)
+
+	signer, err := openpgp.NewEntity("Onyxia test", "", "test@onyxia.sh", nil)
+	require.NoError(t, err)
+	keyring := filepath.Join(t.TempDir(), "pubring.gpg")
+	f, err := os.Create(keyring)
+	require.NoError(t, err)
+	require.NoError(t, signer.Serialize(f))
+	require.NoError(t, f.Close())
+	archive, err := os.ReadFile(signed)
+	require.NoError(t, err)
+	prov, err := (&provenance.Signatory{Entity: signer}).
+		ClearSign(archive, filepath.Base(signed), []byte("name: app\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(signed+".prov", []byte(prov), 0o644))
+
+	hidden := false
+	source := env.CatalogConfig{
+		ID:                   "ide",
+		Type:                 env.CatalogTypeDirectory,
+		Location:             dir,
+		Name:                 map[string]string{"en": "IDE"},
+		Status:               env.StatusProd,
+		MultipleServicesMode: env.MultipleServicesAll,
+		Excluded:             []string{"internal"},
+		Restrictions:         []env.Restriction{{UserAttributeKey: "groups", Match: "^dev$"}},
+		PackageRestrictions: []env.PackageRestriction{
+			{Name: "app", Restrictions: []env.Restriction{{Role: "^admin$"}}},
+		},
+		PackageOverrides: []env.PackageOverride{
+			{Name: "app", Description: "Mirrored app", Category: "tools"},
+		},
+		Visible:          env.CatalogVisibility{Project: &hidden},
+		DeprecatedCharts: env.DeprecatedChartsExistingOnly,
+		Verify:           env.VerifyWarn,
+		Keyring:          &keyring,
+	}
+	exporter, err := NewPackageRepository([]env.CatalogConfig{source}, "", 0, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var bundle bytes.Buffer
+	require.NoError(t, exporter.ExportBundle(ctx, &bundle, nil))
+	imported, err := ImportBundle(ctx, &bundle, ImportOptions{Target: t.TempDir()})
+	require.NoError(t, err)
+
+	// ✅ The printed configuration loads back with the source policies.
+	out, err := yaml.Marshal(map[string]any{"catalogs": imported})
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "env.yaml")
+	require.NoError(t, os.WriteFile(file, out, 0o644))
+	loaded, err := configloader.Load[env.Env]([]byte("catalogs: []"), file)
+	require.NoError(t, err)
+	require.NoError(t, env.ValidateCatalogsConfig(loaded.CatalogsConfig))
+	require.Len(t, loaded.CatalogsConfig, 1)
+	mirror := loaded.CatalogsConfig[0]
+	assert.Equal(t, source.Restrictions, mirror.Restrictions)
+	assert.Equal(t, source.PackageRestrictions, mirror.PackageRestrictions)
+	assert.Equal(t, source.PackageOverrides, mirror.PackageOverrides)
+	assert.False(t, mirror.Visible.InProject())
+	assert.True(t, mirror.Visible.InUser())
+	assert.Equal(t, source.Excluded, mirror.Excluded)
+	assert.Equal(t, source.DeprecatedCharts, mirror.DeprecatedCharts)
+	assert.Equal(t, source.Verify, mirror.Verify)
+	assert.Equal(t, keyring, *mirror.Keyring)
+
+	mirrorRepo, err := NewPackageRepository([]env.CatalogConfig{mirror}, "", 0, nil)
+	require.NoError(t, err)
+
+	// ❌ Deprecated versions exported for running services stay uninstallable.
+	_, err = mirrorRepo.ResolvePackage(ctx, mirror.ID, "app", "1.0.0")
+	assert.ErrorIs(t, err, domain.ErrNotInstallable)
+
+	// ✅ Signed versions stay verified on the mirror.
+	resolved, err := mirrorRepo.ResolvePackage(ctx, mirror.ID, "app", "2.0.0")
+	require.NoError(t, err)
+	assert.False(t, resolved.Unverified)
+	assert.Equal(t, keyring, resolved.Keyring)
+
+	// ✅ Overrides still apply on the mirror.
+	ref, err := mirrorRepo.GetPackage(ctx, mirror.ID, "app")
+	require.NoError(t, err)
+	assert.Equal(t, "Mirrored app", ref.Description)
+	assert.Equal(t, "tools", ref.Category)
+
+	// ❌ Excluded charts are not exported.
+	_, err = mirrorRepo.GetPackage(ctx, mirror.ID, "internal")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestImportBundle_RejectsUnexpectedEntries(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	manifest := []byte(`{"format": 1, "catalogs": [{"id": "ide"}]}`)
+	require.NoError(t, writeTarFile(tw, bundleManifestName, manifest, time.Now()))
+	require.NoError(t, writeTarFile(tw, "ide/../../escape.tgz", []byte("x"), time.Now()))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	target := t.TempDir()
+	_, err := ImportBundle(context.Background(), &buf, ImportOptions{Target: target})
+	assert.ErrorContains(t, err, "unexpected bundle entry")
+
+	_, statErr := os.Stat(filepath.Join(filepath.Dir(target), "escape.tgz"))
+	assert.True(t, os.IsNotExist(statErr))
+}
@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// maxCachedCharts bounds the number of pulled charts kept in memory.
+const maxCachedCharts = 32
+
+// chartCache keeps pulled charts so the schema, README and default values of
+// a version share one download. Charts keyed by archive digest are immutable
+// and kept until evicted; those keyed by reference, such as OCI tags, may be
+// pushed again and expire.
+type chartCache struct {
+	mu     sync.Mutex
+	charts map[string]cachedChart
+	order  []string // insertion order, oldest first
+
+	// Concurrent requests for the same chart wait for a single download.
+	inflight singleflight.Group
+}
+
+type cachedChart struct {
+	chart   *chartv2.Chart
+	expires time.Time // zero for immutable charts
+}
+
+// get returns the chart cached under key, loading it when missing or older
+// than ttl. A zero ttl keeps the chart until it is evicted.
+func (c *chartCache) get(
+	key string,
+	ttl time.Duration,
+	load func() (*chartv2.Chart, error),
+) (*chartv2.Chart, error) {
+	c.mu.Lock()
+	cc, ok := c.charts[key]
+	c.mu.Unlock()
+	if ok && (cc.expires.IsZero() || time.Now().Before(cc.expires)) {
+		return cc.chart, nil
+	}
+
+	v, err, _ := c.inflight.Do(key, func() (any, error) {
+		ch, err := load()
+		if err != nil {
+			return nil, err
+		}
+		var expires time.Time
+		if ttl > 0 {
+			expires = time.Now().Add(ttl)
+		}
+		c.put(key, cachedChart{chart: ch, expires: expires})
+		return ch, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*chartv2.Chart), nil
+}
+
+func (c *chartCache) put(key string, cc cachedChart) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.charts == nil {
+		c.charts = make(map[string]cachedChart)
+	}
+	if _, ok := c.charts[key]; ok {
+		c.charts[key] = cc
+		return
+	}
+	if len(c.order) >= maxCachedCharts {
+		delete(c.charts, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.charts[key] = cc
+	c.order = append(c.order, key)
+}
@@ -0,0 +1,39 @@
+package helm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+func TestChartCache_Expiry(t *testing.T) {
+	var c chartCache
+	loads := 0
+	load := func() (*chartv2.Chart, error) {
+		loads++
+		return &chartv2.Chart{}, nil
+	}
+
+	// ✅ Charts keyed by digest are kept.
+	_, err := c.get("sha256:abc", 0, load)
+	require.NoError(t, err)
+	_, err = c.get("sha256:abc", 0, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	// ✅ Charts keyed by tag are pulled again once expired.
+	_, err = c.get("oci://registry.example.com/charts/app:1.0.0", time.Hour, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+	c.put("oci://registry.example.com/charts/app:1.0.0", cachedChart{
+		chart:   &chartv2.Chart{},
+		expires: time.Now().Add(-time.Second),
+	})
+	_, err = c.get("oci://registry.example.com/charts/app:1.0.0", time.Hour, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads)
+	assert.Len(t, c.order, 2)
+}
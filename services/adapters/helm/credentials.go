@@ -0,0 +1,189 @@
+package helm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+)
+
+// credentialsTTL bounds how long credentials read from a Secret or a file are
+// reused, so rotated credentials are picked up without a restart.
+const credentialsTTL = time.Minute
+
+// Keys of the Secret types kubernetes.io/basic-auth and
+// kubernetes.io/dockerconfigjson.
+const (
+	secretUsernameKey     = "username"
+	secretPasswordKey     = "password"
+	secretDockerConfigKey = ".dockerconfigjson"
+)
+
+type cachedCredentials struct {
+	creds     domain.Credentials
+	fetchedAt time.Time
+}
+
+type credentialStore struct {
+	secrets ports.CatalogSecretReader
+
+	mu      sync.Mutex
+	entries map[string]cachedCredentials
+}
+
+// credentials returns the credentials of a catalog, either inline or read
+// from the referenced source. When the source cannot be read, the last
+// credentials read are used until it can.
+func (h *HelmPackageRepository) credentials(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+) (domain.Credentials, error) {
+	if cfg.Credentials == nil {
+		return domain.Credentials{
+			Username: tools.Deref(cfg.Username),
+			Password: tools.Deref(cfg.Password),
+		}, nil
+	}
+
+	s := &h.creds
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	cached, ok := s.entries[cfg.ID]
+	if ok && now.Sub(cached.fetchedAt) < credentialsTTL {
+		return cached.creds, nil
+	}
+
+	creds, err := readCredentials(ctx, s.secrets, cfg)
+	if err != nil {
+		if ok {
+			slog.WarnContext(ctx, "Using previously read catalog credentials",
+				slog.String("catalog", cfg.ID),
+				slog.Any("error", err),
+			)
+			return cached.creds, nil
+		}
+		return domain.Credentials{}, fmt.Errorf("catalog %q: reading credentials: %w", cfg.ID, err)
+	}
+
+	if s.entries == nil {
+		s.entries = make(map[string]cachedCredentials)
+	}
+	s.entries[cfg.ID] = cachedCredentials{creds: creds, fetchedAt: now}
+	return creds, nil
+}
+
+func readCredentials(
+	ctx context.Context,
+	secrets ports.CatalogSecretReader,
+	cfg env.CatalogConfig,
+) (domain.Credentials, error) {
+	ref := cfg.Credentials
+
+	switch {
+	case ref.Secret != nil:
+		if secrets == nil {
+			return domain.Credentials{}, errors.New("no secret reader configured")
+		}
+		data, err := secrets.ReadSecret(ctx, ref.Secret.Namespace, ref.Secret.Name)
+		if err != nil {
+			return domain.Credentials{}, err
+		}
+		if dockerConfig, ok := data[secretDockerConfigKey]; ok {
+			return dockerConfigCredentials(dockerConfig, cfg.Location)
+		}
+		username, okUser := data[secretUsernameKey]
+		password, okPass := data[secretPasswordKey]
+		if !okUser || !okPass {
+			return domain.Credentials{}, fmt.Errorf(
+				"secret %s/%s has neither %s/%s nor %s keys",
+				ref.Secret.Namespace, ref.Secret.Name,
+				secretUsernameKey, secretPasswordKey, secretDockerConfigKey,
+			)
+		}
+		return domain.Credentials{Username: string(username), Password: string(password)}, nil
+
+	case ref.DockerConfigFile != "":
+		data, err := os.ReadFile(ref.DockerConfigFile)
+		if err != nil {
+			return domain.Credentials{}, err
+		}
+		return dockerConfigCredentials(data, cfg.Location)
+
+	default:
+		username, err := os.ReadFile(ref.UsernameFile)
+		if err != nil {
+			return domain.Credentials{}, err
+		}
+		password, err := os.ReadFile(ref.PasswordFile)
+		if err != nil {
+			return domain.Credentials{}, err
+		}
+		// Mounted files often end with a newline that is not part of the value.
+		return domain.Credentials{
+			Username: strings.TrimRight(string(username), "\r\n"),
+			Password: strings.TrimRight(string(password), "\r\n"),
+		}, nil
+	}
+}
+
+// dockerConfig is the subset of a docker config.json holding credentials.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"` // base64 of "username:password"
+	} `json:"auths"`
+}
+
+// dockerConfigCredentials picks the entry of a docker config matching the
+// host of location.
+func dockerConfigCredentials(data []byte, location string) (domain.Credentials, error) {
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return domain.Credentials{}, fmt.Errorf("parsing docker config: %w", err)
+	}
+
+	host := registryHost(location)
+	for key, entry := range cfg.Auths {
+		if registryHost(key) != host {
+			continue
+		}
+		if entry.Auth == "" {
+			return domain.Credentials{Username: entry.Username, Password: entry.Password}, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return domain.Credentials{}, fmt.Errorf("docker config entry %q: invalid auth: %w", key, err)
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return domain.Credentials{}, fmt.Errorf("docker config entry %q: invalid auth", key)
+		}
+		return domain.Credentials{Username: username, Password: password}, nil
+	}
+	return domain.Credentials{}, fmt.Errorf("docker config has no entry for %q", host)
+}
+
+// registryHost returns the host of a repository location or docker config
+// key, which may or may not carry a scheme.
+func registryHost(location string) string {
+	if u, err := url.Parse(location); err == nil && u.Host != "" {
+		return u.Host
+	}
+	host, _, _ := strings.Cut(location, "/")
+	return host
+}
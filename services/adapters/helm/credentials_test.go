@@ -0,0 +1,241 @@
+package helm
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+type fakeSecretReader struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	err  error
+}
+
+func (f *fakeSecretReader) ReadSecret(_ context.Context, _, _ string) (map[string][]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data, f.err
+}
+
+func (f *fakeSecretReader) set(data map[string][]byte, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data, f.err = data, err
+}
+
+func secretCatalog(location string) env.CatalogConfig {
+	return env.CatalogConfig{
+		ID:       "private",
+		Type:     env.CatalogTypeHelmRepo,
+		Location: location,
+		Credentials: &env.CredentialsRef{
+			Secret: &env.SecretRef{Namespace: "onyxia", Name: "catalog-creds"},
+		},
+	}
+}
+
+func TestDockerConfigCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cr:et"))
+	data := []byte(`{"auths": {
+		"https://index.docker.io/v1/": {"auth": "` + auth + `"},
+		"registry.example.org": {"username": "alice", "password": "pw"},
+		"ghcr.io": {"auth": "` + auth + `"}
+	}}`)
+
+	creds, err := dockerConfigCredentials(data, "oci://registry.example.org/charts")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Credentials{Username: "alice", Password: "pw"}, creds)
+
+	creds, err = dockerConfigCredentials(data, "oci://ghcr.io/org/charts")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Credentials{Username: "robot", Password: "s3cr:et"}, creds)
+
+	creds, err = dockerConfigCredentials(data, "https://index.docker.io/charts")
+	require.NoError(t, err)
+	assert.Equal(t, "robot", creds.Username)
+
+	_, err = dockerConfigCredentials(data, "https://charts.other.org")
+	assert.ErrorContains(t, err, "no entry")
+}
+
+func TestCredentials_Sources(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("inline", func(t *testing.T) {
+		user, pass := "bob", "pw"
+		cfg := env.CatalogConfig{ID: "inline", Username: &user, Password: &pass}
+		h, err := NewPackageRepository(nil, "", 0, nil)
+		require.NoError(t, err)
+
+		creds, err := h.credentials(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Credentials{Username: "bob", Password: "pw"}, creds)
+	})
+
+	t.Run("basic auth secret", func(t *testing.T) {
+		secrets := &fakeSecretReader{data: map[string][]byte{
+			"username": []byte("bob"),
+			"password": []byte("pw"),
+		}}
+		h, err := NewPackageRepository(nil, "", 0, secrets)
+		require.NoError(t, err)
+
+		creds, err := h.credentials(ctx, secretCatalog("https://charts.example.org"))
+		require.NoError(t, err)
+		assert.Equal(t, domain.Credentials{Username: "bob", Password: "pw"}, creds)
+	})
+
+	t.Run("dockerconfigjson secret", func(t *testing.T) {
+		secrets := &fakeSecretReader{data: map[string][]byte{
+			".dockerconfigjson": []byte(
+				`{"auths": {"registry.example.org": {"username": "alice", "password": "pw"}}}`,
+			),
+		}}
+		h, err := NewPackageRepository(nil, "", 0, secrets)
+		require.NoError(t, err)
+
+		creds, err := h.credentials(ctx, secretCatalog("oci://registry.example.org/charts"))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", creds.Username)
+	})
+
+	t.Run("mounted files", func(t *testing.T) {
+		dir := t.TempDir()
+		userFile := filepath.Join(dir, "username")
+		passFile := filepath.Join(dir, "password")
+		require.NoError(t, os.WriteFile(userFile, []byte("bob\n"), 0o600))
+		require.NoError(t, os.WriteFile(passFile, []byte("pw\n"), 0o600))
+
+		cfg := env.CatalogConfig{
+			ID: "files",
+			Credentials: &env.CredentialsRef{
+				UsernameFile: userFile,
+				PasswordFile: passFile,
+			},
+		}
+		h, err := NewPackageRepository(nil, "", 0, nil)
+		require.NoError(t, err)
+
+		creds, err := h.credentials(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Credentials{Username: "bob", Password: "pw"}, creds)
+	})
+
+	t.Run("secret without credentials", func(t *testing.T) {
+		secrets := &fakeSecretReader{data: map[string][]byte{"token": []byte("x")}}
+		h, err := NewPackageRepository(nil, "", 0, secrets)
+		require.NoError(t, err)
+
+		_, err = h.credentials(ctx, secretCatalog("https://charts.example.org"))
+		assert.ErrorContains(t, err, "catalog-creds")
+	})
+}
+
+func TestCredentials_Rotation(t *testing.T) {
+	ctx := context.Background()
+	secrets := &fakeSecretReader{data: map[string][]byte{
+		"username": []byte("bob"),
+		"password": []byte("old"),
+	}}
+	h, err := NewPackageRepository(nil, "", 0, secrets)
+	require.NoError(t, err)
+	cfg := secretCatalog("https://charts.example.org")
+
+	creds, err := h.credentials(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "old", creds.Password)
+
+	// Rotated credentials are used once the cached ones expire.
+	secrets.set(map[string][]byte{
+		"username": []byte("bob"),
+		"password": []byte("new"),
+	}, nil)
+	creds, err = h.credentials(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "old", creds.Password)
+
+	expireCredentials(h, cfg.ID)
+	creds, err = h.credentials(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "new", creds.Password)
+
+	// An unreadable Secret keeps the last credentials read.
+	secrets.set(nil, errors.New("apiserver unavailable"))
+	expireCredentials(h, cfg.ID)
+	creds, err = h.credentials(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "new", creds.Password)
+}
+
+func expireCredentials(h *HelmPackageRepository, catalogID string) {
+	h.creds.mu.Lock()
+	defer h.creds.mu.Unlock()
+	entry := h.creds.entries[catalogID]
+	entry.fetchedAt = time.Now().Add(-2 * credentialsTTL)
+	h.creds.entries[catalogID] = entry
+}
+
+func TestCredentials_AppliedToIndexAndResolve(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	tmp := t.TempDir()
+	idx := repo.NewIndexFile()
+	require.NoError(t, idx.MustAdd(
+		&chartv2.Metadata{Name: "mychart", Version: "1.0.0"},
+		"mychart-1.0.0.tgz", "http://localhost", "",
+	))
+	require.NoError(t, idx.WriteFile(filepath.Join(tmp, "index.yaml"), 0o644))
+
+	files := http.FileServer(http.Dir(tmp))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "bob" || pass != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	secrets := &fakeSecretReader{data: map[string][]byte{
+		"username": []byte("bob"),
+		"password": []byte("wrong"),
+	}}
+	cfg := secretCatalog(server.URL)
+	h, err := NewPackageRepository([]env.CatalogConfig{cfg}, tmp, 0, secrets)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = h.ListPackages(ctx, cfg.ID)
+	require.Error(t, err)
+
+	secrets.set(map[string][]byte{
+		"username": []byte("bob"),
+		"password": []byte("pw"),
+	}, nil)
+	expireCredentials(h, cfg.ID)
+
+	pkgs, err := h.ListPackages(ctx, cfg.ID)
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 1)
+
+	pv, err := h.ResolvePackage(ctx, cfg.ID, "mychart", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, domain.Credentials{Username: "bob", Password: "pw"}, pv.Credentials)
+}
@@ -0,0 +1,125 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// chartDeprecated reports whether a chart is deprecated. Following Helm, a
+// chart is deprecated when its newest version is. Index entries are sorted
+// newest-first when the index is loaded.
+func chartDeprecated(versions repo.ChartVersions) bool {
+	return len(versions) > 0 && versions[0].Deprecated
+}
+
+// listable reports whether a chart appears in the catalog listing.
+func listable(mode env.DeprecatedChartsMode, versions repo.ChartVersions) bool {
+	return !chartDeprecated(versions) || showsDeprecated(mode)
+}
+
+// showsDeprecated reports whether mode lists deprecated charts.
+func showsDeprecated(mode env.DeprecatedChartsMode) bool {
+	return mode == "" || mode == env.DeprecatedChartsShow
+}
+
+// usableVersions drops the deprecated versions when mode hides them.
+func usableVersions(mode env.DeprecatedChartsMode, versions repo.ChartVersions) repo.ChartVersions {
+	if mode != env.DeprecatedChartsHide {
+		return versions
+	}
+	out := make(repo.ChartVersions, 0, len(versions))
+	for _, v := range versions {
+		if !v.Deprecated {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// ociChartDeprecated reports whether an OCI chart is deprecated, that is
+// whether its newest version is. A chart that cannot be pulled is reported
+// as not deprecated, the way listing does not depend on pulls otherwise.
+func (h *HelmPackageRepository) ociChartDeprecated(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	pkg env.OCIPackage,
+) bool {
+	versions, _ := parseVersions(pkg.Versions)
+	if len(versions) == 0 {
+		return false
+	}
+	deprecated, err := h.ociVersionDeprecated(ctx, cfg, pkg.Name, versions[0].Original())
+	if err != nil {
+		slog.WarnContext(ctx, "Unable to read OCI chart deprecation",
+			slog.String("catalog", cfg.ID),
+			slog.String("package", pkg.Name),
+			slog.Any("error", err),
+		)
+		return false
+	}
+	return deprecated
+}
+
+// ociVersionDeprecated reports whether an OCI chart version is deprecated,
+// from its metadata.
+func (h *HelmPackageRepository) ociVersionDeprecated(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
+) (bool, error) {
+	ch, err := h.loadChart(ctx, cfg, name, version)
+	if err != nil {
+		return false, err
+	}
+	return ch.Metadata != nil && ch.Metadata.Deprecated, nil
+}
+
+// checkOCIInstallable is checkInstallable for OCI catalogs. OCI catalogs have
+// no index carrying the deprecation flag, so the chart is only pulled when the
+// catalog mode restricts deprecated charts.
+func (h *HelmPackageRepository) checkOCIInstallable(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
+) error {
+	if showsDeprecated(cfg.DeprecatedCharts) {
+		return nil
+	}
+	deprecated, err := h.ociVersionDeprecated(ctx, cfg, name, version)
+	if err != nil {
+		return fmt.Errorf("reading chart %q version %q: %w", name, version, err)
+	}
+	if !deprecated {
+		i := slices.IndexFunc(cfg.Packages, func(p env.OCIPackage) bool { return p.Name == name })
+		deprecated = i >= 0 && h.ociChartDeprecated(ctx, cfg, cfg.Packages[i])
+	}
+	if deprecated {
+		return checkInstallable(cfg, name, version)
+	}
+	return nil
+}
+
+// checkInstallable fails when the catalog mode forbids installing a
+// deprecated chart version.
+func checkInstallable(cfg env.CatalogConfig, name, version string) error {
+	switch cfg.DeprecatedCharts {
+	case env.DeprecatedChartsHide:
+		return fmt.Errorf(
+			"%w: version %q not found for chart %q in catalog %q",
+			domain.ErrNotFound, version, name, cfg.ID,
+		)
+	case env.DeprecatedChartsExistingOnly:
+		return fmt.Errorf(
+			"%w: chart %q version %q is deprecated in catalog %q",
+			domain.ErrNotInstallable, name, version, cfg.ID,
+		)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// indexChartDirectory builds an in-memory index of the charts found directly
+// under dir: packaged charts (*.tgz) and unpacked chart directories.
+// Chart URLs are absolute paths, so Helm can install them without a repository.
+// Entries that are not charts are skipped.
+func indexChartDirectory(dir string) (*repo.IndexFile, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving chart directory %q: %w", dir, err)
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading chart directory: %w", err)
+	}
+
+	idx := repo.NewIndexFile()
+	for _, e := range entries {
+		path := filepath.Join(root, e.Name())
+
+		var (
+			ch     *chartv2.Chart
+			digest string
+		)
+		switch {
+		case e.IsDir():
+			if _, err := os.Stat(filepath.Join(path, "Chart.yaml")); err != nil {
+				continue
+			}
+			if ch, err = loader.LoadDir(path); err != nil {
+				continue
+			}
+		case strings.HasSuffix(e.Name(), ".tgz"):
+			if ch, err = loader.LoadFile(path); err != nil {
+				continue
+			}
+			if digest, err = provenance.DigestFile(path); err != nil {
+				return nil, fmt.Errorf("hashing %s: %w", e.Name(), err)
+			}
+		default:
+			continue
+		}
+
+		if err := idx.MustAdd(ch.Metadata, path, "", digest); err != nil {
+			return nil, fmt.Errorf("indexing %s: %w", e.Name(), err)
+		}
+		if info, err := e.Info(); err == nil {
+			versions := idx.Entries[ch.Metadata.Name]
+			versions[len(versions)-1].Created = info.ModTime()
+		}
+	}
+
+	idx.SortEntries()
+	return idx, nil
+}
@@ -0,0 +1,84 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+)
+
+var _ ports.CatalogMonitor = (*HelmPackageRepository)(nil)
+
+func (h *HelmPackageRepository) CatalogHealth(
+	ctx context.Context,
+	catalogID string,
+) (domain.CatalogHealth, error) {
+	cfg, ok := h.catalogs[catalogID]
+	if !ok {
+		return domain.CatalogHealth{}, fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, catalogID)
+	}
+
+	cache, ok := h.indexes[catalogID]
+	if !ok {
+		// OCI catalogs list their packages in the configuration.
+		return domain.CatalogHealth{
+			CatalogID: cfg.ID,
+			Type:      string(cfg.Type),
+			Packages:  len(cfg.Packages),
+		}, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return indexHealth(cfg, cache), nil
+}
+
+// RefreshCatalog downloads the index of a catalog again, whether or not the
+// cached one is still fresh. A failed refresh is reported in the returned
+// health, the previous index is kept.
+func (h *HelmPackageRepository) RefreshCatalog(
+	ctx context.Context,
+	catalogID string,
+) (domain.CatalogHealth, error) {
+	cfg, ok := h.catalogs[catalogID]
+	if !ok {
+		return domain.CatalogHealth{}, fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, catalogID)
+	}
+
+	cache, ok := h.indexes[catalogID]
+	if !ok {
+		return h.CatalogHealth(ctx, catalogID)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if _, err := h.refreshIndex(ctx, cfg, cache); err != nil {
+		slog.WarnContext(ctx, "Manual catalog refresh failed",
+			slog.String("catalog", catalogID),
+			slog.Any("error", err),
+		)
+	}
+	return indexHealth(cfg, cache), nil
+}
+
+// indexHealth reports the state of cache. The caller holds cache.mu.
+func indexHealth(cfg env.CatalogConfig, cache *cachedIndex) domain.CatalogHealth {
+	health := domain.CatalogHealth{
+		CatalogID:      cfg.ID,
+		Type:           string(cfg.Type),
+		LastRefresh:    cache.fetchedAt,
+		LastErrorAt:    cache.lastErrAt,
+		RefreshLatency: cache.latency,
+	}
+	if cache.idx != nil {
+		health.Packages = len(cache.idx.Entries)
+	}
+	if cache.lastErr != nil {
+		health.LastError = cache.lastErr.Error()
+	}
+	return health
+}
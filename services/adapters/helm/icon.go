@@ -0,0 +1,233 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+)
+
+// maxIconSize bounds the size of an upstream icon.
+const maxIconSize = 256 << 10
+
+// iconContentTypes are the image types served as package icons.
+var iconContentTypes = map[string]bool{
+	"image/png":     true,
+	"image/jpeg":    true,
+	"image/gif":     true,
+	"image/webp":    true,
+	"image/svg+xml": true,
+	"image/x-icon":  true,
+}
+
+// cachedIcon is the outcome of fetching an icon, errors included, so a
+// broken upstream is not hit on every request.
+type cachedIcon struct {
+	icon      domain.Icon
+	err       error
+	fetchedAt time.Time
+}
+
+type iconCache struct {
+	mu    sync.Mutex
+	icons map[string]cachedIcon
+}
+
+func (c *iconCache) get(key string, ttl time.Duration, now time.Time) (cachedIcon, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ci, ok := c.icons[key]
+	if !ok || ttl <= 0 || now.Sub(ci.fetchedAt) >= ttl {
+		return cachedIcon{}, false
+	}
+	return ci, true
+}
+
+func (c *iconCache) put(key string, ci cachedIcon) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.icons == nil {
+		c.icons = make(map[string]cachedIcon)
+	}
+	c.icons[key] = ci
+}
+
+func (h *HelmPackageRepository) GetPackageIcon(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+) (domain.Icon, error) {
+	key := catalogID + "|" + packageName
+	if ci, ok := h.icons.get(key, h.indexTTL, time.Now()); ok {
+		return ci.icon, ci.err
+	}
+
+	iconURL, err := h.iconURL(ctx, catalogID, packageName)
+	if err != nil {
+		return domain.Icon{}, err
+	}
+
+	var icon domain.Icon
+	if iconURL != "" {
+		icon, err = fetchIcon(ctx, h.iconClient, iconURL)
+	}
+	h.icons.put(key, cachedIcon{icon: icon, err: err, fetchedAt: time.Now()})
+	return icon, err
+}
+
+// iconURL returns the icon URL of a package from the catalog listing, which
+// comes from the cached index of Helm catalogs. OCI catalogs list no metadata,
+// so the newest shown chart version is pulled, once thanks to the chart cache.
+func (h *HelmPackageRepository) iconURL(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+) (string, error) {
+	cfg, ok := h.catalogs[catalogID]
+	if !ok {
+		return "", fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, catalogID)
+	}
+
+	pkgs, err := h.ListPackages(ctx, catalogID)
+	if err != nil {
+		return "", err
+	}
+	i := slices.IndexFunc(pkgs, func(p domain.Package) bool { return p.Name == packageName })
+	if i < 0 {
+		return "", fmt.Errorf(
+			"%w: package %q not found in catalog %q",
+			domain.ErrNotFound, packageName, catalogID,
+		)
+	}
+	if u := pkgs[i].IconUrl.String(); u != "" || cfg.Type != env.CatalogTypeOCI {
+		return u, nil
+	}
+
+	j := slices.IndexFunc(cfg.Packages, func(p env.OCIPackage) bool { return p.Name == packageName })
+	shown := shownVersions(cfg, packageName, cfg.Packages[j].Versions)
+	versions := h.visibleVersions(ctx, cfg, packageName, shown)
+	if len(versions) == 0 {
+		return "", nil
+	}
+	ch, err := h.loadChart(ctx, cfg, packageName, versions[0])
+	if err != nil {
+		return "", err
+	}
+	if ch.Metadata == nil {
+		return "", nil
+	}
+	return ch.Metadata.Icon, nil
+}
+
+// newIconClient returns the client fetching icons. Icon URLs come from chart
+// metadata, which anyone publishing a chart controls, so the client only
+// connects to public addresses, directly, and does not follow redirects to
+// other hosts.
+func newIconClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:       10 * time.Second,
+		Transport:     transport,
+		CheckRedirect: sameHostRedirect,
+	}
+}
+
+// dialPublicOnly refuses connections to loopback, link-local, private and
+// other non-public addresses. It runs after name resolution, so host names
+// resolving to such addresses are refused too.
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("icon host address %s is not public", ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, not covered by IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// sameHostRedirect follows redirects within the host of the icon URL only.
+func sameHostRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 5 {
+		return errors.New("too many icon redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("icon redirect to another host %q", req.URL.Host)
+	}
+	return nil
+}
+
+// fetchIcon downloads an icon over HTTP(S) and checks its size and type.
+// Catalog credentials are never sent: icons are often hosted elsewhere.
+func fetchIcon(ctx context.Context, client *http.Client, iconURL string) (domain.Icon, error) {
+	if !strings.HasPrefix(iconURL, "https://") && !strings.HasPrefix(iconURL, "http://") {
+		return domain.Icon{}, fmt.Errorf("unsupported icon URL %q", iconURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
+	if err != nil {
+		return domain.Icon{}, fmt.Errorf("invalid icon URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return domain.Icon{}, fmt.Errorf("fetching icon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.Icon{}, fmt.Errorf("fetching icon: unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return domain.Icon{}, fmt.Errorf("reading icon: %w", err)
+	}
+	if len(data) > maxIconSize {
+		return domain.Icon{}, fmt.Errorf("icon is larger than %d bytes", maxIconSize)
+	}
+
+	contentType, err := iconContentType(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return domain.Icon{}, err
+	}
+	return domain.Icon{ContentType: contentType, Data: data}, nil
+}
+
+// iconContentType returns the image type of data. The declared type is
+// trusted only when sniffing cannot tell, which is the case for SVG, and
+// only if it is SVG: the controller serves icons in a sandbox.
+func iconContentType(declared string, data []byte) (string, error) {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if iconContentTypes[sniffed] {
+		return sniffed, nil
+	}
+
+	declared, _, _ = mime.ParseMediaType(declared)
+	if (sniffed == "text/xml" || sniffed == "text/plain") && declared == "image/svg+xml" {
+		return declared, nil
+	}
+	return "", fmt.Errorf("unsupported icon type %q", sniffed)
+}
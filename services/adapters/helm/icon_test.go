@@ -0,0 +1,147 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func iconServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	hits := new(atomic.Int32)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/icon.png", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write(pngHeader)
+	})
+	mux.HandleFunc("/icon.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`))
+	})
+	mux.HandleFunc("/undeclared.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body>not an icon</body></html>"))
+	})
+	mux.HandleFunc("/huge.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(append(pngHeader, bytes.Repeat([]byte{0}, maxIconSize)...))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, hits
+}
+
+func TestFetchIcon(t *testing.T) {
+	srv, _ := iconServer(t)
+	ctx := context.Background()
+
+	icon, err := fetchIcon(ctx, srv.Client(), srv.URL+"/icon.png")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", icon.ContentType)
+	assert.Equal(t, pngHeader, icon.Data)
+
+	icon, err = fetchIcon(ctx, srv.Client(), srv.URL+"/icon.svg")
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", icon.ContentType)
+
+	_, err = fetchIcon(ctx, srv.Client(), srv.URL+"/undeclared.svg")
+	assert.ErrorContains(t, err, "unsupported icon type")
+
+	_, err = fetchIcon(ctx, srv.Client(), srv.URL+"/page.html")
+	assert.ErrorContains(t, err, "unsupported icon type")
+
+	_, err = fetchIcon(ctx, srv.Client(), srv.URL+"/huge.png")
+	assert.ErrorContains(t, err, "larger than")
+
+	_, err = fetchIcon(ctx, srv.Client(), srv.URL+"/missing.png")
+	assert.ErrorContains(t, err, "unexpected status")
+
+	_, err = fetchIcon(ctx, srv.Client(), "file:///etc/passwd")
+	assert.ErrorContains(t, err, "unsupported icon URL")
+}
+
+func TestGetPackageIcon_IsCached(t *testing.T) {
+	srv, hits := iconServer(t)
+	dir := t.TempDir()
+
+	for name, icon := range map[string]string{"with-icon": srv.URL + "/icon.png", "no-icon": ""} {
+		require.NoError(t, chartutil.SaveDir(&chartv2.Chart{Metadata: &chartv2.Metadata{
+			APIVersion: chartv2.APIVersionV2,
+			Name:       name,
+			Version:    "1.0.0",
+			Icon:       icon,
+		}}, dir))
+	}
+
+	cfg := env.CatalogConfig{ID: "local", Type: env.CatalogTypeDirectory, Location: dir}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", time.Hour, nil)
+	require.NoError(t, err)
+	repoAdapter.iconClient = srv.Client()
+	ctx := context.Background()
+
+	for range 3 {
+		icon, err := repoAdapter.GetPackageIcon(ctx, cfg.ID, "with-icon")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", icon.ContentType)
+	}
+	assert.Equal(t, int32(1), hits.Load())
+
+	icon, err := repoAdapter.GetPackageIcon(ctx, cfg.ID, "no-icon")
+	require.NoError(t, err)
+	assert.Empty(t, icon.Data)
+
+	_, err = repoAdapter.GetPackageIcon(ctx, cfg.ID, "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestIconClient_RefusesInternalHosts(t *testing.T) {
+	srv, hits := iconServer(t)
+
+	_, err := fetchIcon(context.Background(), newIconClient(), srv.URL+"/icon.png")
+	assert.ErrorContains(t, err, "is not public")
+	assert.Zero(t, hits.Load())
+
+	for _, addr := range []string{"10.0.0.1:80", "169.254.169.254:80", "[::1]:443", "100.64.0.1:80"} {
+		assert.Error(t, dialPublicOnly("tcp", addr, nil), addr)
+	}
+	assert.NoError(t, dialPublicOnly("tcp", "192.0.2.1:443", nil))
+}
+
+func TestIconClient_RefusesRedirectsToOtherHosts(t *testing.T) {
+	srv, _ := iconServer(t)
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		case "/elsewhere":
+			http.Redirect(w, r, srv.URL+"/icon.png", http.StatusFound)
+		default:
+			http.Redirect(w, r, srv.URL+"/icon.png", http.StatusFound)
+		}
+	}))
+	t.Cleanup(redirect.Close)
+
+	client := redirect.Client()
+	client.CheckRedirect = sameHostRedirect
+
+	_, err := fetchIcon(context.Background(), client, redirect.URL+"/other")
+	assert.ErrorContains(t, err, "redirect to another host")
+
+	_, err = fetchIcon(context.Background(), client, redirect.URL+"/same")
+	assert.ErrorContains(t, err, "redirect to another host")
+}
@@ -0,0 +1,35 @@
+package helm
+
+import (
+	"sync"
+	"time"
+
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// cachedIndex holds the last index downloaded for a Helm catalog.
+// Each catalog has its own lock so a slow repository does not block the others.
+type cachedIndex struct {
+	mu        sync.Mutex
+	idx       *repo.IndexFile
+	fetchedAt time.Time
+	revision  string // see indexRevision
+
+	// Outcome of refreshes, for health reporting.
+	lastErr   error
+	lastErrAt time.Time
+	latency   time.Duration
+}
+
+// fresh reports whether the cached index can be served without downloading
+// it again. A zero ttl disables caching.
+func (c *cachedIndex) fresh(ttl time.Duration, now time.Time) bool {
+	return c.idx != nil && ttl > 0 && now.Sub(c.fetchedAt) < ttl
+}
+
+// backingOff reports whether the repository should not be tried again,
+// because the last refresh failed less than ttl ago. The stale index is served
+// meanwhile, or the last error when there is none.
+func (c *cachedIndex) backingOff(ttl time.Duration, now time.Time) bool {
+	return c.lastErr != nil && c.lastErrAt.After(c.fetchedAt) && now.Sub(c.lastErrAt) < ttl
+}
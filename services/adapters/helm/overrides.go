@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// packageOverride returns the override of a package, if the catalog has one.
+func packageOverride(cfg env.CatalogConfig, name string) (env.PackageOverride, bool) {
+	for _, o := range cfg.PackageOverrides {
+		if o.Name == name {
+			return o, true
+		}
+	}
+	return env.PackageOverride{}, false
+}
+
+// applyOverride replaces the metadata of pkg set by its override.
+func applyOverride(cfg env.CatalogConfig, pkg *domain.Package) {
+	o, ok := packageOverride(cfg, pkg.Name)
+	if !ok {
+		return
+	}
+	if o.Description != "" {
+		pkg.Description = o.Description
+	}
+	if o.Icon != "" {
+		pkg.IconUrl = tools.MustParseURL(o.Icon)
+	}
+	if o.Category != "" {
+		pkg.Category = o.Category
+	}
+}
+
+// shownVersions drops the versions the override of a package hides.
+func shownVersions(cfg env.CatalogConfig, name string, versions []string) []string {
+	o, ok := packageOverride(cfg, name)
+	if !ok || len(o.HiddenVersions) == 0 {
+		return versions
+	}
+	return slices.DeleteFunc(slices.Clone(versions), func(v string) bool {
+		return slices.Contains(o.HiddenVersions, v)
+	})
+}
+
+// shownChartVersions is shownVersions for index entries.
+func shownChartVersions(cfg env.CatalogConfig, name string, versions repo.ChartVersions) repo.ChartVersions {
+	o, ok := packageOverride(cfg, name)
+	if !ok || len(o.HiddenVersions) == 0 {
+		return versions
+	}
+	return slices.DeleteFunc(slices.Clone(versions), func(v *repo.ChartVersion) bool {
+		return slices.Contains(o.HiddenVersions, v.Version)
+	})
+}
+
+// reportUnknownOverrides warns about overrides naming packages that are not
+// in the index of a catalog, in a single message. It runs on the first load
+// of the index, at startup; OCI catalogs are checked when the configuration
+// is loaded.
+func reportUnknownOverrides(ctx context.Context, cfg env.CatalogConfig, idx *repo.IndexFile) {
+	var unknown []string
+	for _, o := range cfg.PackageOverrides {
+		if _, ok := idx.Entries[o.Name]; !ok {
+			unknown = append(unknown, o.Name)
+		}
+	}
+	if len(unknown) > 0 {
+		slog.WarnContext(ctx, "Package overrides name unknown packages",
+			slog.String("catalog", cfg.ID),
+			slog.Any("packages", unknown),
+		)
+	}
+}
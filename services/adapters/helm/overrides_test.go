@@ -0,0 +1,119 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+func TestPackageOverrides_DirectoryCatalog(t *testing.T) {
+	dir := t.TempDir()
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		_, err := chartutil.Save(&chartv2.Chart{Metadata: &chartv2.Metadata{
+			APIVersion:  chartv2.APIVersionV2,
+			Name:        "jupyter",
+			Version:     version,
+			Description: "Upstream description",
+			Icon:        "https://upstream.example.org/icon.png",
+		}}, dir)
+		require.NoError(t, err)
+	}
+	_, err := chartutil.Save(&chartv2.Chart{Metadata: &chartv2.Metadata{
+		APIVersion: chartv2.APIVersionV2,
+		Name:       "untouched",
+		Version:    "1.0.0",
+	}}, dir)
+	require.NoError(t, err)
+
+	cfg := env.CatalogConfig{
+		ID:       "local",
+		Type:     env.CatalogTypeDirectory,
+		Location: dir,
+		PackageOverrides: []env.PackageOverride{
+			{
+				Name:           "jupyter",
+				Icon:           "https://mirror.example.org/jupyter.png",
+				Description:    "Python notebooks",
+				Category:       "notebooks",
+				HiddenVersions: []string{"2.0.0", "1.0.0"},
+			},
+			{Name: "unknown"},
+		},
+	}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	pkgs, err := repoAdapter.ListPackages(ctx, cfg.ID)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 2)
+	for _, p := range pkgs {
+		switch p.Name {
+		case "jupyter":
+			assert.Equal(t, "Python notebooks", p.Description)
+			assert.Equal(t, "https://mirror.example.org/jupyter.png", p.IconUrl.String())
+			assert.Equal(t, "notebooks", p.Category)
+		case "untouched":
+			assert.Empty(t, p.Category)
+		}
+	}
+
+	pkg, err := repoAdapter.GetPackage(ctx, cfg.ID, "jupyter")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.1.0"}, pkg.VersionNames())
+	assert.Equal(t, "Python notebooks", pkg.Description)
+	assert.Equal(t, "notebooks", pkg.Category)
+
+	// Hidden versions stay installable.
+	_, err = repoAdapter.ResolvePackage(ctx, cfg.ID, "jupyter", "2.0.0")
+	assert.NoError(t, err)
+}
+
+func TestPackageOverrides_OCICatalog(t *testing.T) {
+	cfg := env.CatalogConfig{
+		ID:       "oci",
+		Type:     env.CatalogTypeOCI,
+		Location: "oci://registry.example.org/charts",
+		Packages: []env.OCIPackage{{Name: "postgres", Versions: []string{"1.0.0"}}},
+		PackageOverrides: []env.PackageOverride{
+			{Name: "postgres", Description: "Relational database", Category: "databases"},
+		},
+	}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+	require.NoError(t, err)
+
+	pkgs, err := repoAdapter.ListPackages(context.Background(), cfg.ID)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	assert.Equal(t, "Relational database", pkgs[0].Description)
+	assert.Equal(t, "databases", pkgs[0].Category)
+}
+
+func TestValidatePackageOverrides(t *testing.T) {
+	cfg := env.CatalogConfig{
+		ID:                   "oci",
+		Type:                 env.CatalogTypeOCI,
+		Name:                 map[string]string{"en": "OCI"},
+		Status:               env.StatusProd,
+		MultipleServicesMode: env.MultipleServicesAll,
+		Location:             "oci://registry.example.org/charts",
+		Packages:             []env.OCIPackage{{Name: "postgres"}},
+	}
+
+	cfg.PackageOverrides = []env.PackageOverride{{Name: "postgres", Category: "databases"}}
+	assert.NoError(t, env.ValidateCatalogConfig(cfg))
+
+	cfg.PackageOverrides = []env.PackageOverride{{Name: "mysql"}}
+	assert.ErrorContains(t, env.ValidateCatalogConfig(cfg), `unknown package "mysql"`)
+
+	cfg.PackageOverrides = []env.PackageOverride{{Name: "postgres", Icon: "ftp://example.org/icon.png"}}
+	assert.ErrorContains(t, env.ValidateCatalogConfig(cfg), "icon")
+
+	cfg.PackageOverrides = []env.PackageOverride{{Name: "postgres"}, {Name: "postgres"}}
+	assert.ErrorContains(t, env.ValidateCatalogConfig(cfg), "duplicate")
+}
@@ -3,22 +3,30 @@ package helm
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
 	"github.com/onyxia-datalab/onyxia-backend/services/domain"
 	"github.com/onyxia-datalab/onyxia-backend/services/ports"
 	"helm.sh/helm/v4/pkg/chart"
+	chartcommonutil "helm.sh/helm/v4/pkg/chart/common/util"
 	"helm.sh/helm/v4/pkg/chart/loader"
 	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartloader "helm.sh/helm/v4/pkg/chart/v2/loader"
 	"helm.sh/helm/v4/pkg/cli"
 	"helm.sh/helm/v4/pkg/getter"
 	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/provenance"
 	"helm.sh/helm/v4/pkg/repo/v1"
 )
 
@@ -26,13 +34,34 @@ var _ ports.PackageRepository = (*HelmPackageRepository)(nil)
 
 type HelmPackageRepository struct {
 	repos    map[string]*repo.ChartRepository
+	indexes  map[string]*cachedIndex
+	indexTTL time.Duration
 	catalogs map[string]env.CatalogConfig
+	filters  map[string]versionFilter
 	getters  getter.Providers
+
+	signatories map[string]*provenance.Signatory
+	verified    verifications
+
+	creds  credentialStore
+	charts chartCache
+
+	iconClient *http.Client
+	icons      iconCache
+
+	// onIndexChange is notified when the index of a catalog changes.
+	onIndexChange []func(ctx context.Context, catalogID string)
 }
 
+// NewPackageRepository builds a repository over catalogs. Helm indexes are
+// kept in memory for indexTTL before being downloaded again; a zero indexTTL
+// downloads them on every call. secrets reads the credentials of catalogs
+// referencing a Secret and may be nil when none does.
 func NewPackageRepository(
 	catalogs []env.CatalogConfig,
 	cacheDir string,
+	indexTTL time.Duration,
+	secrets ports.CatalogSecretReader,
 ) (*HelmPackageRepository, error) {
 	settings := cli.New()
 	if cacheDir != "" {
@@ -40,25 +69,46 @@ func NewPackageRepository(
 	}
 
 	repos := make(map[string]*repo.ChartRepository)
+	indexes := make(map[string]*cachedIndex)
 	catalogMap := make(map[string]env.CatalogConfig)
+	filters := make(map[string]versionFilter)
+	signatories := make(map[string]*provenance.Signatory)
 	getters := getter.All(settings)
 
 	for _, cfg := range catalogs {
 		catalogMap[cfg.ID] = cfg
 
-		if cfg.Type != env.CatalogTypeHelmRepo {
-			continue
+		filter, err := versionFilterFrom(cfg)
+		if err != nil {
+			return nil, err
 		}
+		filters[cfg.ID] = filter
 
-		if _, err := versionFilterFrom(cfg); err != nil {
-			return nil, err
+		if cfg.Verify != "" {
+			sig, err := provenance.NewFromKeyring(tools.Deref(cfg.Keyring), "")
+			if err != nil {
+				return nil, fmt.Errorf("catalog %q: loading keyring: %w", cfg.ID, err)
+			}
+			signatories[cfg.ID] = sig
+		}
+
+		if cfg.Type == env.CatalogTypeDirectory {
+			indexes[cfg.ID] = &cachedIndex{}
+			slog.Info(
+				"Chart directory configured",
+				slog.String("catalog", cfg.ID),
+				slog.String("path", cfg.Location),
+			)
+			continue
+		}
+		if cfg.Type != env.CatalogTypeHelmRepo {
+			continue
 		}
 
+		// Credentials are set on each download, see downloadHelmIndex.
 		entry := &repo.Entry{
 			Name:                  cfg.ID,
 			URL:                   cfg.Location,
-			Username:              tools.Deref(cfg.Username),
-			Password:              tools.Deref(cfg.Password),
 			InsecureSkipTLSVerify: cfg.SkipTLSVerify,
 			CAFile:                tools.Deref(cfg.CAFile),
 		}
@@ -69,6 +119,7 @@ func NewPackageRepository(
 		}
 		cr.CachePath = settings.RepositoryCache
 		repos[cfg.ID] = cr
+		indexes[cfg.ID] = &cachedIndex{}
 
 		slog.Info(
 			"Helm repo configured",
@@ -79,9 +130,15 @@ func NewPackageRepository(
 	}
 
 	return &HelmPackageRepository{
-		repos:    repos,
-		catalogs: catalogMap,
-		getters:  getters,
+		repos:       repos,
+		indexes:     indexes,
+		indexTTL:    indexTTL,
+		catalogs:    catalogMap,
+		filters:     filters,
+		getters:     getters,
+		signatories: signatories,
+		iconClient:  newIconClient(),
+		creds:       credentialStore{secrets: secrets},
 	}, nil
 }
 
@@ -94,7 +151,7 @@ func (h *HelmPackageRepository) ListPackages(
 		return nil, fmt.Errorf("catalog %q not found", catalogID)
 	}
 	switch cfg.Type {
-	case env.CatalogTypeHelmRepo:
+	case env.CatalogTypeHelmRepo, env.CatalogTypeDirectory:
 		return h.listHelmPackages(ctx, cfg)
 	case env.CatalogTypeOCI:
 		return h.listOCIPackages(ctx, cfg)
@@ -113,7 +170,7 @@ func (h *HelmPackageRepository) GetPackage(
 		return nil, fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, catalogID)
 	}
 	switch cfg.Type {
-	case env.CatalogTypeHelmRepo:
+	case env.CatalogTypeHelmRepo, env.CatalogTypeDirectory:
 		return h.getHelmPackage(ctx, cfg, name)
 	case env.CatalogTypeOCI:
 		return h.getOCIPackage(ctx, cfg, name)
@@ -122,6 +179,8 @@ func (h *HelmPackageRepository) GetPackage(
 	}
 }
 
+// ResolvePackage resolves a chart version for install, along with the
+// credentials needed to pull it.
 func (h *HelmPackageRepository) ResolvePackage(
 	ctx context.Context,
 	catalogID, pkgName, version string,
@@ -130,8 +189,38 @@ func (h *HelmPackageRepository) ResolvePackage(
 	if !ok {
 		return domain.PackageVersion{}, fmt.Errorf("catalog %q not found", catalogID)
 	}
+
+	pv, err := h.resolvePackage(ctx, cfg, pkgName, version)
+	if err != nil {
+		return domain.PackageVersion{}, err
+	}
+	if cfg.Type == env.CatalogTypeDirectory {
+		return pv, nil
+	}
+
+	creds, err := h.credentials(ctx, cfg)
+	if err != nil {
+		return domain.PackageVersion{}, err
+	}
+	pv.Credentials = creds
+	return pv, nil
+}
+
+func (h *HelmPackageRepository) resolvePackage(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	pkgName, version string,
+) (domain.PackageVersion, error) {
+	catalogID := cfg.ID
 	if cfg.Type == env.CatalogTypeOCI {
-		return resolveOCIPackage(cfg, pkgName, version)
+		pv, err := resolveOCIPackage(cfg, pkgName, version)
+		if err != nil {
+			return domain.PackageVersion{}, err
+		}
+		if err := h.checkOCIInstallable(ctx, cfg, pkgName, version); err != nil {
+			return domain.PackageVersion{}, err
+		}
+		return h.applyVerification(ctx, cfg, pv)
 	}
 
 	slog.InfoContext(ctx, "Resolving Helm package version",
@@ -140,7 +229,7 @@ func (h *HelmPackageRepository) ResolvePackage(
 		slog.String("version", version),
 	)
 
-	cr, idx, err := h.loadHelmIndex(catalogID)
+	idx, err := h.loadHelmIndex(ctx, cfg)
 	if err != nil {
 		return domain.PackageVersion{}, err
 	}
@@ -156,14 +245,25 @@ func (h *HelmPackageRepository) ResolvePackage(
 
 	for _, v := range versions {
 		if v.Version == version {
-			return domain.PackageVersion{
+			deprecated := v.Deprecated || chartDeprecated(versions)
+			if deprecated {
+				if err := checkInstallable(cfg, pkgName, version); err != nil {
+					return domain.PackageVersion{}, err
+				}
+			}
+			resolved := domain.PackageVersion{
 				Package: domain.Package{
-					Name:      pkgName,
-					CatalogID: catalogID,
+					Name:       pkgName,
+					CatalogID:  catalogID,
+					Deprecated: deprecated,
 				},
 				Version: version,
-				RepoURL: cr.Config.URL,
-			}, nil
+				RepoURL: cfg.Location,
+			}
+			if cfg.Type == env.CatalogTypeDirectory && len(v.URLs) > 0 {
+				resolved.LocalPath = v.URLs[0]
+			}
+			return h.applyVerification(ctx, cfg, resolved)
 		}
 	}
 
@@ -173,22 +273,142 @@ func (h *HelmPackageRepository) ResolvePackage(
 	)
 }
 
+// LoadCatalogs loads the index of every Helm repository and chart directory
+// catalog, so that they are ready and their configuration is checked before
+// the first request. Failures are logged; such catalogs are loaded again on
+// demand.
+func (h *HelmPackageRepository) LoadCatalogs(ctx context.Context) {
+	var wg sync.WaitGroup
+	for id := range h.indexes {
+		cfg := h.catalogs[id]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := h.loadHelmIndex(ctx, cfg); err != nil {
+				slog.WarnContext(ctx, "Unable to load catalog at startup",
+					slog.String("catalog", cfg.ID),
+					slog.Any("error", err),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// loadHelmIndex returns the index of a Helm repository or chart directory
+// catalog, from the cache when it is still fresh.
 func (h *HelmPackageRepository) loadHelmIndex(
-	catalogID string,
-) (*repo.ChartRepository, *repo.IndexFile, error) {
-	cr, ok := h.repos[catalogID]
+	ctx context.Context,
+	cfg env.CatalogConfig,
+) (*repo.IndexFile, error) {
+	cache, ok := h.indexes[cfg.ID]
 	if !ok {
-		return nil, nil, fmt.Errorf("unknown Helm catalog: %s", catalogID)
+		return nil, fmt.Errorf("unknown Helm catalog: %s", cfg.ID)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	if cache.fresh(h.indexTTL, now) {
+		return cache.idx, nil
+	}
+	if cache.backingOff(h.indexTTL, now) {
+		if cache.idx == nil {
+			return nil, fmt.Errorf("catalog %q unavailable, retrying later: %w", cfg.ID, cache.lastErr)
+		}
+		return cache.idx, nil
+	}
+
+	idx, err := h.refreshIndex(ctx, cfg, cache)
+	if err != nil && cache.idx != nil {
+		// Keep serving the last good index while the repository is down.
+		slog.WarnContext(ctx, "Serving stale catalog index",
+			slog.String("catalog", cfg.ID),
+			slog.Any("error", err),
+		)
+		return cache.idx, nil
+	}
+	return idx, err
+}
+
+// refreshIndex fetches the index of cfg and records the outcome in cache.
+// The caller holds cache.mu.
+func (h *HelmPackageRepository) refreshIndex(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	cache *cachedIndex,
+) (*repo.IndexFile, error) {
+	start := time.Now()
+
+	var (
+		idx *repo.IndexFile
+		err error
+	)
+	if cfg.Type == env.CatalogTypeDirectory {
+		idx, err = indexChartDirectory(cfg.Location)
+	} else {
+		idx, err = h.downloadHelmIndex(ctx, cfg)
+	}
+
+	now := time.Now()
+	cache.latency = now.Sub(start)
+	if err != nil {
+		cache.lastErr = err
+		cache.lastErrAt = now
+		return nil, err
+	}
+
+	if cache.idx == nil {
+		reportUnknownOverrides(ctx, cfg, idx)
+	}
+	revision := indexRevision(idx)
+	changed := revision != cache.revision
+	cache.idx = idx
+	cache.fetchedAt = now
+	cache.revision = revision
+	cache.lastErr = nil
+	cache.lastErrAt = time.Time{}
+	if changed {
+		for _, fn := range h.onIndexChange {
+			go fn(context.WithoutCancel(ctx), cfg.ID)
+		}
+	}
+	return idx, nil
+}
+
+// OnIndexChange registers fn to be called, in its own goroutine, whenever the
+// index of a Helm repository or chart directory catalog is loaded with a new
+// revision, the first load included. It must be called before the repository
+// is used.
+func (h *HelmPackageRepository) OnIndexChange(fn func(ctx context.Context, catalogID string)) {
+	h.onIndexChange = append(h.onIndexChange, fn)
+}
+
+// downloadHelmIndex downloads the index of a Helm repository. The caller
+// holds the lock of its cached index, which also guards the repository entry.
+func (h *HelmPackageRepository) downloadHelmIndex(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+) (*repo.IndexFile, error) {
+	creds, err := h.credentials(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	catalogID := cfg.ID
+	cr := h.repos[catalogID]
+	cr.Config.Username = creds.Username
+	cr.Config.Password = creds.Password
 	if _, err := cr.DownloadIndexFile(); err != nil {
-		return nil, nil, fmt.Errorf("fetching Helm index: %w", err)
+		return nil, fmt.Errorf("fetching Helm index: %w", err)
 	}
 	indexPath := filepath.Join(cr.CachePath, helmpath.CacheIndexFile(catalogID))
 	idx, err := repo.LoadIndexFile(indexPath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("parsing Helm index: %w", err)
+		return nil, fmt.Errorf("parsing Helm index: %w", err)
 	}
-	return cr, idx, nil
+	return idx, nil
 }
 
 func (h *HelmPackageRepository) listHelmPackages(
@@ -196,7 +416,7 @@ func (h *HelmPackageRepository) listHelmPackages(
 	cfg env.CatalogConfig,
 ) ([]domain.Package, error) {
 
-	_, idx, err := h.loadHelmIndex(cfg.ID)
+	idx, err := h.loadHelmIndex(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -209,32 +429,92 @@ func (h *HelmPackageRepository) listHelmPackages(
 		if len(versions) == 0 || isExcluded(cfg.Excluded, name) {
 			continue
 		}
+		if !listable(cfg.DeprecatedCharts, versions) {
+			continue
+		}
+		versions = usableVersions(cfg.DeprecatedCharts, versions)
+		versions = shownChartVersions(cfg, name, versions)
+		latest := h.latestVisible(ctx, cfg, name, versions)
+		if latest == nil {
+			continue
+		}
 
-		latest := versions[0]
-		pkgs = append(pkgs, domain.Package{
+		pkg := domain.Package{
 			CatalogID:   cfg.ID,
 			Name:        name,
 			Description: latest.Description,
+			Keywords:    latest.Keywords,
 			HomeUrl:     tools.MustParseURL(latest.Home),
 			IconUrl:     tools.MustParseURL(latest.Icon),
-		})
+			Deprecated:  latest.Deprecated,
+		}
+		applyOverride(cfg, &pkg)
+		pkgs = append(pkgs, pkg)
 	}
 
 	return pkgs, nil
 }
 
+// latestVisible returns the newest of versions left by the catalog version
+// filters and provenance policy, the one getHelmPackage takes the package
+// metadata from.
+func (h *HelmPackageRepository) latestVisible(
+	ctx context.Context,
+	catalog env.CatalogConfig,
+	name string,
+	versions repo.ChartVersions,
+) *repo.ChartVersion {
+	visible := h.visibleVersions(ctx, catalog, name, extractVersions(versions))
+	latest, ok := h.firstVerified(ctx, catalog, name, visible)
+	if !ok {
+		return nil
+	}
+	i := slices.IndexFunc(versions, func(v *repo.ChartVersion) bool {
+		return v != nil && v.Version == latest
+	})
+	if i < 0 {
+		return nil
+	}
+	return versions[i]
+}
+
+// firstVerified returns the first of versions that verifiedVersions keeps.
+// Only the versions before it are verified, so listing a catalog does not
+// verify every version.
+func (h *HelmPackageRepository) firstVerified(
+	ctx context.Context,
+	catalog env.CatalogConfig,
+	name string,
+	versions []string,
+) (string, bool) {
+	for _, v := range versions {
+		if catalog.Verify == env.VerifyStrict && h.verifyChart(ctx, catalog, name, v) != nil {
+			continue
+		}
+		return v, true
+	}
+	return "", false
+}
+
 func (h *HelmPackageRepository) getHelmPackage(
 	ctx context.Context,
 	catalog env.CatalogConfig,
 	name string,
 ) (*domain.PackageRef, error) {
 
-	_, idx, err := h.loadHelmIndex(catalog.ID)
+	idx, err := h.loadHelmIndex(ctx, catalog)
 	if err != nil {
 		return nil, err
 	}
 
 	versions, ok := idx.Entries[name]
+	if catalog.DeprecatedCharts == env.DeprecatedChartsHide {
+		versions = usableVersions(catalog.DeprecatedCharts, versions)
+		if chartDeprecated(idx.Entries[name]) {
+			versions = nil
+		}
+	}
+	versions = shownChartVersions(catalog, name, versions)
 	if !ok || len(versions) == 0 {
 		return nil, fmt.Errorf(
 			"%w: chart %q not found in catalog %q",
@@ -244,58 +524,235 @@ func (h *HelmPackageRepository) getHelmPackage(
 		)
 	}
 
+	byVersion := make(map[string]*repo.ChartVersion, len(versions))
+	for _, v := range versions {
+		if v != nil && v.Metadata != nil {
+			byVersion[v.Version] = v
+		}
+	}
+
+	visible := h.visibleVersions(ctx, catalog, name, extractVersions(versions))
+	visible, unverified := h.verifiedVersions(ctx, catalog, name, visible)
+
+	// Package metadata comes from the newest visible version.
 	latest := versions[0]
-	return &domain.PackageRef{
-		Package: domain.Package{
-			CatalogID:   catalog.ID,
-			Name:        name,
-			Description: latest.Description,
-			HomeUrl:     tools.MustParseURL(latest.Home),
-			IconUrl:     tools.MustParseURL(latest.Icon),
-		},
-		Versions: mustVersionFilter(catalog).apply(extractVersions(versions)),
-	}, nil
+	if len(visible) > 0 {
+		latest = byVersion[visible[0]]
+	}
+
+	result := packageRefFromMetadata(catalog.ID, latest.Metadata)
+	applyOverride(catalog, &result.Package)
+	result.Versions = make([]domain.VersionInfo, 0, len(visible))
+	for _, v := range visible {
+		cv := byVersion[v]
+		result.Versions = append(result.Versions, domain.VersionInfo{
+			Version:    v,
+			AppVersion: cv.AppVersion,
+			Created:    cv.Created,
+			Digest:     cv.Digest,
+			Deprecated: cv.Deprecated,
+			Unverified: unverified[v],
+		})
+	}
+
+	return &result, nil
 }
 
+// GetPackageSchema returns the values.schema.json of a chart version. The
+// chart is pulled the same way for every catalog type, only its location
+// differs.
 func (h *HelmPackageRepository) GetPackageSchema(
 	ctx context.Context,
 	catalogID string,
 	packageName string,
 	version string,
 ) ([]byte, error) {
+	ch, err := h.catalogChart(ctx, catalogID, packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(ch.Schema) == 0 {
+		return nil, fmt.Errorf(
+			"%w: chart %q version %q has no values schema",
+			domain.ErrNotFound, packageName, version,
+		)
+	}
+	return ch.Schema, nil
+}
+
+// GetPackageReadme returns the README of a chart version, as markdown.
+func (h *HelmPackageRepository) GetPackageReadme(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) (string, error) {
+	ch, err := h.catalogChart(ctx, catalogID, packageName, version)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range ch.Files {
+		if f != nil && isReadme(f.Name) {
+			return string(f.Data), nil
+		}
+	}
+	return "", fmt.Errorf(
+		"%w: chart %q version %q has no README",
+		domain.ErrNotFound, packageName, version,
+	)
+}
+
+// GetPackageValues returns the default values of a chart version, as JSON.
+func (h *HelmPackageRepository) GetPackageValues(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) ([]byte, error) {
+	ch, err := h.catalogChart(ctx, catalogID, packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	values := ch.Values
+	if values == nil {
+		values = map[string]any{}
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("encoding default values: %w", err)
+	}
+	return raw, nil
+}
 
-	//TODO  Je suis persuadé que peut importe OCI ou HELM on peut faire la même logique
-	// Il suffit de faire un pull du package et d'extraire le schema (que ce soit un Url ou un lien oci// ne doit pas changer l'implem ? )
+// ValidateValues checks values, merged over the chart defaults the way
+// Helm does on install, against the values schema of a chart version.
+// Charts without a schema accept any values.
+func (h *HelmPackageRepository) ValidateValues(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+	values map[string]any,
+) error {
+	ch, err := h.catalogChart(ctx, catalogID, packageName, version)
+	if err != nil {
+		return err
+	}
+	merged, err := chartcommonutil.CoalesceValues(ch, values)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	if err := chartcommonutil.ValidateAgainstSchema(ch, merged); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	return nil
+}
 
-	// switch catalog.Type {
-	// case env.CatalogTypeHelmRepo:
-	// 	return h.getHelmPackageSchema(ctx, catalog, packageName, version)
-	// case env.CatalogTypeOCI:
-	// 	return h.getOCIPackageSchema(ctx, catalog, packageName, version)
-	// default:
-	// 	return nil, fmt.Errorf("unsupported catalog type: %v", cfg.Type)
-	// }
+// isReadme reports whether a chart file is its README, as Helm looks it up.
+func isReadme(name string) bool {
+	switch strings.ToLower(name) {
+	case "readme.md", "readme.txt", "readme":
+		return true
+	}
+	return false
+}
 
-	return nil, fmt.Errorf("GetPackageSchema not implemented yet")
+// catalogChart fetches a chart version from a catalog given by id.
+func (h *HelmPackageRepository) catalogChart(
+	ctx context.Context,
+	catalogID string,
+	name, version string,
+) (*chartv2.Chart, error) {
+	cfg, ok := h.catalogs[catalogID]
+	if !ok {
+		return nil, fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, catalogID)
+	}
+	return h.loadChart(ctx, cfg, name, version)
 }
 
-func (h *HelmPackageRepository) pullChart(
-	chartURL string,
-	opts ...getter.Option,
+// loadChart fetches a chart version from its catalog. Archives with a digest
+// are cached for good, those pulled by tag for the index TTL since the tag may
+// be pushed again. Unpacked local charts are read again every time since they
+// may change.
+func (h *HelmPackageRepository) loadChart(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
 ) (*chartv2.Chart, error) {
-	u, err := url.Parse(chartURL)
+	src, err := h.locateChart(ctx, cfg, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if src.local && src.digest == "" {
+		return chartloader.Load(src.url)
+	}
+
+	load := func() (*chartv2.Chart, error) {
+		if src.local {
+			return chartloader.Load(src.url)
+		}
+
+		slog.DebugContext(ctx, "Pulling chart",
+			slog.String("catalog", cfg.ID),
+			slog.String("url", src.url),
+		)
+		opts, err := h.getterOptions(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return h.pullChart(src.url, opts...)
+	}
+	if src.digest != "" {
+		return h.charts.get(src.digest, 0, load)
+	}
+	if h.indexTTL <= 0 {
+		return load()
+	}
+	return h.charts.get(src.url, h.indexTTL, load)
+}
+
+// getterOptions returns the TLS and credential options of a catalog.
+func (h *HelmPackageRepository) getterOptions(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+) ([]getter.Option, error) {
+	creds, err := h.credentials(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []getter.Option{
+		getter.WithInsecureSkipVerifyTLS(cfg.SkipTLSVerify),
+		getter.WithTLSClientConfig("", "", tools.Deref(cfg.CAFile)),
+		getter.WithBasicAuth(creds.Username, creds.Password),
+	}, nil
+}
+
+// fetch downloads rawURL with the getter registered for its scheme.
+func (h *HelmPackageRepository) fetch(rawURL string, opts ...getter.Option) ([]byte, error) {
+	u, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid chart URL: %w", err)
+		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 	g, err := h.getters.ByScheme(u.Scheme)
 	if err != nil {
 		return nil, fmt.Errorf("no getter for scheme %q: %w", u.Scheme, err)
 	}
-	buf, err := g.Get(chartURL, opts...)
+	buf, err := g.Get(rawURL, append(opts, getter.WithURL(rawURL))...)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (h *HelmPackageRepository) pullChart(
+	chartURL string,
+	opts ...getter.Option,
+) (*chartv2.Chart, error) {
+	data, err := h.fetch(chartURL, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("downloading chart: %w", err)
 	}
-	raw, err := loader.LoadArchive(bytes.NewReader(buf.Bytes()))
+	raw, err := loader.LoadArchive(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -318,10 +775,21 @@ func (h *HelmPackageRepository) listOCIPackages(
 		if isExcluded(cfg.Excluded, p.Name) {
 			continue
 		}
-		pkgs = append(pkgs, domain.Package{
+		if !showsDeprecated(cfg.DeprecatedCharts) && h.ociChartDeprecated(ctx, cfg, p) {
+			continue
+		}
+		if cfg.Verify == env.VerifyStrict {
+			visible := h.visibleVersions(ctx, cfg, p.Name, shownVersions(cfg, p.Name, p.Versions))
+			if _, ok := h.firstVerified(ctx, cfg, p.Name, visible); !ok {
+				continue
+			}
+		}
+		pkg := domain.Package{
 			CatalogID: cfg.ID,
 			Name:      p.Name,
-		})
+		}
+		applyOverride(cfg, &pkg)
+		pkgs = append(pkgs, pkg)
 	}
 	return pkgs, nil
 }
@@ -340,37 +808,53 @@ func (h *HelmPackageRepository) getOCIPackage(
 		}
 	}
 	if pkg == nil {
-		return nil, fmt.Errorf("package %q not found in OCI catalog %q", name, catalog.ID)
+		return nil, fmt.Errorf("%w: package %q not found in OCI catalog %q", domain.ErrNotFound, name, catalog.ID)
+	}
+
+	hide := catalog.DeprecatedCharts == env.DeprecatedChartsHide
+	if hide && h.ociChartDeprecated(ctx, catalog, *pkg) {
+		return nil, fmt.Errorf("%w: package %q not found in OCI catalog %q", domain.ErrNotFound, name, catalog.ID)
 	}
 
-	base := strings.TrimSuffix(catalog.Location, "/")
+	visible := h.visibleVersions(ctx, catalog, name, shownVersions(catalog, name, pkg.Versions))
+	visible, unverified := h.verifiedVersions(ctx, catalog, name, visible)
+
 	result := domain.PackageRef{
 		Package: domain.Package{
 			CatalogID: catalog.ID,
 			Name:      name,
 		},
-		Versions: pkg.Versions,
+		Versions: make([]domain.VersionInfo, 0, len(visible)),
 	}
 
-	for _, version := range pkg.Versions {
-		ref := fmt.Sprintf("%s/%s", base, strings.TrimPrefix(name, "/"))
-		ch, err := h.pullChart(ref,
-			getter.WithURL(ref),
-			getter.WithTagName(version),
-			getter.WithInsecureSkipVerifyTLS(catalog.SkipTLSVerify),
-			getter.WithTLSClientConfig("", "", tools.Deref(catalog.CAFile)),
-			getter.WithBasicAuth(tools.Deref(catalog.Username), tools.Deref(catalog.Password)),
-		)
-		if err != nil {
-			continue
-		}
-		if ch.Metadata != nil && ch.Metadata.Description != "" {
-			result.Description = ch.Metadata.Description
-			result.HomeUrl = tools.MustParseURL(ch.Metadata.Home)
-			result.IconUrl = tools.MustParseURL(ch.Metadata.Icon)
+	// Package metadata comes from the newest version that could be pulled.
+	haveMetadata := false
+	for _, version := range visible {
+		info := domain.VersionInfo{Version: version, Unverified: unverified[version]}
+
+		ch, err := h.loadChart(ctx, catalog, name, version)
+		if err == nil && ch.Metadata != nil {
+			if hide && ch.Metadata.Deprecated {
+				continue
+			}
+			info.AppVersion = ch.Metadata.AppVersion
+			info.Deprecated = ch.Metadata.Deprecated
+			if !haveMetadata {
+				result = packageRefFromMetadata(catalog.ID, ch.Metadata)
+				result.Name = name
+				result.Versions = make([]domain.VersionInfo, 0, len(visible))
+				haveMetadata = true
+			}
 		}
+
+		result.Versions = append(result.Versions, info)
+	}
+
+	if hide && len(result.Versions) == 0 {
+		return nil, fmt.Errorf("%w: package %q not found in OCI catalog %q", domain.ErrNotFound, name, catalog.ID)
 	}
 
+	applyOverride(catalog, &result.Package)
 	return &result, nil
 }
 
@@ -405,15 +889,79 @@ func resolveOCIPackage(
 	)
 }
 
-// mustVersionFilter returns the version filter for cfg.
-// It panics if the config is invalid, but that can't happen since
-// NewPackageRepository validates all configs at startup.
-func mustVersionFilter(cfg env.CatalogConfig) versionFilter {
-	f, err := versionFilterFrom(cfg)
-	if err != nil {
-		panic(fmt.Sprintf("invalid version filter for catalog %q: %v", cfg.ID, err))
+// packageRefFromMetadata maps chart metadata onto a package, without versions.
+func packageRefFromMetadata(catalogID string, md *chartv2.Metadata) domain.PackageRef {
+	maintainers := make([]domain.Maintainer, 0, len(md.Maintainers))
+	for _, m := range md.Maintainers {
+		if m != nil {
+			maintainers = append(maintainers, domain.Maintainer{
+				Name:  m.Name,
+				Email: m.Email,
+				URL:   m.URL,
+			})
+		}
+	}
+
+	return domain.PackageRef{
+		Package: domain.Package{
+			CatalogID:   catalogID,
+			Name:        md.Name,
+			Description: md.Description,
+			Keywords:    md.Keywords,
+			HomeUrl:     tools.MustParseURL(md.Home),
+			IconUrl:     tools.MustParseURL(md.Icon),
+			Deprecated:  md.Deprecated,
+		},
+		AppVersion:  md.AppVersion,
+		Maintainers: maintainers,
+		Sources:     md.Sources,
+	}
+}
+
+// visibleVersions sorts versions newest-first and applies the catalog version
+// filter. Versions that are not valid semver are logged and dropped.
+func (h *HelmPackageRepository) visibleVersions(
+	ctx context.Context,
+	catalog env.CatalogConfig,
+	name string,
+	versions []string,
+) []string {
+	kept, invalid := filterVersions(h.filters[catalog.ID], versions)
+	if len(invalid) > 0 {
+		slog.WarnContext(ctx, "Ignoring invalid package versions",
+			slog.String("catalog", catalog.ID),
+			slog.String("package", name),
+			slog.Any("versions", invalid),
+		)
+	}
+	return kept
+}
+
+// verifiedVersions applies the catalog provenance policy to versions. In
+// strict mode unverified versions are dropped; in warn mode they are kept and
+// reported in the returned set.
+func (h *HelmPackageRepository) verifiedVersions(
+	ctx context.Context,
+	catalog env.CatalogConfig,
+	name string,
+	versions []string,
+) ([]string, map[string]bool) {
+	if _, ok := h.signatories[catalog.ID]; !ok {
+		return versions, nil
+	}
+
+	kept := make([]string, 0, len(versions))
+	unverified := make(map[string]bool)
+	for _, v := range versions {
+		if err := h.verifyChart(ctx, catalog, name, v); err != nil {
+			if catalog.Verify == env.VerifyStrict {
+				continue
+			}
+			unverified[v] = true
+		}
+		kept = append(kept, v)
 	}
-	return f
+	return kept, unverified
 }
 
 func extractVersions(list []*repo.ChartVersion) []string {
@@ -1,25 +1,35 @@
 package helm
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
 	"github.com/onyxia-datalab/onyxia-backend/services/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v4/pkg/chart/common"
 	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/provenance"
 	"helm.sh/helm/v4/pkg/repo/v1"
 )
 
 type localHelmRepo struct {
-	server *httptest.Server
-	tmpDir string
-	cfg    env.CatalogConfig
+	server    *httptest.Server
+	tmpDir    string
+	cfg       env.CatalogConfig
+	indexHits atomic.Int32
+	down      atomic.Bool // the index is answered with 503 when set
 }
 
 func newLocalHelmRepo(t *testing.T, charts ...*chartv2.Metadata) *localHelmRepo {
@@ -37,28 +47,34 @@ func newLocalHelmRepo(t *testing.T, charts ...*chartv2.Metadata) *localHelmRepo
 	indexPath := filepath.Join(tmp, "index.yaml")
 	require.NoError(t, idx.WriteFile(indexPath, 0644))
 
+	lr := &localHelmRepo{tmpDir: tmp}
+
 	// Serveur HTTP local
 	mux := http.NewServeMux()
-	mux.Handle("/index.yaml", http.FileServer(http.Dir(tmp)))
-	server := httptest.NewServer(mux)
-	t.Cleanup(server.Close)
+	files := http.FileServer(http.Dir(tmp))
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		lr.indexHits.Add(1)
+		if lr.down.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+	lr.server = httptest.NewServer(mux)
+	t.Cleanup(lr.server.Close)
 
-	cfg := env.CatalogConfig{
+	lr.cfg = env.CatalogConfig{
 		ID:       "test",
 		Type:     env.CatalogTypeHelmRepo,
-		Location: server.URL,
+		Location: lr.server.URL,
 	}
 
-	return &localHelmRepo{
-		server: server,
-		tmpDir: tmp,
-		cfg:    cfg,
-	}
+	return lr
 }
 
 func (l *localHelmRepo) newAdapter(t *testing.T) *HelmPackageRepository {
 	t.Helper()
-	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{l.cfg}, l.tmpDir)
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{l.cfg}, l.tmpDir, 0, nil)
 	require.NoError(t, err)
 	return repoAdapter
 }
@@ -92,6 +108,153 @@ func TestListHelmPackages_WithLocalHTTPRepo(t *testing.T) {
 	require.NoError(t, os.RemoveAll(lr.tmpDir))
 }
 
+func TestListHelmPackages_MetadataOfNewestVisibleVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t,
+		&chartv2.Metadata{Name: "mychart", Version: "3.0.0", Description: "excluded"},
+		&chartv2.Metadata{Name: "mychart", Version: "2.0.0", Description: "current"},
+		&chartv2.Metadata{Name: "other", Version: "3.1.0"},
+	)
+	lr.cfg.ExcludedVersions = ">= 3.0.0"
+	repoAdapter := lr.newAdapter(t)
+
+	pkgs, err := repoAdapter.ListPackages(context.Background(), lr.cfg.ID)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1, "Packages without visible versions are not listed")
+
+	pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
+	require.NoError(t, err)
+	assert.Equal(t, "current", pkgs[0].Description)
+	assert.Equal(t, pkg.Description, pkgs[0].Description)
+}
+
+func TestListHelmPackages_IndexIsCached(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t, &chartv2.Metadata{Name: "mychart", Version: "1.0.0"})
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{lr.cfg}, lr.tmpDir, time.Hour, nil)
+	require.NoError(t, err)
+
+	for range 3 {
+		_, err := repoAdapter.ListPackages(context.Background(), lr.cfg.ID)
+		require.NoError(t, err)
+	}
+	_, err = repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), lr.indexHits.Load())
+}
+
+func TestLoadCatalogs_LoadsIndexesAhead(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t, &chartv2.Metadata{Name: "mychart", Version: "1.0.0"})
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{lr.cfg}, lr.tmpDir, time.Hour, nil)
+	require.NoError(t, err)
+
+	repoAdapter.LoadCatalogs(context.Background())
+	assert.Equal(t, int32(1), lr.indexHits.Load())
+
+	_, err = repoAdapter.ListPackages(context.Background(), lr.cfg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), lr.indexHits.Load())
+}
+
+func TestOnIndexChange_NotifiesNewRevisions(t *testing.T) {
+	dir := t.TempDir()
+	saveTestChart(t, dir, "jupyter", "1.0.0", "")
+	cfg := env.CatalogConfig{ID: "local", Type: env.CatalogTypeDirectory, Location: dir}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+	require.NoError(t, err)
+	changes := make(chan string, 10)
+	repoAdapter.OnIndexChange(func(_ context.Context, catalogID string) { changes <- catalogID })
+	ctx := context.Background()
+
+	expectChange := func() {
+		t.Helper()
+		select {
+		case id := <-changes:
+			assert.Equal(t, "local", id)
+		case <-time.After(time.Second):
+			t.Fatal("index change not notified")
+		}
+	}
+
+	// ✅ The first load is a change.
+	_, err = repoAdapter.ListPackages(ctx, cfg.ID)
+	require.NoError(t, err)
+	expectChange()
+
+	// ✅ Loading the same index again is not.
+	_, err = repoAdapter.ListPackages(ctx, cfg.ID)
+	require.NoError(t, err)
+
+	// ✅ A new chart version is.
+	saveTestChart(t, dir, "jupyter", "1.1.0", "")
+	_, err = repoAdapter.ListPackages(ctx, cfg.ID)
+	require.NoError(t, err)
+	expectChange()
+	assert.Empty(t, changes)
+}
+
+func TestListHelmPackages_ServesStaleIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t, &chartv2.Metadata{Name: "mychart", Version: "1.0.0"})
+	repoAdapter, err := NewPackageRepository(
+		[]env.CatalogConfig{lr.cfg},
+		lr.tmpDir,
+		50*time.Millisecond,
+		nil,
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = repoAdapter.ListPackages(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+
+	// Once the repository is down, the last good index is still served.
+	lr.server.Close()
+	time.Sleep(60 * time.Millisecond)
+
+	pkgs, err := repoAdapter.ListPackages(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 1)
+
+	health, err := repoAdapter.CatalogHealth(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, health.LastError)
+}
+
+func TestListHelmPackages_BacksOffWithoutIndex(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := env.CatalogConfig{ID: "down", Type: env.CatalogTypeHelmRepo, Location: server.URL}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, t.TempDir(), time.Hour, nil)
+	require.NoError(t, err)
+
+	// A catalog that never loaded is not hit again until the TTL expires.
+	for range 3 {
+		_, err := repoAdapter.ListPackages(context.Background(), cfg.ID)
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(1), hits.Load())
+}
+
 func TestGetHelmPackage_Found(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -108,7 +271,43 @@ func TestGetHelmPackage_Found(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, pkg)
 	assert.Equal(t, "mychart", pkg.Name)
-	assert.ElementsMatch(t, []string{"2.0.0", "1.0.0"}, pkg.Versions)
+	assert.ElementsMatch(t, []string{"2.0.0", "1.0.0"}, pkg.VersionNames())
+}
+
+func TestGetHelmPackage_Metadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t,
+		&chartv2.Metadata{
+			Name:        "mychart",
+			Version:     "2.0.0",
+			AppVersion:  "4.1",
+			Description: "v2",
+			Keywords:    []string{"notebook"},
+			Sources:     []string{"https://github.com/example/mychart"},
+			Maintainers: []*chartv2.Maintainer{{Name: "Onyxia", Email: "team@onyxia.sh"}},
+		},
+		&chartv2.Metadata{Name: "mychart", Version: "1.0.0", AppVersion: "3.9", Deprecated: true},
+	)
+	repoAdapter := lr.newAdapter(t)
+
+	pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
+	require.NoError(t, err)
+
+	assert.Equal(t, "v2", pkg.Description)
+	assert.Equal(t, "4.1", pkg.AppVersion)
+	assert.False(t, pkg.Deprecated)
+	assert.Equal(t, []string{"notebook"}, pkg.Keywords)
+	assert.Equal(t, []string{"https://github.com/example/mychart"}, pkg.Sources)
+	assert.Equal(t, []domain.Maintainer{{Name: "Onyxia", Email: "team@onyxia.sh"}}, pkg.Maintainers)
+
+	require.Len(t, pkg.Versions, 2)
+	assert.Equal(t, "2.0.0", pkg.Versions[0].Version)
+	assert.Equal(t, "4.1", pkg.Versions[0].AppVersion)
+	assert.Equal(t, "3.9", pkg.Versions[1].AppVersion)
+	assert.False(t, pkg.Versions[0].Created.IsZero())
 }
 
 func TestGetHelmPackage_NotFound(t *testing.T) {
@@ -138,7 +337,7 @@ func TestGetHelmPackage_VersionFilter_Latest(t *testing.T) {
 
 	pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
 	require.NoError(t, err)
-	assert.Equal(t, []string{"2.0.0"}, pkg.Versions)
+	assert.Equal(t, []string{"2.0.0"}, pkg.VersionNames())
 }
 
 func TestGetHelmPackage_VersionFilter_MaxNumber(t *testing.T) {
@@ -158,7 +357,7 @@ func TestGetHelmPackage_VersionFilter_MaxNumber(t *testing.T) {
 
 	pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
 	require.NoError(t, err)
-	assert.Equal(t, []string{"3.0.0", "2.0.0"}, pkg.Versions)
+	assert.Equal(t, []string{"3.0.0", "2.0.0"}, pkg.VersionNames())
 }
 
 func TestGetHelmPackage_VersionFilter_SkipPatches(t *testing.T) {
@@ -177,7 +376,7 @@ func TestGetHelmPackage_VersionFilter_SkipPatches(t *testing.T) {
 
 	pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
 	require.NoError(t, err)
-	assert.Equal(t, []string{"2.1.1", "1.0.5"}, pkg.Versions)
+	assert.Equal(t, []string{"2.1.1", "1.0.5"}, pkg.VersionNames())
 }
 
 func TestNewPackageRepository_MaxNumber_MissingN_ReturnsError(t *testing.T) {
@@ -188,7 +387,7 @@ func TestNewPackageRepository_MaxNumber_MissingN_ReturnsError(t *testing.T) {
 		MultipleServicesMode: env.MultipleServicesMaxNumber,
 		MaxNumberOfVersions:  nil,
 	}}
-	_, err := NewPackageRepository(cfgs, "")
+	_, err := NewPackageRepository(cfgs, "", 0, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "maxNumberOfVersions")
 }
@@ -235,7 +434,7 @@ func TestResolvePackage_OCICatalog(t *testing.T) {
 			{Name: "my-app", Versions: []string{"2.0.0", "1.5.0", "1.0.0"}},
 		},
 	}
-	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{ociCfg}, "")
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{ociCfg}, "", 0, nil)
 	require.NoError(t, err)
 
 	t.Run("existing package and version", func(t *testing.T) {
@@ -257,3 +456,368 @@ func TestResolvePackage_OCICatalog(t *testing.T) {
 		require.ErrorIs(t, err, domain.ErrNotFound)
 	})
 }
+
+func TestDeprecatedCharts_Modes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t,
+		&chartv2.Metadata{Name: "legacy", Version: "1.0.0", Deprecated: true},
+		&chartv2.Metadata{Name: "mychart", Version: "2.0.0"},
+		&chartv2.Metadata{Name: "mychart", Version: "1.0.0", Deprecated: true},
+	)
+
+	adapterFor := func(t *testing.T, mode env.DeprecatedChartsMode) *HelmPackageRepository {
+		t.Helper()
+		cfg := lr.cfg
+		cfg.DeprecatedCharts = mode
+		repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, lr.tmpDir, 0, nil)
+		require.NoError(t, err)
+		return repoAdapter
+	}
+
+	t.Run("show flags deprecated charts and versions", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.DeprecatedChartsShow)
+
+		pkgs, err := repoAdapter.ListPackages(context.Background(), lr.cfg.ID)
+		require.NoError(t, err)
+		require.Len(t, pkgs, 2)
+
+		pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
+		require.NoError(t, err)
+		assert.False(t, pkg.Deprecated)
+		assert.True(t, pkg.Versions[1].Deprecated)
+
+		resolved, err := repoAdapter.ResolvePackage(context.Background(), lr.cfg.ID, "legacy", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, resolved.Deprecated)
+	})
+
+	t.Run("hide removes deprecated charts and versions", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.DeprecatedChartsHide)
+
+		pkgs, err := repoAdapter.ListPackages(context.Background(), lr.cfg.ID)
+		require.NoError(t, err)
+		require.Len(t, pkgs, 1)
+		assert.Equal(t, "mychart", pkgs[0].Name)
+
+		pkg, err := repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "mychart")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.0.0"}, pkg.VersionNames())
+
+		_, err = repoAdapter.GetPackage(context.Background(), lr.cfg.ID, "legacy")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = repoAdapter.ResolvePackage(context.Background(), lr.cfg.ID, "mychart", "1.0.0")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("existingOnly lists nothing new and refuses installs", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.DeprecatedChartsExistingOnly)
+
+		pkgs, err := repoAdapter.ListPackages(context.Background(), lr.cfg.ID)
+		require.NoError(t, err)
+		require.Len(t, pkgs, 1)
+
+		_, err = repoAdapter.ResolvePackage(context.Background(), lr.cfg.ID, "legacy", "1.0.0")
+		assert.ErrorIs(t, err, domain.ErrNotInstallable)
+	})
+}
+
+// fakeOCIGetter serves chart archives by OCI reference, in place of a registry.
+type fakeOCIGetter map[string][]byte
+
+func (f fakeOCIGetter) Get(url string, _ ...getter.Option) (*bytes.Buffer, error) {
+	data, ok := f[url]
+	if !ok {
+		return nil, fmt.Errorf("%s: not found", url)
+	}
+	return bytes.NewBuffer(data), nil
+}
+
+func TestDeprecatedCharts_OCIModes(t *testing.T) {
+	tmp := t.TempDir()
+	charts := fakeOCIGetter{}
+	for _, md := range []*chartv2.Metadata{
+		{Name: "legacy", Version: "1.0.0", Deprecated: true},
+		{Name: "my-app", Version: "2.0.0"},
+		{Name: "my-app", Version: "1.0.0", Deprecated: true},
+	} {
+		md.APIVersion = chartv2.APIVersionV2
+		archive, err := chartutil.Save(&chartv2.Chart{Metadata: md}, tmp)
+		require.NoError(t, err)
+		data, err := os.ReadFile(archive)
+		require.NoError(t, err)
+		charts["oci://registry.example.com/charts/"+md.Name+":"+md.Version] = data
+	}
+
+	adapterFor := func(t *testing.T, mode env.DeprecatedChartsMode) *HelmPackageRepository {
+		t.Helper()
+		cfg := env.CatalogConfig{
+			ID:               "oci",
+			Type:             env.CatalogTypeOCI,
+			Location:         "oci://registry.example.com/charts",
+			DeprecatedCharts: mode,
+			Packages: []env.OCIPackage{
+				{Name: "legacy", Versions: []string{"1.0.0"}},
+				{Name: "my-app", Versions: []string{"1.0.0", "2.0.0"}},
+			},
+		}
+		repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+		require.NoError(t, err)
+		repoAdapter.getters = getter.Providers{{
+			Schemes: []string{"oci"},
+			New:     func(...getter.Option) (getter.Getter, error) { return charts, nil },
+		}}
+		return repoAdapter
+	}
+	ctx := context.Background()
+
+	t.Run("show lists and installs deprecated charts", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.DeprecatedChartsShow)
+
+		pkgs, err := repoAdapter.ListPackages(ctx, "oci")
+		require.NoError(t, err)
+		assert.Len(t, pkgs, 2)
+
+		_, err = repoAdapter.ResolvePackage(ctx, "oci", "legacy", "1.0.0")
+		assert.NoError(t, err)
+	})
+
+	t.Run("hide removes deprecated charts and versions", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.DeprecatedChartsHide)
+
+		pkgs, err := repoAdapter.ListPackages(ctx, "oci")
+		require.NoError(t, err)
+		require.Len(t, pkgs, 1)
+		assert.Equal(t, "my-app", pkgs[0].Name)
+
+		pkg, err := repoAdapter.GetPackage(ctx, "oci", "my-app")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.0.0"}, pkg.VersionNames())
+
+		_, err = repoAdapter.GetPackage(ctx, "oci", "legacy")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = repoAdapter.ResolvePackage(ctx, "oci", "my-app", "1.0.0")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("existingOnly lists nothing new and refuses installs", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.DeprecatedChartsExistingOnly)
+
+		pkgs, err := repoAdapter.ListPackages(ctx, "oci")
+		require.NoError(t, err)
+		require.Len(t, pkgs, 1)
+
+		_, err = repoAdapter.ResolvePackage(ctx, "oci", "legacy", "1.0.0")
+		assert.ErrorIs(t, err, domain.ErrNotInstallable)
+
+		_, err = repoAdapter.ResolvePackage(ctx, "oci", "my-app", "2.0.0")
+		assert.NoError(t, err)
+	})
+}
+
+func TestDirectoryCatalog(t *testing.T) {
+	dir := t.TempDir()
+
+	schema := []byte(`{"type":"object"}`)
+	packaged := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{
+			APIVersion:  chartv2.APIVersionV2,
+			Name:        "packaged",
+			Version:     "1.0.0",
+			Description: "From an archive",
+		},
+		Schema: schema,
+	}
+	_, err := chartutil.Save(packaged, dir)
+	require.NoError(t, err)
+
+	unpacked := &chartv2.Chart{Metadata: &chartv2.Metadata{
+		APIVersion: chartv2.APIVersionV2,
+		Name:       "unpacked",
+		Version:    "0.2.0",
+	}}
+	require.NoError(t, chartutil.SaveDir(unpacked, dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a chart"), 0o644))
+
+	cfg := env.CatalogConfig{ID: "local", Type: env.CatalogTypeDirectory, Location: dir}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	pkgs, err := repoAdapter.ListPackages(ctx, cfg.ID)
+	require.NoError(t, err)
+	names := make([]string, 0, len(pkgs))
+	for _, p := range pkgs {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"packaged", "unpacked"}, names)
+
+	pkg, err := repoAdapter.GetPackage(ctx, cfg.ID, "packaged")
+	require.NoError(t, err)
+	assert.Equal(t, "From an archive", pkg.Description)
+	assert.Equal(t, []string{"1.0.0"}, pkg.VersionNames())
+	assert.NotEmpty(t, pkg.Versions[0].Digest)
+
+	got, err := repoAdapter.GetPackageSchema(ctx, cfg.ID, "packaged", "1.0.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(schema), string(got))
+
+	_, err = repoAdapter.GetPackageSchema(ctx, cfg.ID, "unpacked", "0.2.0")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	resolved, err := repoAdapter.ResolvePackage(ctx, cfg.ID, "unpacked", "0.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "unpacked"), resolved.ChartRef())
+}
+
+func TestCatalogHealth_AndRefresh(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t,
+		&chartv2.Metadata{Name: "mychart", Version: "1.0.0"},
+		&chartv2.Metadata{Name: "other", Version: "1.0.0"},
+	)
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{lr.cfg}, lr.tmpDir, time.Hour, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	health, err := repoAdapter.CatalogHealth(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.True(t, health.LastRefresh.IsZero())
+
+	_, err = repoAdapter.ListPackages(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+
+	health, err = repoAdapter.CatalogHealth(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, health.Packages)
+	assert.False(t, health.LastRefresh.IsZero())
+	assert.Empty(t, health.LastError)
+
+	// A manual refresh bypasses the cache; a failure keeps the previous index.
+	lr.down.Store(true)
+	health, err = repoAdapter.RefreshCatalog(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, health.LastError)
+	assert.False(t, health.LastErrorAt.IsZero())
+	assert.Equal(t, 2, health.Packages)
+	assert.Equal(t, int32(2), lr.indexHits.Load())
+
+	// Once the repository recovers, the error is no longer reported.
+	lr.down.Store(false)
+	health, err = repoAdapter.RefreshCatalog(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.Empty(t, health.LastError)
+	assert.True(t, health.LastErrorAt.IsZero())
+
+	_, err = repoAdapter.CatalogHealth(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestChartDocuments_ShareOneDownload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	tmp := t.TempDir()
+	ch := &chartv2.Chart{
+		Metadata: &chartv2.Metadata{
+			APIVersion: chartv2.APIVersionV2,
+			Name:       "docs",
+			Version:    "1.0.0",
+		},
+		Raw: []*common.File{
+			{Name: "values.yaml", Data: []byte("replicas: 1\nimage:\n  tag: latest\n")},
+		},
+		Schema: []byte(`{"type":"object"}`),
+		Files:  []*common.File{{Name: "README.md", Data: []byte("# Docs\n")}},
+	}
+	archive, err := chartutil.Save(ch, tmp)
+	require.NoError(t, err)
+
+	var downloads atomic.Int32
+	files := http.FileServer(http.Dir(tmp))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filepath.Ext(r.URL.Path) == ".tgz" {
+			downloads.Add(1)
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	digest, err := provenance.DigestFile(archive)
+	require.NoError(t, err)
+	idx := repo.NewIndexFile()
+	require.NoError(t, idx.MustAdd(ch.Metadata, filepath.Base(archive), server.URL, "sha256:"+digest))
+	require.NoError(t, idx.WriteFile(filepath.Join(tmp, "index.yaml"), 0o644))
+
+	cfg := env.CatalogConfig{ID: "docs", Type: env.CatalogTypeHelmRepo, Location: server.URL}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, tmp, time.Hour, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	readme, err := repoAdapter.GetPackageReadme(ctx, cfg.ID, "docs", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "# Docs\n", readme)
+
+	values, err := repoAdapter.GetPackageValues(ctx, cfg.ID, "docs", "1.0.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"replicas":1,"image":{"tag":"latest"}}`, string(values))
+
+	_, err = repoAdapter.GetPackageSchema(ctx, cfg.ID, "docs", "1.0.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), downloads.Load())
+
+	_, err = repoAdapter.GetPackageReadme(ctx, cfg.ID, "docs", "9.9.9")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestCatalogRevision(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	lr := newLocalHelmRepo(t, &chartv2.Metadata{Name: "mychart", Version: "1.0.0"})
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{lr.cfg}, lr.tmpDir, time.Hour, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	first, err := repoAdapter.CatalogRevision(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, first)
+
+	// Regenerating the same index keeps the revision.
+	idx, err := repo.LoadIndexFile(filepath.Join(lr.tmpDir, "index.yaml"))
+	require.NoError(t, err)
+	idx.Generated = idx.Generated.Add(time.Hour)
+	require.NoError(t, idx.WriteFile(filepath.Join(lr.tmpDir, "index.yaml"), 0o644))
+	_, err = repoAdapter.RefreshCatalog(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+
+	same, err := repoAdapter.CatalogRevision(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, same)
+
+	// A new chart version changes it.
+	require.NoError(t, idx.MustAdd(
+		&chartv2.Metadata{Name: "mychart", Version: "1.1.0"},
+		"mychart-1.1.0.tgz", "http://localhost", "",
+	))
+	require.NoError(t, idx.WriteFile(filepath.Join(lr.tmpDir, "index.yaml"), 0o644))
+	_, err = repoAdapter.RefreshCatalog(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+
+	changed, err := repoAdapter.CatalogRevision(ctx, lr.cfg.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+
+	_, err = repoAdapter.CatalogRevision(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
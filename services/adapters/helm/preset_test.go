@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+func TestValidateValues(t *testing.T) {
+	dir := t.TempDir()
+	_, err := chartutil.Save(&chartv2.Chart{
+		Metadata: &chartv2.Metadata{
+			APIVersion: chartv2.APIVersionV2,
+			Name:       "jupyter",
+			Version:    "1.0.0",
+		},
+		Values: map[string]any{"cpu": "100m"},
+		Schema: []byte(`{
+			"type": "object",
+			"properties": {
+				"cpu": {"type": "string"},
+				"gpu": {"type": "integer", "minimum": 0}
+			}
+		}`),
+	}, dir)
+	require.NoError(t, err)
+
+	cfg := env.CatalogConfig{ID: "local", Type: env.CatalogTypeDirectory, Location: dir}
+	repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, repoAdapter.ValidateValues(ctx, cfg.ID, "jupyter", "1.0.0", map[string]any{"gpu": 1}))
+
+	err = repoAdapter.ValidateValues(ctx, cfg.ID, "jupyter", "1.0.0", map[string]any{"gpu": "one"})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	err = repoAdapter.ValidateValues(ctx, cfg.ID, "jupyter", "9.9.9", nil)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestValidatePresets(t *testing.T) {
+	cfg := env.CatalogConfig{
+		ID:                   "oci",
+		Type:                 env.CatalogTypeOCI,
+		Name:                 map[string]string{"en": "OCI"},
+		Status:               env.StatusProd,
+		MultipleServicesMode: env.MultipleServicesAll,
+		Location:             "oci://registry.example.org/charts",
+		Packages:             []env.OCIPackage{{Name: "postgres", Versions: []string{"1.0.0"}}},
+	}
+	preset := env.Preset{
+		ID:      "small",
+		Name:    map[string]string{"en": "Small database"},
+		Package: "postgres",
+		Version: "1.0.0",
+		Values:  "resources:\n  memory: 1Gi\n",
+	}
+
+	cfg.Presets = []env.Preset{preset}
+	assert.NoError(t, env.ValidateCatalogConfig(cfg))
+
+	cfg.Presets = []env.Preset{preset, preset}
+	assert.ErrorContains(t, env.ValidateCatalogConfig(cfg), "duplicate")
+
+	unknown := preset
+	unknown.Version = "2.0.0"
+	cfg.Presets = []env.Preset{unknown}
+	assert.ErrorContains(t, env.ValidateCatalogConfig(cfg), `unknown version "2.0.0"`)
+
+	broken := preset
+	broken.Values = "resources: [memory"
+	cfg.Presets = []env.Preset{broken}
+	assert.ErrorContains(t, env.ValidateCatalogConfig(cfg), "invalid values")
+}
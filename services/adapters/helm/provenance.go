@@ -0,0 +1,256 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// chartSource tells where the archive of a chart version is fetched from.
+type chartSource struct {
+	url      string // archive URL, OCI reference with its tag, or local path
+	local    bool
+	filename string // archive name signed in the provenance file
+	digest   string // archive digest from the index, when known
+}
+
+// locateChart finds where a chart version is published in its catalog.
+func (h *HelmPackageRepository) locateChart(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
+) (chartSource, error) {
+	if cfg.Type == env.CatalogTypeOCI {
+		pv, err := resolveOCIPackage(cfg, name, version)
+		if err != nil {
+			return chartSource{}, err
+		}
+		return chartSource{
+			url:      pv.ChartRef() + ":" + version,
+			filename: fmt.Sprintf("%s-%s.tgz", name, version),
+		}, nil
+	}
+
+	idx, err := h.loadHelmIndex(ctx, cfg)
+	if err != nil {
+		return chartSource{}, err
+	}
+	cv, err := idx.Get(name, version)
+	if err != nil || len(cv.URLs) == 0 {
+		return chartSource{}, fmt.Errorf(
+			"%w: version %q not found for chart %q in catalog %q",
+			domain.ErrNotFound, version, name, cfg.ID,
+		)
+	}
+
+	if cfg.Type == env.CatalogTypeDirectory {
+		return chartSource{
+			url:      cv.URLs[0],
+			local:    true,
+			filename: filepath.Base(cv.URLs[0]),
+			digest:   cv.Digest,
+		}, nil
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(cfg.Location, cv.URLs[0])
+	if err != nil {
+		return chartSource{}, fmt.Errorf("invalid chart URL for %q: %w", name, err)
+	}
+	filename := path.Base(chartURL)
+	if u, err := url.Parse(chartURL); err == nil {
+		filename = path.Base(u.Path)
+	}
+	return chartSource{url: chartURL, filename: filename, digest: cv.Digest}, nil
+}
+
+// verificationRetry is the shortest time a provenance check that could not
+// be settled is kept, so that a zero indexTTL does not download the archive
+// and its signature on every request.
+const verificationRetry = time.Minute
+
+// errSignatureRejected marks checks that fetched the archive and its
+// provenance file, and found the signature invalid.
+var errSignatureRejected = errors.New("signature rejected")
+
+// verification is the cached outcome of a provenance check.
+type verification struct {
+	err       error
+	checkedAt time.Time
+	// permanent checks are kept for the life of the process.
+	permanent bool
+}
+
+// verifications caches provenance checks, since each one downloads the chart
+// archive and its signature.
+type verifications struct {
+	mu      sync.Mutex
+	results map[string]verification
+}
+
+func (v *verifications) get(key string, ttl time.Duration, now time.Time) (error, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	r, ok := v.results[key]
+	if !ok || (!r.permanent && now.Sub(r.checkedAt) >= max(ttl, verificationRetry)) {
+		return nil, false
+	}
+	return r.err, true
+}
+
+func (v *verifications) put(key string, r verification) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.results == nil {
+		v.results = make(map[string]verification)
+	}
+	v.results[key] = r
+}
+
+// verifyChart checks the provenance of a chart version against the catalog
+// keyring. It returns nil when the catalog does not require verification.
+// Settled checks of archives known by digest are never repeated, since the
+// digest identifies the archive; other checks are repeated after the index
+// TTL.
+func (h *HelmPackageRepository) verifyChart(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	name, version string,
+) error {
+	sig, ok := h.signatories[cfg.ID]
+	if !ok {
+		return nil
+	}
+
+	src, err := h.locateChart(ctx, cfg, name, version)
+	if err != nil {
+		return err
+	}
+
+	key := cfg.ID + "|" + src.url
+	if src.digest != "" {
+		key = cfg.ID + "|" + src.filename + "@" + src.digest
+	}
+	if err, ok := h.verified.get(key, h.indexTTL, time.Now()); ok {
+		return err
+	}
+
+	err = h.checkProvenance(ctx, cfg, sig, src)
+	if err != nil {
+		slog.WarnContext(ctx, "Chart provenance verification failed",
+			slog.String("catalog", cfg.ID),
+			slog.String("package", name),
+			slog.String("version", version),
+			slog.Any("error", err),
+		)
+	}
+	settled := err == nil || errors.Is(err, errSignatureRejected)
+	h.verified.put(key, verification{
+		err:       err,
+		checkedAt: time.Now(),
+		permanent: settled && src.digest != "",
+	})
+	return err
+}
+
+func (h *HelmPackageRepository) checkProvenance(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	sig *provenance.Signatory,
+	src chartSource,
+) error {
+	var archive []byte
+	if src.local {
+		if !strings.HasSuffix(src.url, ".tgz") {
+			return errors.New("unpacked charts cannot be verified")
+		}
+		var err error
+		if archive, err = os.ReadFile(src.url); err != nil {
+			return fmt.Errorf("reading chart archive: %w", err)
+		}
+	} else {
+		opts, err := h.getterOptions(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		buf, err := h.fetch(src.url, opts...)
+		if err != nil {
+			return fmt.Errorf("downloading chart: %w", err)
+		}
+		archive = buf
+	}
+	prov, err := h.provenanceFile(ctx, cfg, src)
+	if err != nil {
+		return err
+	}
+
+	if _, err := sig.Verify(archive, prov, src.filename); err != nil {
+		return fmt.Errorf("verifying provenance: %w: %w", errSignatureRejected, err)
+	}
+	return nil
+}
+
+// provenanceFile returns the provenance file stored next to a chart archive.
+func (h *HelmPackageRepository) provenanceFile(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	src chartSource,
+) ([]byte, error) {
+	if src.local {
+		prov, err := os.ReadFile(src.url + ".prov")
+		if err != nil {
+			return nil, fmt.Errorf("reading provenance file: %w", err)
+		}
+		return prov, nil
+	}
+	opts, err := h.getterOptions(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	prov, err := h.fetch(src.url+".prov", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("downloading provenance file: %w", err)
+	}
+	return prov, nil
+}
+
+// applyVerification checks a resolved chart before install. In strict mode
+// an unverified chart is refused; in warn mode it is flagged. Verified charts
+// carry the keyring so Helm checks them again when it fetches them.
+func (h *HelmPackageRepository) applyVerification(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	pv domain.PackageVersion,
+) (domain.PackageVersion, error) {
+	err := h.verifyChart(ctx, cfg, pv.Name, pv.Version)
+	switch {
+	case err == nil:
+		if _, ok := h.signatories[cfg.ID]; ok {
+			pv.Keyring = tools.Deref(cfg.Keyring)
+		}
+		return pv, nil
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.PackageVersion{}, err
+	case cfg.Verify == env.VerifyStrict:
+		return domain.PackageVersion{}, fmt.Errorf(
+			"%w: chart %q version %q failed provenance verification: %v",
+			domain.ErrForbidden, pv.Name, pv.Version, err,
+		)
+	default:
+		pv.Unverified = true
+		return pv, nil
+	}
+}
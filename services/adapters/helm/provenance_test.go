@@ -0,0 +1,299 @@
+package helm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// testSigner signs chart archives with a key written to keyring.
+type testSigner struct {
+	entity  *openpgp.Entity
+	keyring string
+}
+
+func newTestSigner(t *testing.T) testSigner {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Onyxia test", "", "test@onyxia.sh", nil)
+	require.NoError(t, err)
+	keyring := filepath.Join(t.TempDir(), "pubring.gpg")
+	f, err := os.Create(keyring)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(f))
+	require.NoError(t, f.Close())
+	return testSigner{entity: entity, keyring: keyring}
+}
+
+// sign returns the provenance file of the archive at path.
+func (s testSigner) sign(t *testing.T, path string) []byte {
+	t.Helper()
+	archive, err := os.ReadFile(path)
+	require.NoError(t, err)
+	prov, err := (&provenance.Signatory{Entity: s.entity}).
+		ClearSign(archive, filepath.Base(path), []byte("name: chart\n"))
+	require.NoError(t, err)
+	return []byte(prov)
+}
+
+// saveTestChart packages a chart version into dir.
+func saveTestChart(t *testing.T, dir, name, version, description string) string {
+	t.Helper()
+	path, err := chartutil.Save(&chartv2.Chart{Metadata: &chartv2.Metadata{
+		APIVersion:  chartv2.APIVersionV2,
+		Name:        name,
+		Version:     version,
+		Description: description,
+	}}, dir)
+	require.NoError(t, err)
+	return path
+}
+
+func TestDirectoryCatalog_Provenance(t *testing.T) {
+	dir := t.TempDir()
+	signer := newTestSigner(t)
+
+	signed := saveTestChart(t, dir, "signed", "1.0.0", "")
+	require.NoError(t, os.WriteFile(signed+".prov", signer.sign(t, signed), 0o644))
+	saveTestChart(t, dir, "unsigned", "1.0.0", "")
+
+	adapterFor := func(t *testing.T, mode env.VerifyMode) (*HelmPackageRepository, env.CatalogConfig) {
+		t.Helper()
+		cfg := env.CatalogConfig{
+			ID:       "local",
+			Type:     env.CatalogTypeDirectory,
+			Location: dir,
+			Verify:   mode,
+			Keyring:  &signer.keyring,
+		}
+		repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", time.Hour, nil)
+		require.NoError(t, err)
+		return repoAdapter, cfg
+	}
+	ctx := context.Background()
+
+	t.Run("strict hides and refuses unverified charts", func(t *testing.T) {
+		repoAdapter, cfg := adapterFor(t, env.VerifyStrict)
+
+		pkg, err := repoAdapter.GetPackage(ctx, cfg.ID, "unsigned")
+		require.NoError(t, err)
+		assert.Empty(t, pkg.Versions)
+
+		_, err = repoAdapter.ResolvePackage(ctx, cfg.ID, "unsigned", "1.0.0")
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+
+		resolved, err := repoAdapter.ResolvePackage(ctx, cfg.ID, "signed", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, signer.keyring, resolved.Keyring)
+		assert.False(t, resolved.Unverified)
+	})
+
+	t.Run("warn flags unverified charts", func(t *testing.T) {
+		repoAdapter, cfg := adapterFor(t, env.VerifyWarn)
+
+		pkg, err := repoAdapter.GetPackage(ctx, cfg.ID, "unsigned")
+		require.NoError(t, err)
+		require.Len(t, pkg.Versions, 1)
+		assert.True(t, pkg.Versions[0].Unverified)
+
+		resolved, err := repoAdapter.ResolvePackage(ctx, cfg.ID, "unsigned", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, resolved.Unverified)
+		assert.Empty(t, resolved.Keyring)
+	})
+}
+
+func TestHelmRepo_Provenance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	dir := t.TempDir()
+	signer := newTestSigner(t)
+	for _, c := range []struct{ name, version, description string }{
+		{"signed", "1.0.0", ""},
+		{"mixed", "1.0.0", "signed version"},
+	} {
+		path := saveTestChart(t, dir, c.name, c.version, c.description)
+		require.NoError(t, os.WriteFile(path+".prov", signer.sign(t, path), 0o644))
+	}
+	saveTestChart(t, dir, "mixed", "2.0.0", "unsigned version")
+	saveTestChart(t, dir, "unsigned", "1.0.0", "")
+
+	var downloads atomic.Int32
+	files := http.FileServer(http.Dir(dir))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".tgz") || strings.HasSuffix(r.URL.Path, ".prov") {
+			downloads.Add(1)
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	idx, err := repo.IndexDirectory(dir, srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, idx.WriteFile(filepath.Join(dir, "index.yaml"), 0o644))
+
+	adapterFor := func(t *testing.T, mode env.VerifyMode) *HelmPackageRepository {
+		t.Helper()
+		cfg := env.CatalogConfig{
+			ID:       "helm",
+			Type:     env.CatalogTypeHelmRepo,
+			Location: srv.URL,
+			Verify:   mode,
+			Keyring:  &signer.keyring,
+		}
+		repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, t.TempDir(), 0, nil)
+		require.NoError(t, err)
+		return repoAdapter
+	}
+	ctx := context.Background()
+
+	t.Run("strict lists and installs verified versions only", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.VerifyStrict)
+
+		pkgs, err := repoAdapter.ListPackages(ctx, "helm")
+		require.NoError(t, err)
+		descriptions := map[string]string{}
+		for _, p := range pkgs {
+			descriptions[p.Name] = p.Description
+		}
+		assert.Equal(t, map[string]string{"signed": "", "mixed": "signed version"}, descriptions)
+
+		pkg, err := repoAdapter.GetPackage(ctx, "helm", "mixed")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.0.0"}, pkg.VersionNames())
+		assert.Equal(t, "signed version", pkg.Description)
+
+		_, err = repoAdapter.ResolvePackage(ctx, "helm", "mixed", "2.0.0")
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+
+		resolved, err := repoAdapter.ResolvePackage(ctx, "helm", "signed", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, signer.keyring, resolved.Keyring)
+	})
+
+	t.Run("warn flags unverified versions", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.VerifyWarn)
+
+		pkgs, err := repoAdapter.ListPackages(ctx, "helm")
+		require.NoError(t, err)
+		assert.Len(t, pkgs, 3)
+
+		pkg, err := repoAdapter.GetPackage(ctx, "helm", "mixed")
+		require.NoError(t, err)
+		require.Equal(t, []string{"2.0.0", "1.0.0"}, pkg.VersionNames())
+		assert.True(t, pkg.Versions[0].Unverified)
+		assert.False(t, pkg.Versions[1].Unverified)
+	})
+
+	t.Run("checks are kept by digest despite a zero index TTL", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.VerifyStrict)
+
+		_, err := repoAdapter.GetPackage(ctx, "helm", "mixed")
+		require.NoError(t, err)
+		before := downloads.Load()
+
+		_, err = repoAdapter.GetPackage(ctx, "helm", "mixed")
+		require.NoError(t, err)
+		assert.Equal(t, before, downloads.Load())
+	})
+}
+
+func TestOCICatalog_Provenance(t *testing.T) {
+	dir := t.TempDir()
+	signer := newTestSigner(t)
+	const location = "oci://registry.example.com/charts"
+
+	charts := fakeOCIGetter{}
+	add := func(name, version string, signed bool) {
+		path := saveTestChart(t, dir, name, version, "")
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		ref := location + "/" + name + ":" + version
+		charts[ref] = data
+		if signed {
+			charts[ref+".prov"] = signer.sign(t, path)
+		}
+	}
+	add("signed", "1.0.0", true)
+	add("mixed", "1.0.0", true)
+	add("mixed", "2.0.0", false)
+	add("unsigned", "1.0.0", false)
+
+	adapterFor := func(t *testing.T, mode env.VerifyMode) *HelmPackageRepository {
+		t.Helper()
+		cfg := env.CatalogConfig{
+			ID:       "oci",
+			Type:     env.CatalogTypeOCI,
+			Location: location,
+			Verify:   mode,
+			Keyring:  &signer.keyring,
+			Packages: []env.OCIPackage{
+				{Name: "signed", Versions: []string{"1.0.0"}},
+				{Name: "mixed", Versions: []string{"1.0.0", "2.0.0"}},
+				{Name: "unsigned", Versions: []string{"1.0.0"}},
+			},
+		}
+		repoAdapter, err := NewPackageRepository([]env.CatalogConfig{cfg}, "", 0, nil)
+		require.NoError(t, err)
+		repoAdapter.getters = getter.Providers{{
+			Schemes: []string{"oci"},
+			New:     func(...getter.Option) (getter.Getter, error) { return charts, nil },
+		}}
+		return repoAdapter
+	}
+	ctx := context.Background()
+
+	t.Run("strict lists and installs verified versions only", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.VerifyStrict)
+
+		pkgs, err := repoAdapter.ListPackages(ctx, "oci")
+		require.NoError(t, err)
+		names := make([]string, 0, len(pkgs))
+		for _, p := range pkgs {
+			names = append(names, p.Name)
+		}
+		assert.ElementsMatch(t, []string{"signed", "mixed"}, names)
+
+		pkg, err := repoAdapter.GetPackage(ctx, "oci", "mixed")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.0.0"}, pkg.VersionNames())
+
+		_, err = repoAdapter.ResolvePackage(ctx, "oci", "unsigned", "1.0.0")
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+
+		resolved, err := repoAdapter.ResolvePackage(ctx, "oci", "signed", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, signer.keyring, resolved.Keyring)
+	})
+
+	t.Run("warn flags unverified versions", func(t *testing.T) {
+		repoAdapter := adapterFor(t, env.VerifyWarn)
+
+		pkg, err := repoAdapter.GetPackage(ctx, "oci", "mixed")
+		require.NoError(t, err)
+		require.Equal(t, []string{"2.0.0", "1.0.0"}, pkg.VersionNames())
+		assert.True(t, pkg.Versions[0].Unverified)
+		assert.False(t, pkg.Versions[1].Unverified)
+
+		resolved, err := repoAdapter.ResolvePackage(ctx, "oci", "unsigned", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, resolved.Unverified)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"helm.sh/helm/v4/pkg/cli"
 	"helm.sh/helm/v4/pkg/cli/values"
 	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/registry"
 	"k8s.io/client-go/rest"
 )
 
@@ -66,6 +67,19 @@ func (i *Helm) StartInstall(
 	act.ReleaseName = releaseName
 	act.Namespace = i.settings.Namespace()
 	act.Version = pkg.Version
+	if pkg.Keyring != "" {
+		act.Verify = true
+		act.Keyring = pkg.Keyring
+	}
+	act.Username = pkg.Credentials.Username
+	act.Password = pkg.Credentials.Password
+	if registry.IsOCI(chartRef) {
+		rc, err := newRegistryClient(pkg.Credentials)
+		if err != nil {
+			return fmt.Errorf("creating registry client: %w", err)
+		}
+		act.SetRegistryClient(rc)
+	}
 
 	chartPath, err := act.LocateChart(chartRef, i.settings)
 	if err != nil {
@@ -120,3 +134,13 @@ func (i *Helm) StartInstall(
 
 	return nil
 }
+
+// newRegistryClient returns an OCI registry client authenticated with creds,
+// built for each install so rotated credentials are used.
+func newRegistryClient(creds domain.Credentials) (*registry.Client, error) {
+	var opts []registry.ClientOption
+	if creds.Username != "" {
+		opts = append(opts, registry.ClientOptBasicAuth(creds.Username, creds.Password))
+	}
+	return registry.NewClient(opts...)
+}
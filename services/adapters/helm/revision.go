@@ -0,0 +1,72 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"helm.sh/helm/v4/pkg/repo/v1"
+)
+
+// CatalogRevision returns the revision of the index behind a catalog. OCI
+// catalogs list their packages in the configuration, so their revision only
+// changes with it.
+func (h *HelmPackageRepository) CatalogRevision(
+	ctx context.Context,
+	catalogID string,
+) (string, error) {
+	cfg, ok := h.catalogs[catalogID]
+	if !ok {
+		return "", fmt.Errorf("%w: catalog %q not found", domain.ErrNotFound, catalogID)
+	}
+
+	switch cfg.Type {
+	case env.CatalogTypeHelmRepo, env.CatalogTypeDirectory:
+		if _, err := h.loadHelmIndex(ctx, cfg); err != nil {
+			return "", err
+		}
+		cache := h.indexes[cfg.ID]
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return cache.revision, nil
+	case env.CatalogTypeOCI:
+		sum := sha256.New()
+		for _, p := range cfg.Packages {
+			fmt.Fprintf(sum, "%s\x00", p.Name)
+		}
+		return hex.EncodeToString(sum.Sum(nil)), nil
+	default:
+		return "", fmt.Errorf("unsupported catalog type: %v", cfg.Type)
+	}
+}
+
+// indexRevision hashes the content of an index that ends up in catalog
+// listings. Unlike the generated timestamp of the index, it only changes when
+// charts do.
+func indexRevision(idx *repo.IndexFile) string {
+	names := make([]string, 0, len(idx.Entries))
+	for name := range idx.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sum := sha256.New()
+	for _, name := range names {
+		io.WriteString(sum, name)
+		for _, cv := range idx.Entries[name] {
+			if cv == nil || cv.Metadata == nil {
+				continue
+			}
+			fmt.Fprintf(sum, "\x00%s\x00%s\x00%s\x00%t\x00%s\x00%s\x00%s\x00%q",
+				cv.Version, cv.Digest, cv.Created.UTC(), cv.Deprecated,
+				cv.Description, cv.Icon, cv.Home, cv.Keywords)
+		}
+		io.WriteString(sum, "\n")
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
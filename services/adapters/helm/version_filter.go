@@ -2,39 +2,42 @@ package helm
 
 import (
 	"fmt"
-	"strings"
+	"sort"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
 )
 
+// versionFilter selects the versions to expose from a list sorted newest-first.
 type versionFilter interface {
-	apply(versions []string) []string
+	apply(versions []*semver.Version) []*semver.Version
 }
 
 type allVersions struct{}
 type latestOnly struct{}
 type skipPatches struct{}
 type maxNumber struct{ n int }
+type latestMinors struct{ n int }
+type noPrereleases struct{}
+type excludeConstraint struct{ c *semver.Constraints }
 
-func (allVersions) apply(versions []string) []string { return versions }
+// chain applies each filter in turn, feeding the output of one into the next.
+type chain []versionFilter
 
-func (latestOnly) apply(versions []string) []string {
+func (allVersions) apply(versions []*semver.Version) []*semver.Version { return versions }
+
+func (latestOnly) apply(versions []*semver.Version) []*semver.Version {
 	if len(versions) == 0 {
 		return versions
 	}
 	return versions[:1]
 }
 
-func (skipPatches) apply(versions []string) []string {
-	seen := make(map[string]bool)
-	out := make([]string, 0, len(versions))
+func (skipPatches) apply(versions []*semver.Version) []*semver.Version {
+	seen := make(map[[2]uint64]bool)
+	out := make([]*semver.Version, 0, len(versions))
 	for _, v := range versions {
-		parts := strings.SplitN(v, ".", 3)
-		if len(parts) < 2 {
-			out = append(out, v)
-			continue
-		}
-		minor := parts[0] + "." + parts[1]
+		minor := [2]uint64{v.Major(), v.Minor()}
 		if seen[minor] {
 			continue
 		}
@@ -44,14 +47,113 @@ func (skipPatches) apply(versions []string) []string {
 	return out
 }
 
-func (f maxNumber) apply(versions []string) []string {
+func (f maxNumber) apply(versions []*semver.Version) []*semver.Version {
 	if f.n >= len(versions) {
 		return versions
 	}
 	return versions[:f.n]
 }
 
+// apply keeps every version belonging to one of the n most recent minor lines.
+func (f latestMinors) apply(versions []*semver.Version) []*semver.Version {
+	seen := make(map[[2]uint64]bool)
+	out := make([]*semver.Version, 0, len(versions))
+	for _, v := range versions {
+		minor := [2]uint64{v.Major(), v.Minor()}
+		if !seen[minor] {
+			if len(seen) == f.n {
+				continue
+			}
+			seen[minor] = true
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func (noPrereleases) apply(versions []*semver.Version) []*semver.Version {
+	out := make([]*semver.Version, 0, len(versions))
+	for _, v := range versions {
+		if v.Prerelease() == "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (f excludeConstraint) apply(versions []*semver.Version) []*semver.Version {
+	out := make([]*semver.Version, 0, len(versions))
+	for _, v := range versions {
+		if !f.c.Check(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (c chain) apply(versions []*semver.Version) []*semver.Version {
+	for _, f := range c {
+		versions = f.apply(versions)
+	}
+	return versions
+}
+
+// parseVersions parses raw versions and sorts them newest-first by semver
+// precedence. Versions that are not valid semver are returned separately.
+func parseVersions(raw []string) ([]*semver.Version, []string) {
+	parsed := make([]*semver.Version, 0, len(raw))
+	var invalid []string
+	for _, r := range raw {
+		v, err := semver.NewVersion(r)
+		if err != nil {
+			invalid = append(invalid, r)
+			continue
+		}
+		parsed = append(parsed, v)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(parsed)))
+	return parsed, invalid
+}
+
+// filterVersions sorts raw versions and applies f. It returns the kept versions
+// in their original spelling, and the versions that could not be parsed.
+func filterVersions(f versionFilter, raw []string) ([]string, []string) {
+	parsed, invalid := parseVersions(raw)
+	kept := f.apply(parsed)
+	out := make([]string, 0, len(kept))
+	for _, v := range kept {
+		out = append(out, v.Original())
+	}
+	return out, invalid
+}
+
 func versionFilterFrom(cfg env.CatalogConfig) (versionFilter, error) {
+	var filters chain
+
+	if cfg.HidePrereleases {
+		filters = append(filters, noPrereleases{})
+	}
+
+	if cfg.ExcludedVersions != "" {
+		c, err := semver.NewConstraint(cfg.ExcludedVersions)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %q: invalid excludedVersions %q: %w", cfg.ID, cfg.ExcludedVersions, err)
+		}
+		filters = append(filters, excludeConstraint{c: c})
+	}
+
+	mode, err := modeFilterFrom(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filters) == 0 {
+		return mode, nil
+	}
+	return append(filters, mode), nil
+}
+
+func modeFilterFrom(cfg env.CatalogConfig) (versionFilter, error) {
 	switch cfg.MultipleServicesMode {
 	case env.MultipleServicesLatest:
 		return latestOnly{}, nil
@@ -62,6 +164,11 @@ func versionFilterFrom(cfg env.CatalogConfig) (versionFilter, error) {
 			return nil, fmt.Errorf("catalog %q: multipleServicesMode=maxNumber requires maxNumberOfVersions", cfg.ID)
 		}
 		return maxNumber{n: *cfg.MaxNumberOfVersions}, nil
+	case env.MultipleServicesLatestMinors:
+		if cfg.MaxNumberOfMinors == nil {
+			return nil, fmt.Errorf("catalog %q: multipleServicesMode=latestMinors requires maxNumberOfMinors", cfg.ID)
+		}
+		return latestMinors{n: *cfg.MaxNumberOfMinors}, nil
 	default: // "all" or unset
 		return allVersions{}, nil
 	}
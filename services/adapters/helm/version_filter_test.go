@@ -3,6 +3,7 @@ package helm
 import (
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,64 +11,142 @@ import (
 
 func ptr(n int) *int { return &n }
 
+// applyTo runs f on raw versions and returns the kept ones as strings.
+func applyTo(t *testing.T, f versionFilter, raw ...string) []string {
+	t.Helper()
+	kept, invalid := filterVersions(f, raw)
+	require.Empty(t, invalid)
+	return kept
+}
+
+// ---------- parseVersions ----------
+
+func TestParseVersions_SortsBySemverPrecedence(t *testing.T) {
+	parsed, invalid := parseVersions([]string{"1.9.0", "1.10.0", "2.0.0-rc.1", "2.0.0", "1.2.3"})
+	require.Empty(t, invalid)
+
+	got := make([]string, 0, len(parsed))
+	for _, v := range parsed {
+		got = append(got, v.Original())
+	}
+	assert.Equal(t, []string{"2.0.0", "2.0.0-rc.1", "1.10.0", "1.9.0", "1.2.3"}, got)
+}
+
+func TestParseVersions_ReportsInvalid(t *testing.T) {
+	parsed, invalid := parseVersions([]string{"latest", "1.0.0", "not.a.version"})
+	assert.Len(t, parsed, 1)
+	assert.Equal(t, []string{"latest", "not.a.version"}, invalid)
+}
+
+func TestFilterVersions_KeepsOriginalSpelling(t *testing.T) {
+	kept, invalid := filterVersions(allVersions{}, []string{"v1.0", "v2.0.0"})
+	require.Empty(t, invalid)
+	assert.Equal(t, []string{"v2.0.0", "v1.0"}, kept)
+}
+
 // ---------- allVersions ----------
 
 func TestAllVersions_ReturnsAll(t *testing.T) {
-	versions := []string{"1.2.3", "1.1.0", "1.0.0"}
-	assert.Equal(t, versions, allVersions{}.apply(versions))
+	result := applyTo(t, allVersions{}, "1.2.3", "1.1.0", "1.0.0")
+	assert.Equal(t, []string{"1.2.3", "1.1.0", "1.0.0"}, result)
 }
 
 func TestAllVersions_Empty(t *testing.T) {
-	assert.Empty(t, allVersions{}.apply(nil))
+	assert.Empty(t, applyTo(t, allVersions{}))
 }
 
 // ---------- latestOnly ----------
 
 func TestLatestOnly_ReturnsSingle(t *testing.T) {
-	result := latestOnly{}.apply([]string{"2.0.0", "1.9.0", "1.0.0"})
+	result := applyTo(t, latestOnly{}, "2.0.0", "1.9.0", "1.0.0")
+	assert.Equal(t, []string{"2.0.0"}, result)
+}
+
+func TestLatestOnly_UnsortedInput(t *testing.T) {
+	// OCI versions come in config order, not newest-first.
+	result := applyTo(t, latestOnly{}, "1.0.0", "2.0.0", "1.9.0")
 	assert.Equal(t, []string{"2.0.0"}, result)
 }
 
 func TestLatestOnly_Empty(t *testing.T) {
-	assert.Empty(t, latestOnly{}.apply(nil))
+	assert.Empty(t, applyTo(t, latestOnly{}))
 }
 
 // ---------- skipPatches ----------
 
 func TestSkipPatches_KeepsLatestPerMinor(t *testing.T) {
-	// Helm index is sorted newest-first, so first occurrence of each minor wins.
-	input := []string{"1.2.3", "1.2.1", "1.1.5", "1.1.0", "1.0.0"}
-	result := skipPatches{}.apply(input)
+	result := applyTo(t, skipPatches{}, "1.2.3", "1.2.1", "1.1.5", "1.1.0", "1.0.0")
 	assert.Equal(t, []string{"1.2.3", "1.1.5", "1.0.0"}, result)
 }
 
-func TestSkipPatches_NonSemver_PassedThrough(t *testing.T) {
-	result := skipPatches{}.apply([]string{"latest", "1.0.0"})
-	assert.Equal(t, []string{"latest", "1.0.0"}, result)
+func TestSkipPatches_UnsortedInput(t *testing.T) {
+	result := applyTo(t, skipPatches{}, "1.1.0", "1.2.1", "1.1.5", "1.2.3")
+	assert.Equal(t, []string{"1.2.3", "1.1.5"}, result)
+}
+
+func TestSkipPatches_DoubleDigitMinors(t *testing.T) {
+	result := applyTo(t, skipPatches{}, "1.9.4", "1.10.0", "1.10.2")
+	assert.Equal(t, []string{"1.10.2", "1.9.4"}, result)
 }
 
 func TestSkipPatches_Empty(t *testing.T) {
-	assert.Empty(t, skipPatches{}.apply(nil))
+	assert.Empty(t, applyTo(t, skipPatches{}))
 }
 
 // ---------- maxNumber ----------
 
 func TestMaxNumber_Truncates(t *testing.T) {
-	result := maxNumber{n: 2}.apply([]string{"1.2.0", "1.1.0", "1.0.0"})
+	result := applyTo(t, maxNumber{n: 2}, "1.2.0", "1.1.0", "1.0.0")
 	assert.Equal(t, []string{"1.2.0", "1.1.0"}, result)
 }
 
 func TestMaxNumber_NGreaterThanLen_ReturnsAll(t *testing.T) {
-	versions := []string{"1.0.0"}
-	result := maxNumber{n: 10}.apply(versions)
-	assert.Equal(t, versions, result)
+	result := applyTo(t, maxNumber{n: 10}, "1.0.0")
+	assert.Equal(t, []string{"1.0.0"}, result)
 }
 
 func TestMaxNumber_Zero_ReturnsEmpty(t *testing.T) {
-	result := maxNumber{n: 0}.apply([]string{"1.0.0", "2.0.0"})
+	result := applyTo(t, maxNumber{n: 0}, "1.0.0", "2.0.0")
 	assert.Empty(t, result)
 }
 
+// ---------- latestMinors ----------
+
+func TestLatestMinors_KeepsAllPatchesOfNewestMinors(t *testing.T) {
+	result := applyTo(t, latestMinors{n: 2}, "1.2.3", "1.2.1", "1.1.5", "1.1.0", "1.0.0")
+	assert.Equal(t, []string{"1.2.3", "1.2.1", "1.1.5", "1.1.0"}, result)
+}
+
+func TestLatestMinors_NGreaterThanMinors_ReturnsAll(t *testing.T) {
+	result := applyTo(t, latestMinors{n: 5}, "2.0.0", "1.0.0")
+	assert.Equal(t, []string{"2.0.0", "1.0.0"}, result)
+}
+
+// ---------- noPrereleases ----------
+
+func TestNoPrereleases_DropsPrereleases(t *testing.T) {
+	result := applyTo(t, noPrereleases{}, "2.0.0-rc.1", "1.1.0", "1.1.0-beta", "1.0.0")
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, result)
+}
+
+// ---------- excludeConstraint ----------
+
+func TestExcludeConstraint_DropsMatchingVersions(t *testing.T) {
+	c, err := semver.NewConstraint("< 1.1.0 || 2.0.x")
+	require.NoError(t, err)
+
+	result := applyTo(t, excludeConstraint{c: c}, "2.1.0", "2.0.3", "1.1.0", "1.0.0")
+	assert.Equal(t, []string{"2.1.0", "1.1.0"}, result)
+}
+
+// ---------- chain ----------
+
+func TestChain_AppliesInOrder(t *testing.T) {
+	f := chain{noPrereleases{}, latestOnly{}}
+	result := applyTo(t, f, "2.0.0-rc.1", "1.1.0", "1.0.0")
+	assert.Equal(t, []string{"1.1.0"}, result)
+}
+
 // ---------- versionFilterFrom ----------
 
 func TestVersionFilterFrom_All(t *testing.T) {
@@ -113,3 +192,42 @@ func TestVersionFilterFrom_MaxNumber_MissingN_ReturnsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "my-catalog")
 	assert.Contains(t, err.Error(), "maxNumberOfVersions")
 }
+
+func TestVersionFilterFrom_LatestMinors(t *testing.T) {
+	f, err := versionFilterFrom(env.CatalogConfig{
+		MultipleServicesMode: env.MultipleServicesLatestMinors,
+		MaxNumberOfMinors:    ptr(2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, latestMinors{n: 2}, f)
+}
+
+func TestVersionFilterFrom_LatestMinors_MissingN_ReturnsError(t *testing.T) {
+	_, err := versionFilterFrom(env.CatalogConfig{
+		ID:                   "my-catalog",
+		MultipleServicesMode: env.MultipleServicesLatestMinors,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maxNumberOfMinors")
+}
+
+func TestVersionFilterFrom_HidePrereleasesAndExcluded(t *testing.T) {
+	f, err := versionFilterFrom(env.CatalogConfig{
+		MultipleServicesMode: env.MultipleServicesLatest,
+		HidePrereleases:      true,
+		ExcludedVersions:     ">= 3.0.0",
+	})
+	require.NoError(t, err)
+
+	result := applyTo(t, f, "3.0.0", "2.1.0-rc.1", "2.0.0", "1.0.0")
+	assert.Equal(t, []string{"2.0.0"}, result)
+}
+
+func TestVersionFilterFrom_InvalidConstraint_ReturnsError(t *testing.T) {
+	_, err := versionFilterFrom(env.CatalogConfig{
+		ID:               "my-catalog",
+		ExcludedVersions: "not a constraint",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "excludedVersions")
+}
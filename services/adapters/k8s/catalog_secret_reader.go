@@ -0,0 +1,41 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var _ ports.CatalogSecretReader = (*K8sCatalogSecretReader)(nil)
+
+type K8sCatalogSecretReader struct {
+	client kubernetes.Interface
+}
+
+func NewCatalogSecretReader(client kubernetes.Interface) *K8sCatalogSecretReader {
+	return &K8sCatalogSecretReader{client: client}
+}
+
+func (r *K8sCatalogSecretReader) ReadSecret(
+	ctx context.Context,
+	namespace, name string,
+) (map[string][]byte, error) {
+	sec, err := r.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: secret %s/%s", domain.ErrNotFound, namespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if sec.Data == nil {
+		return map[string][]byte{}, nil
+	}
+	return sec.Data, nil
+}
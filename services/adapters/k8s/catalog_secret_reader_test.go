@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReadCatalogSecret(t *testing.T) {
+	ctx := context.Background()
+	cs := k8sfake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog-creds", Namespace: "onyxia"},
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("bob"),
+			corev1.BasicAuthPasswordKey: []byte("pw"),
+		},
+	})
+	r := NewCatalogSecretReader(cs)
+
+	data, err := r.ReadSecret(ctx, "onyxia", "catalog-creds")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bob"), data[corev1.BasicAuthUsernameKey])
+
+	_, err = r.ReadSecret(ctx, "onyxia", "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
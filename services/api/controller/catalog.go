@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
 
 	"github.com/go-faster/jx"
 	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
@@ -25,24 +28,65 @@ func NewCatalogController(
 	return &CatalogController{catalogs: catalogs, userReader: userReader}
 }
 
-func (cc *CatalogController) GetMyCatalogs(ctx context.Context) (api.GetMyCatalogsRes, error) {
-	slog.InfoContext(ctx, "GetMyCatalogs")
+// Cache-Control of catalog lists. The public list is the same for every
+// anonymous user and may be stored by shared caches; the others must be
+// revalidated by the browser only.
+const (
+	publicCatalogsCacheControl   = "public, max-age=60"
+	userCatalogsCacheControl     = "private, no-cache"
+	uncachedCatalogsCacheControl = "no-store"
+	catalogsVary                 = "Authorization, X-Onyxia-Project, Accept-Language"
+)
+
+func (cc *CatalogController) GetMyCatalogs(
+	ctx context.Context,
+	project string,
+	ifNoneMatch string,
+) (api.GetMyCatalogsRes, error) {
+	slog.InfoContext(ctx, "GetMyCatalogs", slog.String("project", project))
+
+	_, authenticated := cc.userReader.GetUser(ctx)
 
 	var (
-		catalogs []domain.Catalog
-		err      error
+		etag string
+		err  error
 	)
+	cacheControl := publicCatalogsCacheControl
+	if authenticated {
+		cacheControl = userCatalogsCacheControl
+		etag, err = cc.catalogs.UserCatalogsETag(ctx, project)
+	} else {
+		etag, err = cc.catalogs.PublicCatalogsETag(ctx)
+	}
+	if err != nil {
+		// Serve the catalogs anyway, just not cacheable.
+		slog.WarnContext(ctx, "Unable to compute catalogs ETag", slog.Any("error", err))
+		etag = ""
+	}
+	etag = localizedETag(ctx, etag)
 
-	if _, authenticated := cc.userReader.GetUser(ctx); authenticated {
-		catalogs, err = cc.catalogs.ListUserCatalogs(ctx)
+	if etag != "" && etagMatches(ifNoneMatch, etag) {
+		return &api.GetMyCatalogsNotModified{
+			Etag:         api.NewOptString(etag),
+			CacheControl: api.NewOptString(cacheControl),
+			Vary:         api.NewOptString(catalogsVary),
+		}, nil
+	}
+
+	// The list comes with the ETag of what it holds, which may differ from
+	// the one above if an index was refreshed meanwhile.
+	var catalogs []domain.Catalog
+	if authenticated {
+		catalogs, etag, err = cc.catalogs.ListUserCatalogs(ctx, project)
 	} else {
-		catalogs, err = cc.catalogs.ListPublicCatalogs(ctx)
+		catalogs, etag, err = cc.catalogs.ListPublicCatalogs(ctx)
 	}
+	etag = localizedETag(ctx, etag)
 
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to list catalogs", slog.String("error", err.Error()))
 		problem := &api.Problem{}
-		problem.Title.SetTo("Unable to list catalogs")
+		problem.Title.SetTo(problemTitle(ctx, "Unable to list catalogs"))
 		problem.Status.SetTo(500)
 		problem.Detail.SetTo(err.Error())
 		return problem, err
@@ -50,12 +94,21 @@ func (cc *CatalogController) GetMyCatalogs(ctx context.Context) (api.GetMyCatalo
 
 	slog.InfoContext(ctx, "Catalogs fetched", slog.Int("count", len(catalogs)))
 
-	response := make(api.GetMyCatalogsOKApplicationJSON, 0, len(catalogs))
+	response := make([]api.Catalog, 0, len(catalogs))
 
 	for _, catalog := range catalogs {
+		if catalog.Error != "" {
+			// A degraded list must not be cached.
+			etag = ""
+		}
+
 		apiCatalog := api.Catalog{
 			ID:                  catalog.ID,
 			HighlightedPackages: append([]string(nil), catalog.HighlightedPackages...),
+			Visible: api.NewOptCatalogVisible(api.CatalogVisible{
+				User:    catalog.Visible.User,
+				Project: catalog.Visible.Project,
+			}),
 		}
 
 		if len(catalog.Packages) > 0 {
@@ -64,33 +117,25 @@ func (cc *CatalogController) GetMyCatalogs(ctx context.Context) (api.GetMyCatalo
 				apiPackages = append(apiPackages, api.Package{
 					Name:        pkg.Name,
 					Description: api.NewOptString(pkg.Description),
-					Icon:        pkg.IconUrl,
+					Icon:        packageIconURL(catalog.ID, pkg),
 					Home:        api.NewOptURI(pkg.HomeUrl),
+					Deprecated:  api.NewOptBool(pkg.Deprecated),
+					Category:    optString(pkg.Category),
 				})
 			}
 			apiCatalog.Packages = apiPackages
 		}
 
-		if plainName, ok := catalog.Name.GetPlain(); ok {
-			apiCatalog.SetName(api.NewStringLocalizedString(plainName))
-		}
-
-		if multiName, ok := catalog.Name.GetMulti(); ok {
-			apiCatalog.SetName(
-				api.NewLocalizedString1LocalizedString(api.LocalizedString1(multiName)),
-			)
+		if catalog.Error != "" {
+			apiCatalog.Error = api.NewOptCatalogError(api.CatalogError(catalog.Error))
 		}
 
-		if plainDescription, ok := catalog.Description.GetPlain(); ok {
-			apiCatalog.SetDescription(
-				api.NewOptLocalizedString(api.NewStringLocalizedString(plainDescription)),
-			)
+		if name, ok := toAPILocalizedString(ctx, catalog.Name); ok {
+			apiCatalog.SetName(name)
 		}
 
-		if multiDescription, ok := catalog.Description.GetMulti(); ok {
-			apiCatalog.SetDescription(api.NewOptLocalizedString(
-				api.NewLocalizedString1LocalizedString(api.LocalizedString1(multiDescription))),
-			)
+		if description, ok := toAPILocalizedString(ctx, catalog.Description); ok {
+			apiCatalog.SetDescription(api.NewOptLocalizedString(description))
 		}
 
 		if status := api.CatalogStatus(catalog.Status); status != "" {
@@ -109,7 +154,29 @@ func (cc *CatalogController) GetMyCatalogs(ctx context.Context) (api.GetMyCatalo
 		response = append(response, apiCatalog)
 	}
 
-	return &response, nil
+	res := &api.GetMyCatalogsOKHeaders{
+		Vary:     api.NewOptString(catalogsVary),
+		Response: response,
+	}
+	if etag != "" {
+		res.Etag = api.NewOptString(etag)
+		res.CacheControl = api.NewOptString(cacheControl)
+	} else {
+		res.CacheControl = api.NewOptString(uncachedCatalogsCacheControl)
+	}
+	return res, nil
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 requires for GET.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
 }
 
 func (cc *CatalogController) GetMyPackage(
@@ -126,30 +193,115 @@ func (cc *CatalogController) GetMyPackage(
 
 	pkg, err := cc.catalogs.GetPackage(ctx, catalogID, packageName)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
+		// A forbidden catalog is reported as missing so its existence is not leaked.
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
 			problem := &api.GetMyPackageNotFound{}
-			problem.Title.SetTo("Not found")
+			problem.Title.SetTo(problemTitle(ctx, "Not found"))
 			problem.Status.SetTo(404)
 			problem.Detail.SetTo(err.Error())
 			return problem, nil
 		}
 		slog.ErrorContext(ctx, "Failed to get package", slog.String("error", err.Error()))
 		problem := &api.GetMyPackageInternalServerError{}
-		problem.Title.SetTo("Unable to get package")
+		problem.Title.SetTo(problemTitle(ctx, "Unable to get package"))
 		problem.Status.SetTo(500)
 		problem.Detail.SetTo(err.Error())
 		return problem, err
 	}
 
+	details := make([]api.PackageVersionDetails, 0, len(pkg.Versions))
+	for _, v := range pkg.Versions {
+		d := api.PackageVersionDetails{Version: v.Version}
+		if v.AppVersion != "" {
+			d.AppVersion.SetTo(v.AppVersion)
+		}
+		if !v.Created.IsZero() {
+			d.Created.SetTo(v.Created)
+		}
+		if v.Digest != "" {
+			d.Digest.SetTo(v.Digest)
+		}
+		if v.Deprecated {
+			d.Deprecated.SetTo(true)
+		}
+		if v.Unverified {
+			d.Unverified.SetTo(true)
+		}
+		details = append(details, d)
+	}
+
+	maintainers := make([]api.Maintainer, 0, len(pkg.Maintainers))
+	for _, m := range pkg.Maintainers {
+		maintainers = append(maintainers, api.Maintainer{
+			Name:  m.Name,
+			Email: api.NewOptString(m.Email),
+			URL:   api.NewOptString(m.URL),
+		})
+	}
+
 	return &api.DetailedPackage{
-		Name:        pkg.Name,
-		Description: api.NewOptString(pkg.Description),
-		Icon:        pkg.IconUrl,
-		Home:        api.NewOptURI(pkg.HomeUrl),
-		Versions:    pkg.Versions,
+		Name:           pkg.Name,
+		Description:    api.NewOptString(pkg.Description),
+		Icon:           packageIconURL(catalogID, pkg.Package),
+		Home:           api.NewOptURI(pkg.HomeUrl),
+		Versions:       pkg.VersionNames(),
+		VersionDetails: details,
+		AppVersion:     api.NewOptString(pkg.AppVersion),
+		Deprecated:     api.NewOptBool(pkg.Deprecated),
+		Category:       optString(pkg.Category),
+		Keywords:       pkg.Keywords,
+		Sources:        pkg.Sources,
+		Maintainers:    maintainers,
 	}, nil
 }
 
+func (cc *CatalogController) SearchPackages(
+	ctx context.Context,
+	project string,
+	query string,
+	limit int,
+) (api.SearchPackagesRes, error) {
+	slog.InfoContext(ctx, "SearchPackages",
+		slog.String("project", project),
+		slog.String("query", query),
+		slog.Int("limit", limit),
+	)
+
+	results, err := cc.catalogs.SearchPackages(ctx, project, query, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			problem := &api.SearchPackagesBadRequest{}
+			problem.Title.SetTo(problemTitle(ctx, "Invalid search query"))
+			problem.Status.SetTo(400)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		}
+		slog.ErrorContext(ctx, "Failed to search packages", slog.String("error", err.Error()))
+		problem := &api.SearchPackagesInternalServerError{}
+		problem.Title.SetTo(problemTitle(ctx, "Unable to search packages"))
+		problem.Status.SetTo(500)
+		problem.Detail.SetTo(err.Error())
+		return problem, err
+	}
+
+	response := make(api.SearchPackagesOKApplicationJSON, 0, len(results))
+	for _, r := range results {
+		response = append(response, api.PackageSearchResult{
+			Name:        r.Name,
+			Description: api.NewOptString(r.Description),
+			Icon:        packageIconURL(r.CatalogID, r.Package),
+			Home:        api.NewOptURI(r.HomeUrl),
+			Deprecated:  api.NewOptBool(r.Deprecated),
+			Category:    optString(r.Category),
+			CatalogId:   r.CatalogID,
+			Keywords:    r.Keywords,
+			Highlighted: api.NewOptBool(r.Highlighted),
+			Score:       r.Score,
+		})
+	}
+	return &response, nil
+}
+
 func (cc *CatalogController) GetPackageSchema(
 	ctx context.Context,
 	catalogID string,
@@ -164,22 +316,167 @@ func (cc *CatalogController) GetPackageSchema(
 
 	raw, err := cc.catalogs.GetPackageSchema(ctx, catalogID, packageName, version)
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			problem := &api.GetPackageSchemaNotFound{}
+			problem.Title.SetTo(problemTitle(ctx, "Not found"))
+			problem.Status.SetTo(404)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		}
 		slog.ErrorContext(ctx, "Failed to get package schema", slog.String("error", err.Error()))
 		problem := &api.GetPackageSchemaInternalServerError{}
-		problem.Title.SetTo("Unable to get package schema")
+		problem.Title.SetTo(problemTitle(ctx, "Unable to get package schema"))
 		problem.Status.SetTo(500)
 		problem.Detail.SetTo(err.Error())
 		return problem, err
 	}
 
-	var rawMap map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &rawMap); err != nil {
+	obj, err := rawObject(raw)
+	if err != nil {
 		return nil, fmt.Errorf("parsing schema: %w", err)
 	}
+	result := api.GetPackageSchemaOK(obj)
+	return &result, nil
+}
 
-	result := make(api.GetPackageSchemaOK, len(rawMap))
-	for k, v := range rawMap {
-		result[k] = jx.Raw(v)
+func (cc *CatalogController) GetPackageReadme(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) (api.GetPackageReadmeRes, error) {
+	slog.InfoContext(ctx, "GetPackageReadme",
+		slog.String("catalog_id", catalogID),
+		slog.String("package_name", packageName),
+		slog.String("version", version),
+	)
+
+	readme, err := cc.catalogs.GetPackageReadme(ctx, catalogID, packageName, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			problem := &api.GetPackageReadmeNotFound{}
+			problem.Title.SetTo(problemTitle(ctx, "Not found"))
+			problem.Status.SetTo(404)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		}
+		slog.ErrorContext(ctx, "Failed to get package README", slog.String("error", err.Error()))
+		problem := &api.GetPackageReadmeInternalServerError{}
+		problem.Title.SetTo(problemTitle(ctx, "Unable to get package README"))
+		problem.Status.SetTo(500)
+		problem.Detail.SetTo(err.Error())
+		return problem, err
+	}
+
+	return &api.GetPackageReadmeOK{Data: strings.NewReader(readme)}, nil
+}
+
+func (cc *CatalogController) GetPackageValues(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) (api.GetPackageValuesRes, error) {
+	slog.InfoContext(ctx, "GetPackageValues",
+		slog.String("catalog_id", catalogID),
+		slog.String("package_name", packageName),
+		slog.String("version", version),
+	)
+
+	raw, err := cc.catalogs.GetPackageValues(ctx, catalogID, packageName, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			problem := &api.GetPackageValuesNotFound{}
+			problem.Title.SetTo(problemTitle(ctx, "Not found"))
+			problem.Status.SetTo(404)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		}
+		slog.ErrorContext(ctx, "Failed to get package values", slog.String("error", err.Error()))
+		problem := &api.GetPackageValuesInternalServerError{}
+		problem.Title.SetTo(problemTitle(ctx, "Unable to get package values"))
+		problem.Status.SetTo(500)
+		problem.Detail.SetTo(err.Error())
+		return problem, err
+	}
+
+	obj, err := rawObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing values: %w", err)
 	}
+	result := api.GetPackageValuesOK(obj)
 	return &result, nil
 }
+
+// rawObject splits a JSON object into its raw members.
+func rawObject(raw []byte) (map[string]jx.Raw, error) {
+	var rawMap map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &rawMap); err != nil {
+		return nil, err
+	}
+	obj := make(map[string]jx.Raw, len(rawMap))
+	for k, v := range rawMap {
+		obj[k] = jx.Raw(v)
+	}
+	return obj, nil
+}
+
+func (cc *CatalogController) GetPackageIcon(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+) (api.GetPackageIconRes, error) {
+	icon, err := cc.catalogs.GetPackageIcon(ctx, catalogID, packageName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			problem := &api.Problem{}
+			problem.Title.SetTo(problemTitle(ctx, "Not found"))
+			problem.Status.SetTo(404)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		}
+		slog.ErrorContext(ctx, "Failed to get package icon", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &api.GetPackageIconOKHeaders{
+		CacheControl:          api.NewOptString(iconCacheControl(icon)),
+		ContentType:           icon.ContentType,
+		ContentSecurityPolicy: api.NewOptString(iconContentSecurityPolicy),
+		XContentTypeOptions:   api.NewOptString("nosniff"),
+		ContentDisposition:    api.NewOptString("inline"),
+		Response:              api.GetPackageIconOK{Data: bytes.NewReader(icon.Data)},
+	}, nil
+}
+
+// iconContentSecurityPolicy keeps icons, SVG ones in particular, from running
+// scripts or loading anything when opened directly rather than as an image.
+const iconContentSecurityPolicy = "default-src 'none'; sandbox"
+
+// iconCacheControl lets browsers keep icons for an hour. Icons of restricted
+// packages are kept out of shared caches.
+func iconCacheControl(icon domain.Icon) string {
+	if icon.Restricted {
+		return "private, max-age=3600"
+	}
+	return "public, max-age=3600"
+}
+
+// packageIconURL is the path of the icon endpoint of a package, served in
+// place of the upstream icon URL. Browsers load images without the user's
+// token, so restricted packages keep their upstream icon URL.
+func packageIconURL(catalogID string, pkg domain.Package) url.URL {
+	if pkg.Restricted {
+		return pkg.IconUrl
+	}
+	return url.URL{Path: fmt.Sprintf(
+		"/api/services/catalogs/%s/packages/%s/icon",
+		url.PathEscape(catalogID),
+		url.PathEscape(pkg.Name),
+	)}
+}
+
+// optString leaves empty strings out of responses.
+func optString(s string) api.OptString {
+	return api.OptString{Value: s, Set: s != ""}
+}
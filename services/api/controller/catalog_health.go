@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	api "github.com/onyxia-datalab/onyxia-backend/services/api/oas"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+)
+
+type CatalogHealthController struct {
+	health domain.CatalogHealthService
+}
+
+func NewCatalogHealthController(health domain.CatalogHealthService) *CatalogHealthController {
+	return &CatalogHealthController{health: health}
+}
+
+func (hc *CatalogHealthController) GetCatalogsHealth(
+	ctx context.Context,
+) (api.GetCatalogsHealthRes, error) {
+	slog.InfoContext(ctx, "GetCatalogsHealth")
+
+	healths, err := hc.health.ListCatalogHealth(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			problem := &api.GetCatalogsHealthForbidden{}
+			problem.Title.SetTo(problemTitle(ctx, "Forbidden"))
+			problem.Status.SetTo(403)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		}
+		slog.ErrorContext(ctx, "Failed to get catalogs health", slog.String("error", err.Error()))
+		problem := &api.GetCatalogsHealthInternalServerError{}
+		problem.Title.SetTo(problemTitle(ctx, "Unable to get catalogs health"))
+		problem.Status.SetTo(500)
+		problem.Detail.SetTo(err.Error())
+		return problem, err
+	}
+
+	response := make(api.GetCatalogsHealthOKApplicationJSON, 0, len(healths))
+	for _, h := range healths {
+		response = append(response, toAPICatalogHealth(h))
+	}
+	return &response, nil
+}
+
+func (hc *CatalogHealthController) RefreshCatalog(
+	ctx context.Context,
+	catalogID string,
+) (api.RefreshCatalogRes, error) {
+	slog.InfoContext(ctx, "RefreshCatalog", slog.String("catalog_id", catalogID))
+
+	health, err := hc.health.RefreshCatalog(ctx, catalogID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			problem := &api.RefreshCatalogForbidden{}
+			problem.Title.SetTo(problemTitle(ctx, "Forbidden"))
+			problem.Status.SetTo(403)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		case errors.Is(err, domain.ErrNotFound):
+			problem := &api.RefreshCatalogNotFound{}
+			problem.Title.SetTo(problemTitle(ctx, "Not found"))
+			problem.Status.SetTo(404)
+			problem.Detail.SetTo(err.Error())
+			return problem, nil
+		default:
+			slog.ErrorContext(ctx, "Failed to refresh catalog", slog.String("error", err.Error()))
+			problem := &api.RefreshCatalogInternalServerError{}
+			problem.Title.SetTo(problemTitle(ctx, "Unable to refresh catalog"))
+			problem.Status.SetTo(500)
+			problem.Detail.SetTo(err.Error())
+			return problem, err
+		}
+	}
+
+	res := toAPICatalogHealth(health)
+	return &res, nil
+}
+
+func toAPICatalogHealth(h domain.CatalogHealth) api.CatalogHealth {
+	res := api.CatalogHealth{
+		CatalogId: h.CatalogID,
+		Type:      h.Type,
+		Packages:  h.Packages,
+	}
+	if !h.LastRefresh.IsZero() {
+		res.LastRefresh.SetTo(h.LastRefresh)
+	}
+	if h.LastError != "" {
+		res.LastError.SetTo(h.LastError)
+	}
+	if !h.LastErrorAt.IsZero() {
+		res.LastErrorAt.SetTo(h.LastErrorAt)
+	}
+	if h.RefreshLatency > 0 {
+		res.RefreshLatencyMs.SetTo(h.RefreshLatency.Milliseconds())
+	}
+	return res
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/httputil"
+	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
+	api "github.com/onyxia-datalab/onyxia-backend/services/api/oas"
+)
+
+// toAPILocalizedString maps s to the API. When the client negotiated a
+// language, s is collapsed to its best translation; otherwise every
+// translation is returned. ok is false when s is unset.
+func toAPILocalizedString(ctx context.Context, s tools.LocalizedString) (api.LocalizedString, bool) {
+	if langs := httputil.LanguagesFromContext(ctx); len(langs) > 0 && s.Type() != "" {
+		return api.NewStringLocalizedString(s.Localize(langs)), true
+	}
+	if plain, ok := s.GetPlain(); ok {
+		return api.NewStringLocalizedString(plain), true
+	}
+	if multi, ok := s.GetMulti(); ok {
+		return api.NewLocalizedString1LocalizedString(api.LocalizedString1(multi)), true
+	}
+	return api.LocalizedString{}, false
+}
+
+// problemTitles translates Problem titles, keyed by their English text.
+var problemTitles = map[string]tools.MultiLangString{
+	"Not found": {
+		"fr": "Introuvable", "de": "Nicht gefunden", "es": "No encontrado",
+		"it": "Non trovato", "nl": "Niet gevonden", "zh-CN": "未找到",
+	},
+	"Forbidden": {
+		"fr": "Accès refusé", "de": "Zugriff verweigert", "es": "Acceso denegado",
+		"it": "Accesso negato", "nl": "Toegang geweigerd", "zh-CN": "禁止访问",
+	},
+	"Invalid search query": {
+		"fr": "Recherche invalide", "de": "Ungültige Suchanfrage", "es": "Búsqueda no válida",
+		"it": "Ricerca non valida", "nl": "Ongeldige zoekopdracht", "zh-CN": "无效的搜索查询",
+	},
+	"Unable to list catalogs": {
+		"fr": "Impossible de lister les catalogues", "de": "Kataloge können nicht aufgelistet werden",
+		"es": "No se pueden listar los catálogos", "it": "Impossibile elencare i cataloghi",
+		"nl": "Kan catalogi niet weergeven", "zh-CN": "无法列出目录",
+	},
+	"Unable to get package": {
+		"fr": "Impossible de récupérer le package", "de": "Paket kann nicht abgerufen werden",
+		"es": "No se puede obtener el paquete", "it": "Impossibile recuperare il pacchetto",
+		"nl": "Kan pakket niet ophalen", "zh-CN": "无法获取软件包",
+	},
+	"Unable to search packages": {
+		"fr": "Impossible de rechercher les packages", "de": "Pakete können nicht durchsucht werden",
+		"es": "No se pueden buscar paquetes", "it": "Impossibile cercare i pacchetti",
+		"nl": "Kan pakketten niet doorzoeken", "zh-CN": "无法搜索软件包",
+	},
+	"Unable to get package schema": {
+		"fr": "Impossible de récupérer le schéma du package",
+		"de": "Paketschema kann nicht abgerufen werden",
+		"es": "No se puede obtener el esquema del paquete",
+		"it": "Impossibile recuperare lo schema del pacchetto",
+		"nl": "Kan pakketschema niet ophalen", "zh-CN": "无法获取软件包架构",
+	},
+	"Unable to get package README": {
+		"fr": "Impossible de récupérer le README du package",
+		"de": "Paket-README kann nicht abgerufen werden",
+		"es": "No se puede obtener el README del paquete",
+		"it": "Impossibile recuperare il README del pacchetto",
+		"nl": "Kan README van pakket niet ophalen", "zh-CN": "无法获取软件包 README",
+	},
+	"Unable to list presets": {
+		"fr": "Impossible de lister les préréglages", "de": "Voreinstellungen können nicht aufgelistet werden",
+		"es": "No se pueden listar los ajustes predefinidos", "it": "Impossibile elencare i preset",
+		"nl": "Kan voorinstellingen niet weergeven", "zh-CN": "无法列出预设",
+	},
+	"Unable to get package values": {
+		"fr": "Impossible de récupérer les valeurs du package",
+		"de": "Paketwerte können nicht abgerufen werden",
+		"es": "No se pueden obtener los valores del paquete",
+		"it": "Impossibile recuperare i valori del pacchetto",
+		"nl": "Kan pakketwaarden niet ophalen", "zh-CN": "无法获取软件包默认值",
+	},
+	"Unable to get catalogs health": {
+		"fr": "Impossible de récupérer l'état des catalogues",
+		"de": "Katalogstatus kann nicht abgerufen werden",
+		"es": "No se puede obtener el estado de los catálogos",
+		"it": "Impossibile recuperare lo stato dei cataloghi",
+		"nl": "Kan status van catalogi niet ophalen", "zh-CN": "无法获取目录状态",
+	},
+	"Unable to refresh catalog": {
+		"fr": "Impossible de rafraîchir le catalogue", "de": "Katalog kann nicht aktualisiert werden",
+		"es": "No se puede actualizar el catálogo", "it": "Impossibile aggiornare il catalogo",
+		"nl": "Kan catalogus niet vernieuwen", "zh-CN": "无法刷新目录",
+	},
+}
+
+// problemTitle translates a Problem title to the negotiated language. Titles
+// stay in English when the client did not negotiate one.
+func problemTitle(ctx context.Context, title string) string {
+	langs := httputil.LanguagesFromContext(ctx)
+	translations, ok := problemTitles[title]
+	if len(langs) == 0 || !ok {
+		return title
+	}
+
+	all := make(tools.MultiLangString, len(translations)+1)
+	for lang, t := range translations {
+		all[lang] = t
+	}
+	all["en"] = title
+
+	localized, err := tools.NewLocalizedString(all)
+	if err != nil {
+		return title
+	}
+	// English is the last resort, whatever the default language.
+	return localized.Localize(append(langs[:len(langs):len(langs)], "en"))
+}
+
+// localizedETag derives the ETag of a localized representation from etag, so
+// a cached response in one language is never revalidated for another.
+func localizedETag(ctx context.Context, etag string) string {
+	langs := httputil.LanguagesFromContext(ctx)
+	if etag == "" || len(langs) == 0 {
+		return etag
+	}
+	sum := sha256.Sum256([]byte(strings.Join(langs, ",")))
+	return strings.TrimSuffix(etag, `"`) + "-" + hex.EncodeToString(sum[:4]) + `"`
+}
@@ -2,9 +2,7 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 
 	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
@@ -52,18 +50,9 @@ func (ic *InstallController) InstallService(
 		return &api.InstallServiceBadRequest{}, errors.New("options are required")
 	}
 
-	values := make(map[string]interface{}, len(req.Options))
-
-	for k, raw := range req.Options {
-		var v interface{}
-		if err := json.Unmarshal(raw, &v); err != nil {
-			return &api.InstallServiceBadRequest{}, fmt.Errorf(
-				"unmarshal values[%q]: %w",
-				k,
-				err,
-			)
-		}
-		values[k] = v
+	values, err := decodeOptions(req.Options)
+	if err != nil {
+		return &api.InstallServiceBadRequest{}, err
 	}
 
 	dreq := domain.StartRequest{
@@ -79,14 +68,17 @@ func (ic *InstallController) InstallService(
 	}
 
 	// Execute use case.
-	_, err := ic.serviceLifecycleUc.Start(ctx, dreq)
+	res, err := ic.serviceLifecycleUc.Start(ctx, dreq)
 
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidInput):
 			return &api.InstallServiceBadRequest{}, err
-		case errors.Is(err, domain.ErrForbidden):
+		case errors.Is(err, domain.ErrNotInstallable):
 			return &api.InstallServiceForbidden{}, err
+		// Restricted catalogs and packages are hidden, as on reads.
+		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrForbidden):
+			return &api.InstallServiceNotFound{}, err
 		case errors.Is(err, domain.ErrAlreadyExists):
 			return &api.InstallServiceConflict{}, err
 		default:
@@ -103,6 +95,7 @@ func (ic *InstallController) InstallService(
 				Release:   "",
 				Resources: "",
 			},
+			Warnings: res.Warnings,
 		},
 	}, nil
 }
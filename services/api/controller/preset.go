@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/go-faster/jx"
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	api "github.com/onyxia-datalab/onyxia-backend/services/api/oas"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+)
+
+type PresetController struct {
+	presets    domain.PresetService
+	userGetter usercontext.UserGetter
+}
+
+func NewPresetController(
+	presets domain.PresetService,
+	userGetter usercontext.UserGetter,
+) *PresetController {
+	return &PresetController{presets: presets, userGetter: userGetter}
+}
+
+func (pc *PresetController) GetMyPresets(
+	ctx context.Context,
+	project string,
+) (api.GetMyPresetsRes, error) {
+	presets, err := pc.presets.ListPresets(ctx, project)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list presets", slog.String("error", err.Error()))
+		problem := &api.Problem{}
+		problem.Title.SetTo(problemTitle(ctx, "Unable to list presets"))
+		problem.Status.SetTo(500)
+		problem.Detail.SetTo(err.Error())
+		return problem, err
+	}
+
+	response := make(api.GetMyPresetsOKApplicationJSON, 0, len(presets))
+	for _, p := range presets {
+		preset, err := toAPIPreset(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("preset %q: %w", p.ID, err)
+		}
+		response = append(response, preset)
+	}
+	return &response, nil
+}
+
+func toAPIPreset(ctx context.Context, p domain.Preset) (api.Preset, error) {
+	preset := api.Preset{
+		CatalogId:   p.CatalogID,
+		ID:          p.ID,
+		PackageName: p.PackageName,
+		Version:     optString(p.Version),
+	}
+	if name, ok := toAPILocalizedString(ctx, p.Name); ok {
+		preset.Name = name
+	}
+	if desc, ok := toAPILocalizedString(ctx, p.Description); ok {
+		preset.Description.SetTo(desc)
+	}
+	if len(p.Values) > 0 {
+		raw, err := json.Marshal(p.Values)
+		if err != nil {
+			return api.Preset{}, fmt.Errorf("encoding values: %w", err)
+		}
+		obj, err := rawObject(raw)
+		if err != nil {
+			return api.Preset{}, fmt.Errorf("encoding values: %w", err)
+		}
+		preset.Values.SetTo(api.PresetValues(obj))
+	}
+	return preset, nil
+}
+
+func (pc *PresetController) InstallPreset(
+	ctx context.Context,
+	req *api.PresetInstallRequest,
+	params api.InstallPresetParams,
+) (api.InstallPresetRes, error) {
+
+	u, ok := pc.userGetter.GetUser(ctx)
+	if !ok || u == nil {
+		slog.ErrorContext(ctx, "user not found in context")
+		return &api.InstallPresetForbidden{}, errors.New("user not found")
+	}
+
+	if req == nil {
+		return &api.InstallPresetBadRequest{}, errors.New("request body is required")
+	}
+	if req.CatalogId == "" {
+		return &api.InstallPresetBadRequest{}, errors.New("catalogId is required")
+	}
+	if req.PresetId == "" {
+		return &api.InstallPresetBadRequest{}, errors.New("presetId is required")
+	}
+
+	values, err := decodeOptions(req.Options.Or(nil))
+	if err != nil {
+		return &api.InstallPresetBadRequest{}, err
+	}
+
+	dreq := domain.StartRequest{
+		Username:      u.Username,
+		Name:          req.Name,
+		ReleaseID:     params.ReleaseId,
+		OnyxiaProject: params.XOnyxiaProject.Or(""),
+		FriendlyName:  req.FriendlyName.Or(req.PresetId),
+		Share:         req.Share.Or(false),
+		Values:        values,
+	}
+
+	res, err := pc.presets.InstallPreset(ctx, req.CatalogId, req.PresetId, dreq)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			return &api.InstallPresetBadRequest{}, err
+		case errors.Is(err, domain.ErrNotInstallable):
+			return &api.InstallPresetForbidden{}, err
+		case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrForbidden):
+			return &api.InstallPresetNotFound{}, err
+		case errors.Is(err, domain.ErrAlreadyExists):
+			return &api.InstallPresetConflict{}, err
+		default:
+			slog.ErrorContext(ctx, "preset install failed", slog.Any("error", err))
+			return &api.InstallPresetInternalServerError{}, err
+		}
+	}
+
+	return &api.InstallAcceptedHeaders{
+		Location: api.NewOptString(""),
+		Response: api.InstallAccepted{
+			EventsUrl: api.InstallAcceptedEventsUrl{
+				Release:   "",
+				Resources: "",
+			},
+			Warnings: res.Warnings,
+		},
+	}, nil
+}
+
+// decodeOptions decodes the raw install options of a request into values.
+func decodeOptions(options map[string]jx.Raw) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(options))
+	for k, raw := range options {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("unmarshal values[%q]: %w", k, err)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
@@ -28,19 +28,53 @@ func trimTrailingSlashes(u *url.URL) {
 
 // Invoker invokes operations described by OpenAPI v3 specification.
 type Invoker interface {
+	// GetCatalogsHealth invokes getCatalogsHealth operation.
+	//
+	// Reports, for each catalog, the last successful index refresh, the last error, the number of
+	// packages and the refresh latency. Restricted to users holding one of the configured admin roles.
+	//
+	// GET /api/services/admin/catalogs/health
+	GetCatalogsHealth(ctx context.Context) (GetCatalogsHealthRes, error)
 	// GetMyCatalogs invokes getMyCatalogs operation.
 	//
 	// Returns the list of catalogs and packages available for the user. The list of packages is filtered
-	// by user permissions if the user is authenticated. Otherwise returns the public catalog.
+	// by user permissions if the user is authenticated. Otherwise returns the public catalog. When a
+	// project is given, only catalogs visible in project context are returned, otherwise only catalogs
+	// visible in user context. When the client asks for a language with the `lang` query parameter or
+	// the Accept-Language header, catalog names and descriptions are returned as plain strings in the
+	// best available language.
 	//
 	// GET /api/services/catalogs
-	GetMyCatalogs(ctx context.Context) (GetMyCatalogsRes, error)
+	GetMyCatalogs(ctx context.Context, params GetMyCatalogsParams) (GetMyCatalogsRes, error)
 	// GetMyPackage invokes getMyPackage operation.
 	//
 	// Returns detailed information about a package in a catalog, including available versions.
 	//
 	// GET /api/services/catalogs/{catalogId}/packages/{packageName}
 	GetMyPackage(ctx context.Context, params GetMyPackageParams) (GetMyPackageRes, error)
+	// GetMyPresets invokes getMyPresets operation.
+	//
+	// Returns the presets of the catalogs available to the user, with the same visibility rules as the
+	// catalog list. Presets whose values do not match the chart schema are left out.
+	//
+	// GET /api/services/presets
+	GetMyPresets(ctx context.Context, params GetMyPresetsParams) (GetMyPresetsRes, error)
+	// GetPackageIcon invokes getPackageIcon operation.
+	//
+	// Serves the icon of a package, fetched from its upstream location and cached by the server. A
+	// default icon is returned when the package has no icon or it cannot be fetched. Catalog
+	// restrictions apply: icons of packages the user may not access are not found. Icons are served with
+	// a sandboxing Content-Security-Policy, so SVG icons run no script.
+	//
+	// GET /api/services/catalogs/{catalogId}/packages/{packageName}/icon
+	GetPackageIcon(ctx context.Context, params GetPackageIconParams) (GetPackageIconRes, error)
+	// GetPackageReadme invokes getPackageReadme operation.
+	//
+	// Returns the README of the chart, as markdown. The chart is downloaded once and shared with the
+	// schema and default values endpoints.
+	//
+	// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/readme
+	GetPackageReadme(ctx context.Context, params GetPackageReadmeParams) (GetPackageReadmeRes, error)
 	// GetPackageSchema invokes getPackageSchema operation.
 	//
 	// Returns the values.schema.json of a versioned package. The schema is enhanced by user permissions
@@ -48,13 +82,43 @@ type Invoker interface {
 	//
 	// GET /api/services/schemas/{catalogId}/packageName/{packageName}/versions/{version}
 	GetPackageSchema(ctx context.Context, params GetPackageSchemaParams) (GetPackageSchemaRes, error)
+	// GetPackageValues invokes getPackageValues operation.
+	//
+	// Returns the default values.yaml of the chart, converted to JSON.
+	//
+	// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/values
+	GetPackageValues(ctx context.Context, params GetPackageValuesParams) (GetPackageValuesRes, error)
+	// InstallPreset invokes installPreset operation.
+	//
+	// Starts an install of the package of a catalog preset. The options of the request are merged over
+	// the preset values, nested objects being merged key by key. Responds like installService.
+	//
+	// PUT /api/services/{releaseId}/install-preset
+	InstallPreset(ctx context.Context, request *PresetInstallRequest, params InstallPresetParams) (InstallPresetRes, error)
 	// InstallService invokes installService operation.
 	//
 	// Starts an install for the given releaseId. Returns 202 with URLs for SSE streams. Idempotent if
-	// the release already exists (returns 202 with the same event URLs).
+	// the release already exists (returns 202 with the same event URLs). Catalogs and packages the user
+	// may not access answer 404, like reads; 403 means the version is deprecated and only kept for
+	// running services.
 	//
 	// PUT /api/services/{releaseId}/install
 	InstallService(ctx context.Context, request *ServiceInstallRequest, params InstallServiceParams) (InstallServiceRes, error)
+	// RefreshCatalog invokes refreshCatalog operation.
+	//
+	// Downloads the catalog index again, bypassing the cache, and returns the resulting status. A failed
+	// refresh is reported in lastError. Restricted to users holding one of the configured admin roles.
+	//
+	// POST /api/services/admin/catalogs/{catalogId}/refresh
+	RefreshCatalog(ctx context.Context, params RefreshCatalogParams) (RefreshCatalogRes, error)
+	// SearchPackages invokes searchPackages operation.
+	//
+	// Searches package names, descriptions and keywords across every catalog the user may access (public
+	// catalogs only when unauthenticated), with the same visibility rules as the catalog list. Results
+	// are ranked by relevance, highlighted packages first on ties.
+	//
+	// GET /api/services/packages
+	SearchPackages(ctx context.Context, params SearchPackagesParams) (SearchPackagesRes, error)
 	// WatchRelease invokes watchRelease operation.
 	//
 	// Server-Sent Events (text/event-stream). Emits: "status", "log" (optional), and "done".
@@ -112,22 +176,22 @@ func (c *Client) requestURL(ctx context.Context) *url.URL {
 	return u
 }
 
-// GetMyCatalogs invokes getMyCatalogs operation.
+// GetCatalogsHealth invokes getCatalogsHealth operation.
 //
-// Returns the list of catalogs and packages available for the user. The list of packages is filtered
-// by user permissions if the user is authenticated. Otherwise returns the public catalog.
+// Reports, for each catalog, the last successful index refresh, the last error, the number of
+// packages and the refresh latency. Restricted to users holding one of the configured admin roles.
 //
-// GET /api/services/catalogs
-func (c *Client) GetMyCatalogs(ctx context.Context) (GetMyCatalogsRes, error) {
-	res, err := c.sendGetMyCatalogs(ctx)
+// GET /api/services/admin/catalogs/health
+func (c *Client) GetCatalogsHealth(ctx context.Context) (GetCatalogsHealthRes, error) {
+	res, err := c.sendGetCatalogsHealth(ctx)
 	return res, err
 }
 
-func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, err error) {
+func (c *Client) sendGetCatalogsHealth(ctx context.Context) (res GetCatalogsHealthRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("getMyCatalogs"),
+		otelogen.OperationID("getCatalogsHealth"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/api/services/catalogs"),
+		semconv.URLTemplateKey.String("/api/services/admin/catalogs/health"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -143,7 +207,7 @@ func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, e
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, GetMyCatalogsOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetCatalogsHealthOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -161,7 +225,7 @@ func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, e
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/api/services/catalogs"
+	pathParts[0] = "/api/services/admin/catalogs/health"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -175,7 +239,7 @@ func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, e
 		var satisfied bitset
 		{
 			stage = "Security:Oidc"
-			switch err := c.securityOidc(ctx, GetMyCatalogsOperation, r); {
+			switch err := c.securityOidc(ctx, GetCatalogsHealthOperation, r); {
 			case err == nil: // if NO error
 				satisfied[0] |= 1 << 0
 			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
@@ -188,7 +252,6 @@ func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, e
 		if ok := func() bool {
 		nextRequirement:
 			for _, requirement := range []bitset{
-				{},
 				{0b00000001},
 			} {
 				for i, mask := range requirement {
@@ -213,7 +276,7 @@ func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, e
 	defer body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeGetMyCatalogsResponse(resp)
+	result, err := decodeGetCatalogsHealthResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -221,21 +284,26 @@ func (c *Client) sendGetMyCatalogs(ctx context.Context) (res GetMyCatalogsRes, e
 	return result, nil
 }
 
-// GetMyPackage invokes getMyPackage operation.
+// GetMyCatalogs invokes getMyCatalogs operation.
 //
-// Returns detailed information about a package in a catalog, including available versions.
+// Returns the list of catalogs and packages available for the user. The list of packages is filtered
+// by user permissions if the user is authenticated. Otherwise returns the public catalog. When a
+// project is given, only catalogs visible in project context are returned, otherwise only catalogs
+// visible in user context. When the client asks for a language with the `lang` query parameter or
+// the Accept-Language header, catalog names and descriptions are returned as plain strings in the
+// best available language.
 //
-// GET /api/services/catalogs/{catalogId}/packages/{packageName}
-func (c *Client) GetMyPackage(ctx context.Context, params GetMyPackageParams) (GetMyPackageRes, error) {
-	res, err := c.sendGetMyPackage(ctx, params)
+// GET /api/services/catalogs
+func (c *Client) GetMyCatalogs(ctx context.Context, params GetMyCatalogsParams) (GetMyCatalogsRes, error) {
+	res, err := c.sendGetMyCatalogs(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams) (res GetMyPackageRes, err error) {
+func (c *Client) sendGetMyCatalogs(ctx context.Context, params GetMyCatalogsParams) (res GetMyCatalogsRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("getMyPackage"),
+		otelogen.OperationID("getMyCatalogs"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/api/services/catalogs/{catalogId}/packages/{packageName}"),
+		semconv.URLTemplateKey.String("/api/services/catalogs"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -251,7 +319,7 @@ func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, GetMyPackageOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetMyCatalogsOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -268,51 +336,45 @@ func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams
 
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
-	var pathParts [4]string
-	pathParts[0] = "/api/services/catalogs/"
+	var pathParts [1]string
+	pathParts[0] = "/api/services/catalogs"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
 	{
-		// Encode "catalogId" parameter.
-		e := uri.NewPathEncoder(uri.PathEncoderConfig{
-			Param:   "catalogId",
-			Style:   uri.PathStyleSimple,
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "X-Onyxia-Project",
 			Explode: false,
-		})
-		if err := func() error {
-			return e.EncodeValue(conv.StringToString(params.CatalogId))
-		}(); err != nil {
-			return res, errors.Wrap(err, "encode path")
 		}
-		encoded, err := e.Result()
-		if err != nil {
-			return res, errors.Wrap(err, "encode path")
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.XOnyxiaProject.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
 		}
-		pathParts[1] = encoded
 	}
-	pathParts[2] = "/packages/"
 	{
-		// Encode "packageName" parameter.
-		e := uri.NewPathEncoder(uri.PathEncoderConfig{
-			Param:   "packageName",
-			Style:   uri.PathStyleSimple,
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "If-None-Match",
 			Explode: false,
-		})
-		if err := func() error {
-			return e.EncodeValue(conv.StringToString(params.PackageName))
-		}(); err != nil {
-			return res, errors.Wrap(err, "encode path")
 		}
-		encoded, err := e.Result()
-		if err != nil {
-			return res, errors.Wrap(err, "encode path")
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.IfNoneMatch.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
 		}
-		pathParts[3] = encoded
-	}
-	uri.AddPathParts(u, pathParts[:]...)
-
-	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "GET", u)
-	if err != nil {
-		return res, errors.Wrap(err, "create request")
 	}
 
 	{
@@ -320,7 +382,7 @@ func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams
 		var satisfied bitset
 		{
 			stage = "Security:Oidc"
-			switch err := c.securityOidc(ctx, GetMyPackageOperation, r); {
+			switch err := c.securityOidc(ctx, GetMyCatalogsOperation, r); {
 			case err == nil: // if NO error
 				satisfied[0] |= 1 << 0
 			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
@@ -333,6 +395,7 @@ func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams
 		if ok := func() bool {
 		nextRequirement:
 			for _, requirement := range []bitset{
+				{},
 				{0b00000001},
 			} {
 				for i, mask := range requirement {
@@ -357,7 +420,7 @@ func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams
 	defer body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeGetMyPackageResponse(resp)
+	result, err := decodeGetMyCatalogsResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -365,22 +428,21 @@ func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams
 	return result, nil
 }
 
-// GetPackageSchema invokes getPackageSchema operation.
+// GetMyPackage invokes getMyPackage operation.
 //
-// Returns the values.schema.json of a versioned package. The schema is enhanced by user permissions
-// and roles.
+// Returns detailed information about a package in a catalog, including available versions.
 //
-// GET /api/services/schemas/{catalogId}/packageName/{packageName}/versions/{version}
-func (c *Client) GetPackageSchema(ctx context.Context, params GetPackageSchemaParams) (GetPackageSchemaRes, error) {
-	res, err := c.sendGetPackageSchema(ctx, params)
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}
+func (c *Client) GetMyPackage(ctx context.Context, params GetMyPackageParams) (GetMyPackageRes, error) {
+	res, err := c.sendGetMyPackage(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSchemaParams) (res GetPackageSchemaRes, err error) {
+func (c *Client) sendGetMyPackage(ctx context.Context, params GetMyPackageParams) (res GetMyPackageRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("getPackageSchema"),
+		otelogen.OperationID("getMyPackage"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/api/services/schemas/{catalogId}/packageName/{packageName}/versions/{version}"),
+		semconv.URLTemplateKey.String("/api/services/catalogs/{catalogId}/packages/{packageName}"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -396,7 +458,7 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, GetPackageSchemaOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetMyPackageOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -413,8 +475,8 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
-	var pathParts [6]string
-	pathParts[0] = "/api/services/schemas/"
+	var pathParts [4]string
+	pathParts[0] = "/api/services/catalogs/"
 	{
 		// Encode "catalogId" parameter.
 		e := uri.NewPathEncoder(uri.PathEncoderConfig{
@@ -433,7 +495,7 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 		}
 		pathParts[1] = encoded
 	}
-	pathParts[2] = "/packageName/"
+	pathParts[2] = "/packages/"
 	{
 		// Encode "packageName" parameter.
 		e := uri.NewPathEncoder(uri.PathEncoderConfig{
@@ -452,25 +514,6 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 		}
 		pathParts[3] = encoded
 	}
-	pathParts[4] = "/versions/"
-	{
-		// Encode "version" parameter.
-		e := uri.NewPathEncoder(uri.PathEncoderConfig{
-			Param:   "version",
-			Style:   uri.PathStyleSimple,
-			Explode: false,
-		})
-		if err := func() error {
-			return e.EncodeValue(conv.StringToString(params.Version))
-		}(); err != nil {
-			return res, errors.Wrap(err, "encode path")
-		}
-		encoded, err := e.Result()
-		if err != nil {
-			return res, errors.Wrap(err, "encode path")
-		}
-		pathParts[5] = encoded
-	}
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -484,7 +527,7 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 		var satisfied bitset
 		{
 			stage = "Security:Oidc"
-			switch err := c.securityOidc(ctx, GetPackageSchemaOperation, r); {
+			switch err := c.securityOidc(ctx, GetMyPackageOperation, r); {
 			case err == nil: // if NO error
 				satisfied[0] |= 1 << 0
 			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
@@ -521,7 +564,7 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 	defer body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeGetPackageSchemaResponse(resp)
+	result, err := decodeGetMyPackageResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -529,22 +572,22 @@ func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSche
 	return result, nil
 }
 
-// InstallService invokes installService operation.
+// GetMyPresets invokes getMyPresets operation.
 //
-// Starts an install for the given releaseId. Returns 202 with URLs for SSE streams. Idempotent if
-// the release already exists (returns 202 with the same event URLs).
+// Returns the presets of the catalogs available to the user, with the same visibility rules as the
+// catalog list. Presets whose values do not match the chart schema are left out.
 //
-// PUT /api/services/{releaseId}/install
-func (c *Client) InstallService(ctx context.Context, request *ServiceInstallRequest, params InstallServiceParams) (InstallServiceRes, error) {
-	res, err := c.sendInstallService(ctx, request, params)
+// GET /api/services/presets
+func (c *Client) GetMyPresets(ctx context.Context, params GetMyPresetsParams) (GetMyPresetsRes, error) {
+	res, err := c.sendGetMyPresets(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstallRequest, params InstallServiceParams) (res InstallServiceRes, err error) {
+func (c *Client) sendGetMyPresets(ctx context.Context, params GetMyPresetsParams) (res GetMyPresetsRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("installService"),
-		semconv.HTTPRequestMethodKey.String("PUT"),
-		semconv.URLTemplateKey.String("/api/services/{releaseId}/install"),
+		otelogen.OperationID("getMyPresets"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/api/services/presets"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -560,7 +603,7 @@ func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstall
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, InstallServiceOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetMyPresetsOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -577,37 +620,15 @@ func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstall
 
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
-	var pathParts [3]string
-	pathParts[0] = "/api/services/"
-	{
-		// Encode "releaseId" parameter.
-		e := uri.NewPathEncoder(uri.PathEncoderConfig{
-			Param:   "releaseId",
-			Style:   uri.PathStyleSimple,
-			Explode: false,
-		})
-		if err := func() error {
-			return e.EncodeValue(conv.StringToString(params.ReleaseId))
-		}(); err != nil {
-			return res, errors.Wrap(err, "encode path")
-		}
-		encoded, err := e.Result()
-		if err != nil {
-			return res, errors.Wrap(err, "encode path")
-		}
-		pathParts[1] = encoded
-	}
-	pathParts[2] = "/install"
+	var pathParts [1]string
+	pathParts[0] = "/api/services/presets"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "PUT", u)
+	r, err := ht.NewRequest(ctx, "GET", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
-	if err := encodeInstallServiceRequest(request, r); err != nil {
-		return res, errors.Wrap(err, "encode request")
-	}
 
 	stage = "EncodeHeaderParams"
 	h := uri.NewHeaderEncoder(r.Header)
@@ -631,7 +652,7 @@ func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstall
 		var satisfied bitset
 		{
 			stage = "Security:Oidc"
-			switch err := c.securityOidc(ctx, InstallServiceOperation, r); {
+			switch err := c.securityOidc(ctx, GetMyPresetsOperation, r); {
 			case err == nil: // if NO error
 				satisfied[0] |= 1 << 0
 			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
@@ -644,6 +665,7 @@ func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstall
 		if ok := func() bool {
 		nextRequirement:
 			for _, requirement := range []bitset{
+				{},
 				{0b00000001},
 			} {
 				for i, mask := range requirement {
@@ -668,7 +690,1234 @@ func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstall
 	defer body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeInstallServiceResponse(resp)
+	result, err := decodeGetMyPresetsResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// GetPackageIcon invokes getPackageIcon operation.
+//
+// Serves the icon of a package, fetched from its upstream location and cached by the server. A
+// default icon is returned when the package has no icon or it cannot be fetched. Catalog
+// restrictions apply: icons of packages the user may not access are not found. Icons are served with
+// a sandboxing Content-Security-Policy, so SVG icons run no script.
+//
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}/icon
+func (c *Client) GetPackageIcon(ctx context.Context, params GetPackageIconParams) (GetPackageIconRes, error) {
+	res, err := c.sendGetPackageIcon(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendGetPackageIcon(ctx context.Context, params GetPackageIconParams) (res GetPackageIconRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("getPackageIcon"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/api/services/catalogs/{catalogId}/packages/{packageName}/icon"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, GetPackageIconOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [5]string
+	pathParts[0] = "/api/services/catalogs/"
+	{
+		// Encode "catalogId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "catalogId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.CatalogId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/packages/"
+	{
+		// Encode "packageName" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "packageName",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.PackageName))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[3] = encoded
+	}
+	pathParts[4] = "/icon"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, GetPackageIconOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{},
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeGetPackageIconResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// GetPackageReadme invokes getPackageReadme operation.
+//
+// Returns the README of the chart, as markdown. The chart is downloaded once and shared with the
+// schema and default values endpoints.
+//
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/readme
+func (c *Client) GetPackageReadme(ctx context.Context, params GetPackageReadmeParams) (GetPackageReadmeRes, error) {
+	res, err := c.sendGetPackageReadme(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendGetPackageReadme(ctx context.Context, params GetPackageReadmeParams) (res GetPackageReadmeRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("getPackageReadme"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/readme"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, GetPackageReadmeOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [7]string
+	pathParts[0] = "/api/services/catalogs/"
+	{
+		// Encode "catalogId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "catalogId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.CatalogId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/packages/"
+	{
+		// Encode "packageName" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "packageName",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.PackageName))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[3] = encoded
+	}
+	pathParts[4] = "/versions/"
+	{
+		// Encode "version" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "version",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.Version))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[5] = encoded
+	}
+	pathParts[6] = "/readme"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, GetPackageReadmeOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeGetPackageReadmeResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// GetPackageSchema invokes getPackageSchema operation.
+//
+// Returns the values.schema.json of a versioned package. The schema is enhanced by user permissions
+// and roles.
+//
+// GET /api/services/schemas/{catalogId}/packageName/{packageName}/versions/{version}
+func (c *Client) GetPackageSchema(ctx context.Context, params GetPackageSchemaParams) (GetPackageSchemaRes, error) {
+	res, err := c.sendGetPackageSchema(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendGetPackageSchema(ctx context.Context, params GetPackageSchemaParams) (res GetPackageSchemaRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("getPackageSchema"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/api/services/schemas/{catalogId}/packageName/{packageName}/versions/{version}"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, GetPackageSchemaOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [6]string
+	pathParts[0] = "/api/services/schemas/"
+	{
+		// Encode "catalogId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "catalogId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.CatalogId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/packageName/"
+	{
+		// Encode "packageName" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "packageName",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.PackageName))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[3] = encoded
+	}
+	pathParts[4] = "/versions/"
+	{
+		// Encode "version" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "version",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.Version))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[5] = encoded
+	}
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, GetPackageSchemaOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeGetPackageSchemaResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// GetPackageValues invokes getPackageValues operation.
+//
+// Returns the default values.yaml of the chart, converted to JSON.
+//
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/values
+func (c *Client) GetPackageValues(ctx context.Context, params GetPackageValuesParams) (GetPackageValuesRes, error) {
+	res, err := c.sendGetPackageValues(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendGetPackageValues(ctx context.Context, params GetPackageValuesParams) (res GetPackageValuesRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("getPackageValues"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/values"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, GetPackageValuesOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [7]string
+	pathParts[0] = "/api/services/catalogs/"
+	{
+		// Encode "catalogId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "catalogId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.CatalogId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/packages/"
+	{
+		// Encode "packageName" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "packageName",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.PackageName))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[3] = encoded
+	}
+	pathParts[4] = "/versions/"
+	{
+		// Encode "version" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "version",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.Version))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[5] = encoded
+	}
+	pathParts[6] = "/values"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, GetPackageValuesOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeGetPackageValuesResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// InstallPreset invokes installPreset operation.
+//
+// Starts an install of the package of a catalog preset. The options of the request are merged over
+// the preset values, nested objects being merged key by key. Responds like installService.
+//
+// PUT /api/services/{releaseId}/install-preset
+func (c *Client) InstallPreset(ctx context.Context, request *PresetInstallRequest, params InstallPresetParams) (InstallPresetRes, error) {
+	res, err := c.sendInstallPreset(ctx, request, params)
+	return res, err
+}
+
+func (c *Client) sendInstallPreset(ctx context.Context, request *PresetInstallRequest, params InstallPresetParams) (res InstallPresetRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("installPreset"),
+		semconv.HTTPRequestMethodKey.String("PUT"),
+		semconv.URLTemplateKey.String("/api/services/{releaseId}/install-preset"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, InstallPresetOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [3]string
+	pathParts[0] = "/api/services/"
+	{
+		// Encode "releaseId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "releaseId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.ReleaseId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/install-preset"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "PUT", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeInstallPresetRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.XOnyxiaProject.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, InstallPresetOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeInstallPresetResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// InstallService invokes installService operation.
+//
+// Starts an install for the given releaseId. Returns 202 with URLs for SSE streams. Idempotent if
+// the release already exists (returns 202 with the same event URLs). Catalogs and packages the user
+// may not access answer 404, like reads; 403 means the version is deprecated and only kept for
+// running services.
+//
+// PUT /api/services/{releaseId}/install
+func (c *Client) InstallService(ctx context.Context, request *ServiceInstallRequest, params InstallServiceParams) (InstallServiceRes, error) {
+	res, err := c.sendInstallService(ctx, request, params)
+	return res, err
+}
+
+func (c *Client) sendInstallService(ctx context.Context, request *ServiceInstallRequest, params InstallServiceParams) (res InstallServiceRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("installService"),
+		semconv.HTTPRequestMethodKey.String("PUT"),
+		semconv.URLTemplateKey.String("/api/services/{releaseId}/install"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, InstallServiceOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [3]string
+	pathParts[0] = "/api/services/"
+	{
+		// Encode "releaseId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "releaseId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.ReleaseId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/install"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "PUT", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeInstallServiceRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.XOnyxiaProject.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, InstallServiceOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeInstallServiceResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// RefreshCatalog invokes refreshCatalog operation.
+//
+// Downloads the catalog index again, bypassing the cache, and returns the resulting status. A failed
+// refresh is reported in lastError. Restricted to users holding one of the configured admin roles.
+//
+// POST /api/services/admin/catalogs/{catalogId}/refresh
+func (c *Client) RefreshCatalog(ctx context.Context, params RefreshCatalogParams) (RefreshCatalogRes, error) {
+	res, err := c.sendRefreshCatalog(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendRefreshCatalog(ctx context.Context, params RefreshCatalogParams) (res RefreshCatalogRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("refreshCatalog"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/api/services/admin/catalogs/{catalogId}/refresh"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, RefreshCatalogOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [3]string
+	pathParts[0] = "/api/services/admin/catalogs/"
+	{
+		// Encode "catalogId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "catalogId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.CatalogId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/refresh"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, RefreshCatalogOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeRefreshCatalogResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// SearchPackages invokes searchPackages operation.
+//
+// Searches package names, descriptions and keywords across every catalog the user may access (public
+// catalogs only when unauthenticated), with the same visibility rules as the catalog list. Results
+// are ranked by relevance, highlighted packages first on ties.
+//
+// GET /api/services/packages
+func (c *Client) SearchPackages(ctx context.Context, params SearchPackagesParams) (SearchPackagesRes, error) {
+	res, err := c.sendSearchPackages(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendSearchPackages(ctx context.Context, params SearchPackagesParams) (res SearchPackagesRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("searchPackages"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/api/services/packages"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, SearchPackagesOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/api/services/packages"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "q" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "q",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.Q))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "limit" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "limit",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.Limit.Get(); ok {
+				return e.EncodeValue(conv.IntToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "EncodeHeaderParams"
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.XOnyxiaProject.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+			stage = "Security:Oidc"
+			switch err := c.securityOidc(ctx, SearchPackagesOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"Oidc\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{},
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	body := resp.Body
+	defer body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeSearchPackagesResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -2,6 +2,14 @@
 
 package api
 
+// setDefaults set default value of fields.
+func (s *PresetInstallRequest) setDefaults() {
+	{
+		val := bool(false)
+		s.Share.SetTo(val)
+	}
+}
+
 // setDefaults set default value of fields.
 func (s *ServiceInstallRequest) setDefaults() {
 	{
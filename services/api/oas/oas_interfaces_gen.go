@@ -1,6 +1,10 @@
 // Code generated by ogen, DO NOT EDIT.
 package api
 
+type GetCatalogsHealthRes interface {
+	getCatalogsHealthRes()
+}
+
 type GetMyCatalogsRes interface {
 	getMyCatalogsRes()
 }
@@ -9,14 +13,42 @@ type GetMyPackageRes interface {
 	getMyPackageRes()
 }
 
+type GetMyPresetsRes interface {
+	getMyPresetsRes()
+}
+
+type GetPackageIconRes interface {
+	getPackageIconRes()
+}
+
+type GetPackageReadmeRes interface {
+	getPackageReadmeRes()
+}
+
 type GetPackageSchemaRes interface {
 	getPackageSchemaRes()
 }
 
+type GetPackageValuesRes interface {
+	getPackageValuesRes()
+}
+
+type InstallPresetRes interface {
+	installPresetRes()
+}
+
 type InstallServiceRes interface {
 	installServiceRes()
 }
 
+type RefreshCatalogRes interface {
+	refreshCatalogRes()
+}
+
+type SearchPackagesRes interface {
+	searchPackagesRes()
+}
+
 type WatchReleaseRes interface {
 	watchReleaseRes()
 }
@@ -6,10 +6,18 @@ package api
 type OperationName = string
 
 const (
-	GetMyCatalogsOperation    OperationName = "GetMyCatalogs"
-	GetMyPackageOperation     OperationName = "GetMyPackage"
-	GetPackageSchemaOperation OperationName = "GetPackageSchema"
-	InstallServiceOperation   OperationName = "InstallService"
-	WatchReleaseOperation     OperationName = "WatchRelease"
-	WatchResourcesOperation   OperationName = "WatchResources"
+	GetCatalogsHealthOperation OperationName = "GetCatalogsHealth"
+	GetMyCatalogsOperation     OperationName = "GetMyCatalogs"
+	GetMyPackageOperation      OperationName = "GetMyPackage"
+	GetMyPresetsOperation      OperationName = "GetMyPresets"
+	GetPackageIconOperation    OperationName = "GetPackageIcon"
+	GetPackageReadmeOperation  OperationName = "GetPackageReadme"
+	GetPackageSchemaOperation  OperationName = "GetPackageSchema"
+	GetPackageValuesOperation  OperationName = "GetPackageValues"
+	InstallPresetOperation     OperationName = "InstallPreset"
+	InstallServiceOperation    OperationName = "InstallService"
+	RefreshCatalogOperation    OperationName = "RefreshCatalog"
+	SearchPackagesOperation    OperationName = "SearchPackages"
+	WatchReleaseOperation      OperationName = "WatchRelease"
+	WatchResourcesOperation    OperationName = "WatchResources"
 )
@@ -14,6 +14,119 @@ import (
 	"github.com/ogen-go/ogen/validate"
 )
 
+// GetMyCatalogsParams is parameters of getMyCatalogs operation.
+type GetMyCatalogsParams struct {
+	// Project identifier in Onyxia.
+	XOnyxiaProject OptString `json:",omitempty,omitzero"`
+	// ETag of a previous response; 304 is returned if unchanged.
+	IfNoneMatch OptString `json:",omitempty,omitzero"`
+}
+
+func unpackGetMyCatalogsParams(packed middleware.Parameters) (params GetMyCatalogsParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.XOnyxiaProject = v.(OptString)
+		}
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "If-None-Match",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.IfNoneMatch = v.(OptString)
+		}
+	}
+	return params
+}
+
+func decodeGetMyCatalogsParams(args [0]string, argsEscaped bool, r *http.Request) (params GetMyCatalogsParams, _ error) {
+	h := uri.NewHeaderDecoder(r.Header)
+	// Decode header: X-Onyxia-Project.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotXOnyxiaProjectVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotXOnyxiaProjectVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.XOnyxiaProject.SetTo(paramsDotXOnyxiaProjectVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	// Decode header: If-None-Match.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "If-None-Match",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotIfNoneMatchVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotIfNoneMatchVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.IfNoneMatch.SetTo(paramsDotIfNoneMatchVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "If-None-Match",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
 // GetMyPackageParams is parameters of getMyPackage operation.
 type GetMyPackageParams struct {
 	// Catalog identifier.
@@ -134,17 +247,78 @@ func decodeGetMyPackageParams(args [2]string, argsEscaped bool, r *http.Request)
 	return params, nil
 }
 
-// GetPackageSchemaParams is parameters of getPackageSchema operation.
-type GetPackageSchemaParams struct {
+// GetMyPresetsParams is parameters of getMyPresets operation.
+type GetMyPresetsParams struct {
+	// Project identifier in Onyxia.
+	XOnyxiaProject OptString `json:",omitempty,omitzero"`
+}
+
+func unpackGetMyPresetsParams(packed middleware.Parameters) (params GetMyPresetsParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.XOnyxiaProject = v.(OptString)
+		}
+	}
+	return params
+}
+
+func decodeGetMyPresetsParams(args [0]string, argsEscaped bool, r *http.Request) (params GetMyPresetsParams, _ error) {
+	h := uri.NewHeaderDecoder(r.Header)
+	// Decode header: X-Onyxia-Project.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotXOnyxiaProjectVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotXOnyxiaProjectVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.XOnyxiaProject.SetTo(paramsDotXOnyxiaProjectVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// GetPackageIconParams is parameters of getPackageIcon operation.
+type GetPackageIconParams struct {
 	// Catalog identifier.
 	CatalogId string
 	// Package name.
 	PackageName string
-	// Package version (semver).
-	Version string
 }
 
-func unpackGetPackageSchemaParams(packed middleware.Parameters) (params GetPackageSchemaParams) {
+func unpackGetPackageIconParams(packed middleware.Parameters) (params GetPackageIconParams) {
 	{
 		key := middleware.ParameterKey{
 			Name: "catalogId",
@@ -159,17 +333,10 @@ func unpackGetPackageSchemaParams(packed middleware.Parameters) (params GetPacka
 		}
 		params.PackageName = packed[key].(string)
 	}
-	{
-		key := middleware.ParameterKey{
-			Name: "version",
-			In:   "path",
-		}
-		params.Version = packed[key].(string)
-	}
 	return params
 }
 
-func decodeGetPackageSchemaParams(args [3]string, argsEscaped bool, r *http.Request) (params GetPackageSchemaParams, _ error) {
+func decodeGetPackageIconParams(args [2]string, argsEscaped bool, r *http.Request) (params GetPackageIconParams, _ error) {
 	// Decode path: catalogId.
 	if err := func() error {
 		param := args[0]
@@ -260,85 +427,46 @@ func decodeGetPackageSchemaParams(args [3]string, argsEscaped bool, r *http.Requ
 			Err:  err,
 		}
 	}
-	// Decode path: version.
-	if err := func() error {
-		param := args[2]
-		if argsEscaped {
-			unescaped, err := url.PathUnescape(args[2])
-			if err != nil {
-				return errors.Wrap(err, "unescape path")
-			}
-			param = unescaped
-		}
-		if len(param) > 0 {
-			d := uri.NewPathDecoder(uri.PathDecoderConfig{
-				Param:   "version",
-				Value:   param,
-				Style:   uri.PathStyleSimple,
-				Explode: false,
-			})
-
-			if err := func() error {
-				val, err := d.DecodeValue()
-				if err != nil {
-					return err
-				}
-
-				c, err := conv.ToString(val)
-				if err != nil {
-					return err
-				}
-
-				params.Version = c
-				return nil
-			}(); err != nil {
-				return err
-			}
-		} else {
-			return validate.ErrFieldRequired
-		}
-		return nil
-	}(); err != nil {
-		return params, &ogenerrors.DecodeParamError{
-			Name: "version",
-			In:   "path",
-			Err:  err,
-		}
-	}
 	return params, nil
 }
 
-// InstallServiceParams is parameters of installService operation.
-type InstallServiceParams struct {
-	// Logical release identifier.
-	ReleaseId string
-	// Project identifier in Onyxia.
-	XOnyxiaProject OptString `json:",omitempty,omitzero"`
+// GetPackageReadmeParams is parameters of getPackageReadme operation.
+type GetPackageReadmeParams struct {
+	// Catalog identifier.
+	CatalogId string
+	// Package name.
+	PackageName string
+	// Package version (semver).
+	Version string
 }
 
-func unpackInstallServiceParams(packed middleware.Parameters) (params InstallServiceParams) {
+func unpackGetPackageReadmeParams(packed middleware.Parameters) (params GetPackageReadmeParams) {
 	{
 		key := middleware.ParameterKey{
-			Name: "releaseId",
+			Name: "catalogId",
 			In:   "path",
 		}
-		params.ReleaseId = packed[key].(string)
+		params.CatalogId = packed[key].(string)
 	}
 	{
 		key := middleware.ParameterKey{
-			Name: "X-Onyxia-Project",
-			In:   "header",
+			Name: "packageName",
+			In:   "path",
 		}
-		if v, ok := packed[key]; ok {
-			params.XOnyxiaProject = v.(OptString)
+		params.PackageName = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "version",
+			In:   "path",
 		}
+		params.Version = packed[key].(string)
 	}
 	return params
 }
 
-func decodeInstallServiceParams(args [1]string, argsEscaped bool, r *http.Request) (params InstallServiceParams, _ error) {
-	h := uri.NewHeaderDecoder(r.Header)
-	// Decode path: releaseId.
+func decodeGetPackageReadmeParams(args [3]string, argsEscaped bool, r *http.Request) (params GetPackageReadmeParams, _ error) {
+	// Decode path: catalogId.
 	if err := func() error {
 		param := args[0]
 		if argsEscaped {
@@ -350,7 +478,7 @@ func decodeInstallServiceParams(args [1]string, argsEscaped bool, r *http.Reques
 		}
 		if len(param) > 0 {
 			d := uri.NewPathDecoder(uri.PathDecoderConfig{
-				Param:   "releaseId",
+				Param:   "catalogId",
 				Value:   param,
 				Style:   uri.PathStyleSimple,
 				Explode: false,
@@ -367,13 +495,666 @@ func decodeInstallServiceParams(args [1]string, argsEscaped bool, r *http.Reques
 					return err
 				}
 
-				params.ReleaseId = c
+				params.CatalogId = c
 				return nil
 			}(); err != nil {
 				return err
 			}
-			if err := func() error {
-				if err := (validate.String{
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "catalogId",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: packageName.
+	if err := func() error {
+		param := args[1]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[1])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "packageName",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.PackageName = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "packageName",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: version.
+	if err := func() error {
+		param := args[2]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[2])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "version",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.Version = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "version",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// GetPackageSchemaParams is parameters of getPackageSchema operation.
+type GetPackageSchemaParams struct {
+	// Catalog identifier.
+	CatalogId string
+	// Package name.
+	PackageName string
+	// Package version (semver).
+	Version string
+}
+
+func unpackGetPackageSchemaParams(packed middleware.Parameters) (params GetPackageSchemaParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "catalogId",
+			In:   "path",
+		}
+		params.CatalogId = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "packageName",
+			In:   "path",
+		}
+		params.PackageName = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "version",
+			In:   "path",
+		}
+		params.Version = packed[key].(string)
+	}
+	return params
+}
+
+func decodeGetPackageSchemaParams(args [3]string, argsEscaped bool, r *http.Request) (params GetPackageSchemaParams, _ error) {
+	// Decode path: catalogId.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "catalogId",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.CatalogId = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "catalogId",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: packageName.
+	if err := func() error {
+		param := args[1]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[1])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "packageName",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.PackageName = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "packageName",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: version.
+	if err := func() error {
+		param := args[2]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[2])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "version",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.Version = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "version",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// GetPackageValuesParams is parameters of getPackageValues operation.
+type GetPackageValuesParams struct {
+	// Catalog identifier.
+	CatalogId string
+	// Package name.
+	PackageName string
+	// Package version (semver).
+	Version string
+}
+
+func unpackGetPackageValuesParams(packed middleware.Parameters) (params GetPackageValuesParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "catalogId",
+			In:   "path",
+		}
+		params.CatalogId = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "packageName",
+			In:   "path",
+		}
+		params.PackageName = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "version",
+			In:   "path",
+		}
+		params.Version = packed[key].(string)
+	}
+	return params
+}
+
+func decodeGetPackageValuesParams(args [3]string, argsEscaped bool, r *http.Request) (params GetPackageValuesParams, _ error) {
+	// Decode path: catalogId.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "catalogId",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.CatalogId = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "catalogId",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: packageName.
+	if err := func() error {
+		param := args[1]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[1])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "packageName",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.PackageName = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "packageName",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: version.
+	if err := func() error {
+		param := args[2]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[2])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "version",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.Version = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "version",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// InstallPresetParams is parameters of installPreset operation.
+type InstallPresetParams struct {
+	// Logical release identifier.
+	ReleaseId string
+	// Project identifier in Onyxia.
+	XOnyxiaProject OptString `json:",omitempty,omitzero"`
+}
+
+func unpackInstallPresetParams(packed middleware.Parameters) (params InstallPresetParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "releaseId",
+			In:   "path",
+		}
+		params.ReleaseId = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.XOnyxiaProject = v.(OptString)
+		}
+	}
+	return params
+}
+
+func decodeInstallPresetParams(args [1]string, argsEscaped bool, r *http.Request) (params InstallPresetParams, _ error) {
+	h := uri.NewHeaderDecoder(r.Header)
+	// Decode path: releaseId.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "releaseId",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.ReleaseId = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+			if err := func() error {
+				if err := (validate.String{
+					MinLength:     1,
+					MinLengthSet:  true,
+					MaxLength:     0,
+					MaxLengthSet:  false,
+					Email:         false,
+					Hostname:      false,
+					Regex:         regexMap["^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"],
+					MinNumeric:    0,
+					MinNumericSet: false,
+					MaxNumeric:    0,
+					MaxNumericSet: false,
+				}).Validate(string(params.ReleaseId)); err != nil {
+					return errors.Wrap(err, "string")
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "releaseId",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode header: X-Onyxia-Project.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotXOnyxiaProjectVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotXOnyxiaProjectVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.XOnyxiaProject.SetTo(paramsDotXOnyxiaProjectVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// InstallServiceParams is parameters of installService operation.
+type InstallServiceParams struct {
+	// Logical release identifier.
+	ReleaseId string
+	// Project identifier in Onyxia.
+	XOnyxiaProject OptString `json:",omitempty,omitzero"`
+}
+
+func unpackInstallServiceParams(packed middleware.Parameters) (params InstallServiceParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "releaseId",
+			In:   "path",
+		}
+		params.ReleaseId = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.XOnyxiaProject = v.(OptString)
+		}
+	}
+	return params
+}
+
+func decodeInstallServiceParams(args [1]string, argsEscaped bool, r *http.Request) (params InstallServiceParams, _ error) {
+	h := uri.NewHeaderDecoder(r.Header)
+	// Decode path: releaseId.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "releaseId",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.ReleaseId = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+			if err := func() error {
+				if err := (validate.String{
 					MinLength:     1,
 					MinLengthSet:  true,
 					MaxLength:     0,
@@ -445,6 +1226,283 @@ func decodeInstallServiceParams(args [1]string, argsEscaped bool, r *http.Reques
 	return params, nil
 }
 
+// RefreshCatalogParams is parameters of refreshCatalog operation.
+type RefreshCatalogParams struct {
+	// Catalog identifier.
+	CatalogId string
+}
+
+func unpackRefreshCatalogParams(packed middleware.Parameters) (params RefreshCatalogParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "catalogId",
+			In:   "path",
+		}
+		params.CatalogId = packed[key].(string)
+	}
+	return params
+}
+
+func decodeRefreshCatalogParams(args [1]string, argsEscaped bool, r *http.Request) (params RefreshCatalogParams, _ error) {
+	// Decode path: catalogId.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "catalogId",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.CatalogId = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "catalogId",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// SearchPackagesParams is parameters of searchPackages operation.
+type SearchPackagesParams struct {
+	// Project identifier in Onyxia.
+	XOnyxiaProject OptString `json:",omitempty,omitzero"`
+	// Search terms, all of which must match.
+	Q string
+	// Maximum number of results.
+	Limit OptInt `json:",omitempty,omitzero"`
+}
+
+func unpackSearchPackagesParams(packed middleware.Parameters) (params SearchPackagesParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.XOnyxiaProject = v.(OptString)
+		}
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "q",
+			In:   "query",
+		}
+		params.Q = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "limit",
+			In:   "query",
+		}
+		if v, ok := packed[key]; ok {
+			params.Limit = v.(OptInt)
+		}
+	}
+	return params
+}
+
+func decodeSearchPackagesParams(args [0]string, argsEscaped bool, r *http.Request) (params SearchPackagesParams, _ error) {
+	q := uri.NewQueryDecoder(r.URL.Query())
+	h := uri.NewHeaderDecoder(r.Header)
+	// Decode header: X-Onyxia-Project.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "X-Onyxia-Project",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotXOnyxiaProjectVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotXOnyxiaProjectVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.XOnyxiaProject.SetTo(paramsDotXOnyxiaProjectVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "X-Onyxia-Project",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	// Decode query: q.
+	if err := func() error {
+		cfg := uri.QueryParameterDecodingConfig{
+			Name:    "q",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.HasParam(cfg); err == nil {
+			if err := q.DecodeParam(cfg, func(d uri.Decoder) error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.Q = c
+				return nil
+			}); err != nil {
+				return err
+			}
+			if err := func() error {
+				if err := (validate.String{
+					MinLength:     1,
+					MinLengthSet:  true,
+					MaxLength:     0,
+					MaxLengthSet:  false,
+					Email:         false,
+					Hostname:      false,
+					Regex:         nil,
+					MinNumeric:    0,
+					MinNumericSet: false,
+					MaxNumeric:    0,
+					MaxNumericSet: false,
+				}).Validate(string(params.Q)); err != nil {
+					return errors.Wrap(err, "string")
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "q",
+			In:   "query",
+			Err:  err,
+		}
+	}
+	// Set default value for query: limit.
+	{
+		val := int(50)
+		params.Limit.SetTo(val)
+	}
+	// Decode query: limit.
+	if err := func() error {
+		cfg := uri.QueryParameterDecodingConfig{
+			Name:    "limit",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.HasParam(cfg); err == nil {
+			if err := q.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotLimitVal int
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToInt(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotLimitVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.Limit.SetTo(paramsDotLimitVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+			if err := func() error {
+				if value, ok := params.Limit.Get(); ok {
+					if err := func() error {
+						if err := (validate.Int{
+							MinSet:        true,
+							Min:           1,
+							MaxSet:        true,
+							Max:           200,
+							MinExclusive:  false,
+							MaxExclusive:  false,
+							MultipleOfSet: false,
+							MultipleOf:    0,
+							Pattern:       nil,
+						}).Validate(int64(value)); err != nil {
+							return errors.Wrap(err, "int")
+						}
+						return nil
+					}(); err != nil {
+						return err
+					}
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "limit",
+			In:   "query",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
 // WatchReleaseParams is parameters of watchRelease operation.
 type WatchReleaseParams struct {
 	// Logical release identifier.
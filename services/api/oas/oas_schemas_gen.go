@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/go-faster/errors"
 	"github.com/go-faster/jx"
@@ -27,6 +28,9 @@ type Catalog struct {
 	Visible OptCatalogVisible `json:"visible"`
 	// List of packages available in the catalog.
 	Packages []Package `json:"packages"`
+	// Set when the catalog could not be loaded; packages is then empty and the other catalogs are still
+	// served. Details are reported by the catalog health endpoint.
+	Error OptCatalogError `json:"error"`
 }
 
 // GetID returns the value of ID.
@@ -64,6 +68,11 @@ func (s *Catalog) GetPackages() []Package {
 	return s.Packages
 }
 
+// GetError returns the value of Error.
+func (s *Catalog) GetError() OptCatalogError {
+	return s.Error
+}
+
 // SetID sets the value of ID.
 func (s *Catalog) SetID(val string) {
 	s.ID = val
@@ -99,6 +108,143 @@ func (s *Catalog) SetPackages(val []Package) {
 	s.Packages = val
 }
 
+// SetError sets the value of Error.
+func (s *Catalog) SetError(val OptCatalogError) {
+	s.Error = val
+}
+
+// Set when the catalog could not be loaded; packages is then empty and the other catalogs are still
+// served. Details are reported by the catalog health endpoint.
+type CatalogError string
+
+const (
+	CatalogErrorTimeout     CatalogError = "timeout"
+	CatalogErrorUnavailable CatalogError = "unavailable"
+)
+
+// AllValues returns all CatalogError values.
+func (CatalogError) AllValues() []CatalogError {
+	return []CatalogError{
+		CatalogErrorTimeout,
+		CatalogErrorUnavailable,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s CatalogError) MarshalText() ([]byte, error) {
+	switch s {
+	case CatalogErrorTimeout:
+		return []byte(s), nil
+	case CatalogErrorUnavailable:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *CatalogError) UnmarshalText(data []byte) error {
+	switch CatalogError(data) {
+	case CatalogErrorTimeout:
+		*s = CatalogErrorTimeout
+		return nil
+	case CatalogErrorUnavailable:
+		*s = CatalogErrorUnavailable
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/CatalogHealth
+type CatalogHealth struct {
+	CatalogId string `json:"catalogId"`
+	// Catalog type, one of helm, oci or directory.
+	Type string `json:"type"`
+	// Number of packages in the catalog.
+	Packages int `json:"packages"`
+	// Last successful index refresh.
+	LastRefresh OptDateTime `json:"lastRefresh"`
+	// Error of the last failed refresh.
+	LastError OptString `json:"lastError"`
+	// Time of the last failed refresh.
+	LastErrorAt OptDateTime `json:"lastErrorAt"`
+	// Duration of the last refresh attempt in milliseconds.
+	RefreshLatencyMs OptInt64 `json:"refreshLatencyMs"`
+}
+
+// GetCatalogId returns the value of CatalogId.
+func (s *CatalogHealth) GetCatalogId() string {
+	return s.CatalogId
+}
+
+// GetType returns the value of Type.
+func (s *CatalogHealth) GetType() string {
+	return s.Type
+}
+
+// GetPackages returns the value of Packages.
+func (s *CatalogHealth) GetPackages() int {
+	return s.Packages
+}
+
+// GetLastRefresh returns the value of LastRefresh.
+func (s *CatalogHealth) GetLastRefresh() OptDateTime {
+	return s.LastRefresh
+}
+
+// GetLastError returns the value of LastError.
+func (s *CatalogHealth) GetLastError() OptString {
+	return s.LastError
+}
+
+// GetLastErrorAt returns the value of LastErrorAt.
+func (s *CatalogHealth) GetLastErrorAt() OptDateTime {
+	return s.LastErrorAt
+}
+
+// GetRefreshLatencyMs returns the value of RefreshLatencyMs.
+func (s *CatalogHealth) GetRefreshLatencyMs() OptInt64 {
+	return s.RefreshLatencyMs
+}
+
+// SetCatalogId sets the value of CatalogId.
+func (s *CatalogHealth) SetCatalogId(val string) {
+	s.CatalogId = val
+}
+
+// SetType sets the value of Type.
+func (s *CatalogHealth) SetType(val string) {
+	s.Type = val
+}
+
+// SetPackages sets the value of Packages.
+func (s *CatalogHealth) SetPackages(val int) {
+	s.Packages = val
+}
+
+// SetLastRefresh sets the value of LastRefresh.
+func (s *CatalogHealth) SetLastRefresh(val OptDateTime) {
+	s.LastRefresh = val
+}
+
+// SetLastError sets the value of LastError.
+func (s *CatalogHealth) SetLastError(val OptString) {
+	s.LastError = val
+}
+
+// SetLastErrorAt sets the value of LastErrorAt.
+func (s *CatalogHealth) SetLastErrorAt(val OptDateTime) {
+	s.LastErrorAt = val
+}
+
+// SetRefreshLatencyMs sets the value of RefreshLatencyMs.
+func (s *CatalogHealth) SetRefreshLatencyMs(val OptInt64) {
+	s.RefreshLatencyMs = val
+}
+
+func (*CatalogHealth) refreshCatalogRes() {}
+
 // Is the catalog a test or a production catalog.
 type CatalogStatus string
 
@@ -176,12 +322,25 @@ type DetailedPackage struct {
 	Name string `json:"name"`
 	// The description of the package.
 	Description OptString `json:"description"`
-	// URL to an icon.
+	// URL to an icon. It is the icon endpoint of the package, except for restricted packages whose
+	// upstream icon URL is given, as browsers load images without the user's token.
 	Icon url.URL `json:"icon"`
 	// URL to the home page.
 	Home OptURI `json:"home"`
-	// List of versions available for the package.
+	// Is the package deprecated by its maintainers.
+	Deprecated OptBool `json:"deprecated"`
+	// Category given to the package by the catalog.
+	Category OptString `json:"category"`
+	// List of versions available for the package, newest first.
 	Versions []string `json:"versions"`
+	// Details of each version, in the same order as versions.
+	VersionDetails []PackageVersionDetails `json:"versionDetails"`
+	// Version of the application shipped by the latest version.
+	AppVersion OptString `json:"appVersion"`
+	Keywords   []string  `json:"keywords"`
+	// URLs to the source code of the package.
+	Sources     []string     `json:"sources"`
+	Maintainers []Maintainer `json:"maintainers"`
 }
 
 // GetName returns the value of Name.
@@ -204,11 +363,46 @@ func (s *DetailedPackage) GetHome() OptURI {
 	return s.Home
 }
 
+// GetDeprecated returns the value of Deprecated.
+func (s *DetailedPackage) GetDeprecated() OptBool {
+	return s.Deprecated
+}
+
+// GetCategory returns the value of Category.
+func (s *DetailedPackage) GetCategory() OptString {
+	return s.Category
+}
+
 // GetVersions returns the value of Versions.
 func (s *DetailedPackage) GetVersions() []string {
 	return s.Versions
 }
 
+// GetVersionDetails returns the value of VersionDetails.
+func (s *DetailedPackage) GetVersionDetails() []PackageVersionDetails {
+	return s.VersionDetails
+}
+
+// GetAppVersion returns the value of AppVersion.
+func (s *DetailedPackage) GetAppVersion() OptString {
+	return s.AppVersion
+}
+
+// GetKeywords returns the value of Keywords.
+func (s *DetailedPackage) GetKeywords() []string {
+	return s.Keywords
+}
+
+// GetSources returns the value of Sources.
+func (s *DetailedPackage) GetSources() []string {
+	return s.Sources
+}
+
+// GetMaintainers returns the value of Maintainers.
+func (s *DetailedPackage) GetMaintainers() []Maintainer {
+	return s.Maintainers
+}
+
 // SetName sets the value of Name.
 func (s *DetailedPackage) SetName(val string) {
 	s.Name = val
@@ -229,16 +423,148 @@ func (s *DetailedPackage) SetHome(val OptURI) {
 	s.Home = val
 }
 
+// SetDeprecated sets the value of Deprecated.
+func (s *DetailedPackage) SetDeprecated(val OptBool) {
+	s.Deprecated = val
+}
+
+// SetCategory sets the value of Category.
+func (s *DetailedPackage) SetCategory(val OptString) {
+	s.Category = val
+}
+
 // SetVersions sets the value of Versions.
 func (s *DetailedPackage) SetVersions(val []string) {
 	s.Versions = val
 }
 
+// SetVersionDetails sets the value of VersionDetails.
+func (s *DetailedPackage) SetVersionDetails(val []PackageVersionDetails) {
+	s.VersionDetails = val
+}
+
+// SetAppVersion sets the value of AppVersion.
+func (s *DetailedPackage) SetAppVersion(val OptString) {
+	s.AppVersion = val
+}
+
+// SetKeywords sets the value of Keywords.
+func (s *DetailedPackage) SetKeywords(val []string) {
+	s.Keywords = val
+}
+
+// SetSources sets the value of Sources.
+func (s *DetailedPackage) SetSources(val []string) {
+	s.Sources = val
+}
+
+// SetMaintainers sets the value of Maintainers.
+func (s *DetailedPackage) SetMaintainers(val []Maintainer) {
+	s.Maintainers = val
+}
+
 func (*DetailedPackage) getMyPackageRes() {}
 
-type GetMyCatalogsOKApplicationJSON []Catalog
+type GetCatalogsHealthForbidden Problem
+
+func (*GetCatalogsHealthForbidden) getCatalogsHealthRes() {}
+
+type GetCatalogsHealthInternalServerError Problem
+
+func (*GetCatalogsHealthInternalServerError) getCatalogsHealthRes() {}
+
+type GetCatalogsHealthOKApplicationJSON []CatalogHealth
+
+func (*GetCatalogsHealthOKApplicationJSON) getCatalogsHealthRes() {}
+
+// GetMyCatalogsNotModified is response for GetMyCatalogs operation.
+type GetMyCatalogsNotModified struct {
+	CacheControl OptString
+	Etag         OptString
+	Vary         OptString
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetMyCatalogsNotModified) GetCacheControl() OptString {
+	return s.CacheControl
+}
+
+// GetEtag returns the value of Etag.
+func (s *GetMyCatalogsNotModified) GetEtag() OptString {
+	return s.Etag
+}
+
+// GetVary returns the value of Vary.
+func (s *GetMyCatalogsNotModified) GetVary() OptString {
+	return s.Vary
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetMyCatalogsNotModified) SetCacheControl(val OptString) {
+	s.CacheControl = val
+}
+
+// SetEtag sets the value of Etag.
+func (s *GetMyCatalogsNotModified) SetEtag(val OptString) {
+	s.Etag = val
+}
+
+// SetVary sets the value of Vary.
+func (s *GetMyCatalogsNotModified) SetVary(val OptString) {
+	s.Vary = val
+}
+
+func (*GetMyCatalogsNotModified) getMyCatalogsRes() {}
+
+// GetMyCatalogsOKHeaders wraps []Catalog with response headers.
+type GetMyCatalogsOKHeaders struct {
+	CacheControl OptString
+	Etag         OptString
+	Vary         OptString
+	Response     []Catalog
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetMyCatalogsOKHeaders) GetCacheControl() OptString {
+	return s.CacheControl
+}
+
+// GetEtag returns the value of Etag.
+func (s *GetMyCatalogsOKHeaders) GetEtag() OptString {
+	return s.Etag
+}
+
+// GetVary returns the value of Vary.
+func (s *GetMyCatalogsOKHeaders) GetVary() OptString {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetMyCatalogsOKHeaders) GetResponse() []Catalog {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetMyCatalogsOKHeaders) SetCacheControl(val OptString) {
+	s.CacheControl = val
+}
+
+// SetEtag sets the value of Etag.
+func (s *GetMyCatalogsOKHeaders) SetEtag(val OptString) {
+	s.Etag = val
+}
+
+// SetVary sets the value of Vary.
+func (s *GetMyCatalogsOKHeaders) SetVary(val OptString) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetMyCatalogsOKHeaders) SetResponse(val []Catalog) {
+	s.Response = val
+}
 
-func (*GetMyCatalogsOKApplicationJSON) getMyCatalogsRes() {}
+func (*GetMyCatalogsOKHeaders) getMyCatalogsRes() {}
 
 type GetMyPackageInternalServerError Problem
 
@@ -248,6 +574,124 @@ type GetMyPackageNotFound Problem
 
 func (*GetMyPackageNotFound) getMyPackageRes() {}
 
+type GetMyPresetsOKApplicationJSON []Preset
+
+func (*GetMyPresetsOKApplicationJSON) getMyPresetsRes() {}
+
+type GetPackageIconOK struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetPackageIconOK) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// GetPackageIconOKHeaders wraps GetPackageIconOK with response headers.
+type GetPackageIconOKHeaders struct {
+	CacheControl          OptString
+	ContentDisposition    OptString
+	ContentSecurityPolicy OptString
+	ContentType           string
+	XContentTypeOptions   OptString
+	Response              GetPackageIconOK
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetPackageIconOKHeaders) GetCacheControl() OptString {
+	return s.CacheControl
+}
+
+// GetContentDisposition returns the value of ContentDisposition.
+func (s *GetPackageIconOKHeaders) GetContentDisposition() OptString {
+	return s.ContentDisposition
+}
+
+// GetContentSecurityPolicy returns the value of ContentSecurityPolicy.
+func (s *GetPackageIconOKHeaders) GetContentSecurityPolicy() OptString {
+	return s.ContentSecurityPolicy
+}
+
+// GetContentType returns the value of ContentType.
+func (s *GetPackageIconOKHeaders) GetContentType() string {
+	return s.ContentType
+}
+
+// GetXContentTypeOptions returns the value of XContentTypeOptions.
+func (s *GetPackageIconOKHeaders) GetXContentTypeOptions() OptString {
+	return s.XContentTypeOptions
+}
+
+// GetResponse returns the value of Response.
+func (s *GetPackageIconOKHeaders) GetResponse() GetPackageIconOK {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetPackageIconOKHeaders) SetCacheControl(val OptString) {
+	s.CacheControl = val
+}
+
+// SetContentDisposition sets the value of ContentDisposition.
+func (s *GetPackageIconOKHeaders) SetContentDisposition(val OptString) {
+	s.ContentDisposition = val
+}
+
+// SetContentSecurityPolicy sets the value of ContentSecurityPolicy.
+func (s *GetPackageIconOKHeaders) SetContentSecurityPolicy(val OptString) {
+	s.ContentSecurityPolicy = val
+}
+
+// SetContentType sets the value of ContentType.
+func (s *GetPackageIconOKHeaders) SetContentType(val string) {
+	s.ContentType = val
+}
+
+// SetXContentTypeOptions sets the value of XContentTypeOptions.
+func (s *GetPackageIconOKHeaders) SetXContentTypeOptions(val OptString) {
+	s.XContentTypeOptions = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetPackageIconOKHeaders) SetResponse(val GetPackageIconOK) {
+	s.Response = val
+}
+
+func (*GetPackageIconOKHeaders) getPackageIconRes() {}
+
+type GetPackageReadmeBadRequest Problem
+
+func (*GetPackageReadmeBadRequest) getPackageReadmeRes() {}
+
+type GetPackageReadmeInternalServerError Problem
+
+func (*GetPackageReadmeInternalServerError) getPackageReadmeRes() {}
+
+type GetPackageReadmeNotFound Problem
+
+func (*GetPackageReadmeNotFound) getPackageReadmeRes() {}
+
+type GetPackageReadmeOK struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetPackageReadmeOK) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+func (*GetPackageReadmeOK) getPackageReadmeRes() {}
+
 type GetPackageSchemaBadRequest Problem
 
 func (*GetPackageSchemaBadRequest) getPackageSchemaRes() {}
@@ -256,6 +700,10 @@ type GetPackageSchemaInternalServerError Problem
 
 func (*GetPackageSchemaInternalServerError) getPackageSchemaRes() {}
 
+type GetPackageSchemaNotFound Problem
+
+func (*GetPackageSchemaNotFound) getPackageSchemaRes() {}
+
 type GetPackageSchemaOK map[string]jx.Raw
 
 func (s *GetPackageSchemaOK) init() GetPackageSchemaOK {
@@ -269,9 +717,36 @@ func (s *GetPackageSchemaOK) init() GetPackageSchemaOK {
 
 func (*GetPackageSchemaOK) getPackageSchemaRes() {}
 
+type GetPackageValuesBadRequest Problem
+
+func (*GetPackageValuesBadRequest) getPackageValuesRes() {}
+
+type GetPackageValuesInternalServerError Problem
+
+func (*GetPackageValuesInternalServerError) getPackageValuesRes() {}
+
+type GetPackageValuesNotFound Problem
+
+func (*GetPackageValuesNotFound) getPackageValuesRes() {}
+
+type GetPackageValuesOK map[string]jx.Raw
+
+func (s *GetPackageValuesOK) init() GetPackageValuesOK {
+	m := *s
+	if m == nil {
+		m = map[string]jx.Raw{}
+		*s = m
+	}
+	return m
+}
+
+func (*GetPackageValuesOK) getPackageValuesRes() {}
+
 // Ref: #/components/schemas/InstallAccepted
 type InstallAccepted struct {
 	EventsUrl InstallAcceptedEventsUrl `json:"eventsUrl"`
+	// Non-blocking remarks about the install, such as the use of a deprecated package.
+	Warnings []string `json:"warnings"`
 }
 
 // GetEventsUrl returns the value of EventsUrl.
@@ -279,11 +754,21 @@ func (s *InstallAccepted) GetEventsUrl() InstallAcceptedEventsUrl {
 	return s.EventsUrl
 }
 
+// GetWarnings returns the value of Warnings.
+func (s *InstallAccepted) GetWarnings() []string {
+	return s.Warnings
+}
+
 // SetEventsUrl sets the value of EventsUrl.
 func (s *InstallAccepted) SetEventsUrl(val InstallAcceptedEventsUrl) {
 	s.EventsUrl = val
 }
 
+// SetWarnings sets the value of Warnings.
+func (s *InstallAccepted) SetWarnings(val []string) {
+	s.Warnings = val
+}
+
 type InstallAcceptedEventsUrl struct {
 	Release   string `json:"release"`
 	Resources string `json:"resources"`
@@ -335,8 +820,33 @@ func (s *InstallAcceptedHeaders) SetResponse(val InstallAccepted) {
 	s.Response = val
 }
 
+func (*InstallAcceptedHeaders) installPresetRes()  {}
 func (*InstallAcceptedHeaders) installServiceRes() {}
 
+type InstallPresetBadRequest Problem
+
+func (*InstallPresetBadRequest) installPresetRes() {}
+
+type InstallPresetConflict Problem
+
+func (*InstallPresetConflict) installPresetRes() {}
+
+type InstallPresetForbidden Problem
+
+func (*InstallPresetForbidden) installPresetRes() {}
+
+type InstallPresetInternalServerError Problem
+
+func (*InstallPresetInternalServerError) installPresetRes() {}
+
+type InstallPresetNotFound Problem
+
+func (*InstallPresetNotFound) installPresetRes() {}
+
+type InstallPresetUnauthorized Problem
+
+func (*InstallPresetUnauthorized) installPresetRes() {}
+
 type InstallServiceBadRequest Problem
 
 func (*InstallServiceBadRequest) installServiceRes() {}
@@ -353,11 +863,17 @@ type InstallServiceInternalServerError Problem
 
 func (*InstallServiceInternalServerError) installServiceRes() {}
 
+type InstallServiceNotFound Problem
+
+func (*InstallServiceNotFound) installServiceRes() {}
+
 type InstallServiceUnauthorized Problem
 
 func (*InstallServiceUnauthorized) installServiceRes() {}
 
-// A string or a map of localized strings by language code.
+// A string or a map of localized strings by language code. Collapsed to the best match when the
+// request carries a `lang` query parameter or an Accept-Language header, falling back to the
+// configured default language.
 // Ref: #/components/schemas/LocalizedString
 // LocalizedString represents sum type.
 type LocalizedString struct {
@@ -434,6 +950,43 @@ func (s *LocalizedString1) init() LocalizedString1 {
 	return m
 }
 
+// Ref: #/components/schemas/Maintainer
+type Maintainer struct {
+	Name  string    `json:"name"`
+	Email OptString `json:"email"`
+	URL   OptString `json:"url"`
+}
+
+// GetName returns the value of Name.
+func (s *Maintainer) GetName() string {
+	return s.Name
+}
+
+// GetEmail returns the value of Email.
+func (s *Maintainer) GetEmail() OptString {
+	return s.Email
+}
+
+// GetURL returns the value of URL.
+func (s *Maintainer) GetURL() OptString {
+	return s.URL
+}
+
+// SetName sets the value of Name.
+func (s *Maintainer) SetName(val string) {
+	s.Name = val
+}
+
+// SetEmail sets the value of Email.
+func (s *Maintainer) SetEmail(val OptString) {
+	s.Email = val
+}
+
+// SetURL sets the value of URL.
+func (s *Maintainer) SetURL(val OptString) {
+	s.URL = val
+}
+
 type Oidc struct {
 	Request *http.Request
 	Roles   []string
@@ -505,6 +1058,52 @@ func (o OptBool) Or(d bool) bool {
 	return d
 }
 
+// NewOptCatalogError returns new OptCatalogError with value set to v.
+func NewOptCatalogError(v CatalogError) OptCatalogError {
+	return OptCatalogError{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptCatalogError is optional CatalogError.
+type OptCatalogError struct {
+	Value CatalogError
+	Set   bool
+}
+
+// IsSet returns true if OptCatalogError was set.
+func (o OptCatalogError) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptCatalogError) Reset() {
+	var v CatalogError
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptCatalogError) SetTo(v CatalogError) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptCatalogError) Get() (v CatalogError, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptCatalogError) Or(d CatalogError) CatalogError {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptCatalogStatus returns new OptCatalogStatus with value set to v.
 func NewOptCatalogStatus(v CatalogStatus) OptCatalogStatus {
 	return OptCatalogStatus{
@@ -597,6 +1196,52 @@ func (o OptCatalogVisible) Or(d CatalogVisible) CatalogVisible {
 	return d
 }
 
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptInt returns new OptInt with value set to v.
 func NewOptInt(v int) OptInt {
 	return OptInt{
@@ -643,6 +1288,52 @@ func (o OptInt) Or(d int) int {
 	return d
 }
 
+// NewOptInt64 returns new OptInt64 with value set to v.
+func NewOptInt64(v int64) OptInt64 {
+	return OptInt64{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt64 is optional int64.
+type OptInt64 struct {
+	Value int64
+	Set   bool
+}
+
+// IsSet returns true if OptInt64 was set.
+func (o OptInt64) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt64) Reset() {
+	var v int64
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt64) SetTo(v int64) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt64) Get() (v int64, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt64) Or(d int64) int64 {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptLocalizedString returns new OptLocalizedString with value set to v.
 func NewOptLocalizedString(v LocalizedString) OptLocalizedString {
 	return OptLocalizedString{
@@ -689,6 +1380,98 @@ func (o OptLocalizedString) Or(d LocalizedString) LocalizedString {
 	return d
 }
 
+// NewOptPresetInstallRequestOptions returns new OptPresetInstallRequestOptions with value set to v.
+func NewOptPresetInstallRequestOptions(v PresetInstallRequestOptions) OptPresetInstallRequestOptions {
+	return OptPresetInstallRequestOptions{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptPresetInstallRequestOptions is optional PresetInstallRequestOptions.
+type OptPresetInstallRequestOptions struct {
+	Value PresetInstallRequestOptions
+	Set   bool
+}
+
+// IsSet returns true if OptPresetInstallRequestOptions was set.
+func (o OptPresetInstallRequestOptions) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptPresetInstallRequestOptions) Reset() {
+	var v PresetInstallRequestOptions
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptPresetInstallRequestOptions) SetTo(v PresetInstallRequestOptions) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptPresetInstallRequestOptions) Get() (v PresetInstallRequestOptions, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptPresetInstallRequestOptions) Or(d PresetInstallRequestOptions) PresetInstallRequestOptions {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptPresetValues returns new OptPresetValues with value set to v.
+func NewOptPresetValues(v PresetValues) OptPresetValues {
+	return OptPresetValues{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptPresetValues is optional PresetValues.
+type OptPresetValues struct {
+	Value PresetValues
+	Set   bool
+}
+
+// IsSet returns true if OptPresetValues was set.
+func (o OptPresetValues) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptPresetValues) Reset() {
+	var v PresetValues
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptPresetValues) SetTo(v PresetValues) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptPresetValues) Get() (v PresetValues, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptPresetValues) Or(d PresetValues) PresetValues {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptString returns new OptString with value set to v.
 func NewOptString(v string) OptString {
 	return OptString{
@@ -787,10 +1570,15 @@ type Package struct {
 	Name string `json:"name"`
 	// The description of the package.
 	Description OptString `json:"description"`
-	// URL to an icon.
+	// URL to an icon. It is the icon endpoint of the package, except for restricted packages whose
+	// upstream icon URL is given, as browsers load images without the user's token.
 	Icon url.URL `json:"icon"`
 	// URL to the home page.
 	Home OptURI `json:"home"`
+	// Is the package deprecated by its maintainers.
+	Deprecated OptBool `json:"deprecated"`
+	// Category given to the package by the catalog.
+	Category OptString `json:"category"`
 }
 
 // GetName returns the value of Name.
@@ -813,6 +1601,16 @@ func (s *Package) GetHome() OptURI {
 	return s.Home
 }
 
+// GetDeprecated returns the value of Deprecated.
+func (s *Package) GetDeprecated() OptBool {
+	return s.Deprecated
+}
+
+// GetCategory returns the value of Category.
+func (s *Package) GetCategory() OptString {
+	return s.Category
+}
+
 // SetName sets the value of Name.
 func (s *Package) SetName(val string) {
 	s.Name = val
@@ -833,6 +1631,405 @@ func (s *Package) SetHome(val OptURI) {
 	s.Home = val
 }
 
+// SetDeprecated sets the value of Deprecated.
+func (s *Package) SetDeprecated(val OptBool) {
+	s.Deprecated = val
+}
+
+// SetCategory sets the value of Category.
+func (s *Package) SetCategory(val OptString) {
+	s.Category = val
+}
+
+// Merged schema.
+// Ref: #/components/schemas/PackageSearchResult
+type PackageSearchResult struct {
+	// Package name.
+	Name string `json:"name"`
+	// The description of the package.
+	Description OptString `json:"description"`
+	// URL to an icon. It is the icon endpoint of the package, except for restricted packages whose
+	// upstream icon URL is given, as browsers load images without the user's token.
+	Icon url.URL `json:"icon"`
+	// URL to the home page.
+	Home OptURI `json:"home"`
+	// Is the package deprecated by its maintainers.
+	Deprecated OptBool `json:"deprecated"`
+	// Category given to the package by the catalog.
+	Category OptString `json:"category"`
+	// Catalog of the package.
+	CatalogId string `json:"catalogId"`
+	// Keywords of the package.
+	Keywords []string `json:"keywords"`
+	// Is the package highlighted by its catalog.
+	Highlighted OptBool `json:"highlighted"`
+	// Relevance score, higher is better.
+	Score int `json:"score"`
+}
+
+// GetName returns the value of Name.
+func (s *PackageSearchResult) GetName() string {
+	return s.Name
+}
+
+// GetDescription returns the value of Description.
+func (s *PackageSearchResult) GetDescription() OptString {
+	return s.Description
+}
+
+// GetIcon returns the value of Icon.
+func (s *PackageSearchResult) GetIcon() url.URL {
+	return s.Icon
+}
+
+// GetHome returns the value of Home.
+func (s *PackageSearchResult) GetHome() OptURI {
+	return s.Home
+}
+
+// GetDeprecated returns the value of Deprecated.
+func (s *PackageSearchResult) GetDeprecated() OptBool {
+	return s.Deprecated
+}
+
+// GetCategory returns the value of Category.
+func (s *PackageSearchResult) GetCategory() OptString {
+	return s.Category
+}
+
+// GetCatalogId returns the value of CatalogId.
+func (s *PackageSearchResult) GetCatalogId() string {
+	return s.CatalogId
+}
+
+// GetKeywords returns the value of Keywords.
+func (s *PackageSearchResult) GetKeywords() []string {
+	return s.Keywords
+}
+
+// GetHighlighted returns the value of Highlighted.
+func (s *PackageSearchResult) GetHighlighted() OptBool {
+	return s.Highlighted
+}
+
+// GetScore returns the value of Score.
+func (s *PackageSearchResult) GetScore() int {
+	return s.Score
+}
+
+// SetName sets the value of Name.
+func (s *PackageSearchResult) SetName(val string) {
+	s.Name = val
+}
+
+// SetDescription sets the value of Description.
+func (s *PackageSearchResult) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetIcon sets the value of Icon.
+func (s *PackageSearchResult) SetIcon(val url.URL) {
+	s.Icon = val
+}
+
+// SetHome sets the value of Home.
+func (s *PackageSearchResult) SetHome(val OptURI) {
+	s.Home = val
+}
+
+// SetDeprecated sets the value of Deprecated.
+func (s *PackageSearchResult) SetDeprecated(val OptBool) {
+	s.Deprecated = val
+}
+
+// SetCategory sets the value of Category.
+func (s *PackageSearchResult) SetCategory(val OptString) {
+	s.Category = val
+}
+
+// SetCatalogId sets the value of CatalogId.
+func (s *PackageSearchResult) SetCatalogId(val string) {
+	s.CatalogId = val
+}
+
+// SetKeywords sets the value of Keywords.
+func (s *PackageSearchResult) SetKeywords(val []string) {
+	s.Keywords = val
+}
+
+// SetHighlighted sets the value of Highlighted.
+func (s *PackageSearchResult) SetHighlighted(val OptBool) {
+	s.Highlighted = val
+}
+
+// SetScore sets the value of Score.
+func (s *PackageSearchResult) SetScore(val int) {
+	s.Score = val
+}
+
+// Ref: #/components/schemas/PackageVersionDetails
+type PackageVersionDetails struct {
+	Version string `json:"version"`
+	// Version of the application shipped.
+	AppVersion OptString `json:"appVersion"`
+	// Publication date (Helm repositories only).
+	Created OptDateTime `json:"created"`
+	// Digest of the chart archive (Helm repositories only).
+	Digest OptString `json:"digest"`
+	// Is this version deprecated.
+	Deprecated OptBool `json:"deprecated"`
+	// Did this version fail the catalog provenance check.
+	Unverified OptBool `json:"unverified"`
+}
+
+// GetVersion returns the value of Version.
+func (s *PackageVersionDetails) GetVersion() string {
+	return s.Version
+}
+
+// GetAppVersion returns the value of AppVersion.
+func (s *PackageVersionDetails) GetAppVersion() OptString {
+	return s.AppVersion
+}
+
+// GetCreated returns the value of Created.
+func (s *PackageVersionDetails) GetCreated() OptDateTime {
+	return s.Created
+}
+
+// GetDigest returns the value of Digest.
+func (s *PackageVersionDetails) GetDigest() OptString {
+	return s.Digest
+}
+
+// GetDeprecated returns the value of Deprecated.
+func (s *PackageVersionDetails) GetDeprecated() OptBool {
+	return s.Deprecated
+}
+
+// GetUnverified returns the value of Unverified.
+func (s *PackageVersionDetails) GetUnverified() OptBool {
+	return s.Unverified
+}
+
+// SetVersion sets the value of Version.
+func (s *PackageVersionDetails) SetVersion(val string) {
+	s.Version = val
+}
+
+// SetAppVersion sets the value of AppVersion.
+func (s *PackageVersionDetails) SetAppVersion(val OptString) {
+	s.AppVersion = val
+}
+
+// SetCreated sets the value of Created.
+func (s *PackageVersionDetails) SetCreated(val OptDateTime) {
+	s.Created = val
+}
+
+// SetDigest sets the value of Digest.
+func (s *PackageVersionDetails) SetDigest(val OptString) {
+	s.Digest = val
+}
+
+// SetDeprecated sets the value of Deprecated.
+func (s *PackageVersionDetails) SetDeprecated(val OptBool) {
+	s.Deprecated = val
+}
+
+// SetUnverified sets the value of Unverified.
+func (s *PackageVersionDetails) SetUnverified(val OptBool) {
+	s.Unverified = val
+}
+
+// Ref: #/components/schemas/Preset
+type Preset struct {
+	// Catalog defining the preset.
+	CatalogId string `json:"catalogId"`
+	// Preset id.
+	ID string `json:"id"`
+	// The name of the preset.
+	Name LocalizedString `json:"name"`
+	// The description of the preset.
+	Description OptLocalizedString `json:"description"`
+	// Package the preset installs.
+	PackageName string `json:"packageName"`
+	// Version installed; absent when the preset follows the latest version.
+	Version OptString `json:"version"`
+	// Values the preset installs the package with.
+	Values OptPresetValues `json:"values"`
+}
+
+// GetCatalogId returns the value of CatalogId.
+func (s *Preset) GetCatalogId() string {
+	return s.CatalogId
+}
+
+// GetID returns the value of ID.
+func (s *Preset) GetID() string {
+	return s.ID
+}
+
+// GetName returns the value of Name.
+func (s *Preset) GetName() LocalizedString {
+	return s.Name
+}
+
+// GetDescription returns the value of Description.
+func (s *Preset) GetDescription() OptLocalizedString {
+	return s.Description
+}
+
+// GetPackageName returns the value of PackageName.
+func (s *Preset) GetPackageName() string {
+	return s.PackageName
+}
+
+// GetVersion returns the value of Version.
+func (s *Preset) GetVersion() OptString {
+	return s.Version
+}
+
+// GetValues returns the value of Values.
+func (s *Preset) GetValues() OptPresetValues {
+	return s.Values
+}
+
+// SetCatalogId sets the value of CatalogId.
+func (s *Preset) SetCatalogId(val string) {
+	s.CatalogId = val
+}
+
+// SetID sets the value of ID.
+func (s *Preset) SetID(val string) {
+	s.ID = val
+}
+
+// SetName sets the value of Name.
+func (s *Preset) SetName(val LocalizedString) {
+	s.Name = val
+}
+
+// SetDescription sets the value of Description.
+func (s *Preset) SetDescription(val OptLocalizedString) {
+	s.Description = val
+}
+
+// SetPackageName sets the value of PackageName.
+func (s *Preset) SetPackageName(val string) {
+	s.PackageName = val
+}
+
+// SetVersion sets the value of Version.
+func (s *Preset) SetVersion(val OptString) {
+	s.Version = val
+}
+
+// SetValues sets the value of Values.
+func (s *Preset) SetValues(val OptPresetValues) {
+	s.Values = val
+}
+
+// Ref: #/components/schemas/PresetInstallRequest
+type PresetInstallRequest struct {
+	// Catalog defining the preset.
+	CatalogId string `json:"catalogId"`
+	// Preset to install.
+	PresetId string `json:"presetId"`
+	// Options merged over the preset values.
+	Options OptPresetInstallRequestOptions `json:"options"`
+	// When true, visible to all users of the namespace.
+	Share OptBool `json:"share"`
+	// Friendly name for the service.
+	FriendlyName OptString `json:"friendlyName"`
+	// A chosen name for the service.
+	Name string `json:"name"`
+}
+
+// GetCatalogId returns the value of CatalogId.
+func (s *PresetInstallRequest) GetCatalogId() string {
+	return s.CatalogId
+}
+
+// GetPresetId returns the value of PresetId.
+func (s *PresetInstallRequest) GetPresetId() string {
+	return s.PresetId
+}
+
+// GetOptions returns the value of Options.
+func (s *PresetInstallRequest) GetOptions() OptPresetInstallRequestOptions {
+	return s.Options
+}
+
+// GetShare returns the value of Share.
+func (s *PresetInstallRequest) GetShare() OptBool {
+	return s.Share
+}
+
+// GetFriendlyName returns the value of FriendlyName.
+func (s *PresetInstallRequest) GetFriendlyName() OptString {
+	return s.FriendlyName
+}
+
+// GetName returns the value of Name.
+func (s *PresetInstallRequest) GetName() string {
+	return s.Name
+}
+
+// SetCatalogId sets the value of CatalogId.
+func (s *PresetInstallRequest) SetCatalogId(val string) {
+	s.CatalogId = val
+}
+
+// SetPresetId sets the value of PresetId.
+func (s *PresetInstallRequest) SetPresetId(val string) {
+	s.PresetId = val
+}
+
+// SetOptions sets the value of Options.
+func (s *PresetInstallRequest) SetOptions(val OptPresetInstallRequestOptions) {
+	s.Options = val
+}
+
+// SetShare sets the value of Share.
+func (s *PresetInstallRequest) SetShare(val OptBool) {
+	s.Share = val
+}
+
+// SetFriendlyName sets the value of FriendlyName.
+func (s *PresetInstallRequest) SetFriendlyName(val OptString) {
+	s.FriendlyName = val
+}
+
+// SetName sets the value of Name.
+func (s *PresetInstallRequest) SetName(val string) {
+	s.Name = val
+}
+
+// Options merged over the preset values.
+type PresetInstallRequestOptions map[string]jx.Raw
+
+func (s *PresetInstallRequestOptions) init() PresetInstallRequestOptions {
+	m := *s
+	if m == nil {
+		m = map[string]jx.Raw{}
+		*s = m
+	}
+	return m
+}
+
+// Values the preset installs the package with.
+type PresetValues map[string]jx.Raw
+
+func (s *PresetValues) init() PresetValues {
+	m := *s
+	if m == nil {
+		m = map[string]jx.Raw{}
+		*s = m
+	}
+	return m
+}
+
 // Ref: #/components/schemas/Problem
 type Problem struct {
 	Type            OptURI    `json:"type"`
@@ -903,7 +2100,9 @@ func (s *Problem) SetAdditionalProps(val ProblemAdditional) {
 	s.AdditionalProps = val
 }
 
-func (*Problem) getMyCatalogsRes() {}
+func (*Problem) getMyCatalogsRes()  {}
+func (*Problem) getMyPresetsRes()   {}
+func (*Problem) getPackageIconRes() {}
 
 type ProblemAdditional map[string]jx.Raw
 
@@ -916,6 +2115,30 @@ func (s *ProblemAdditional) init() ProblemAdditional {
 	return m
 }
 
+type RefreshCatalogForbidden Problem
+
+func (*RefreshCatalogForbidden) refreshCatalogRes() {}
+
+type RefreshCatalogInternalServerError Problem
+
+func (*RefreshCatalogInternalServerError) refreshCatalogRes() {}
+
+type RefreshCatalogNotFound Problem
+
+func (*RefreshCatalogNotFound) refreshCatalogRes() {}
+
+type SearchPackagesBadRequest Problem
+
+func (*SearchPackagesBadRequest) searchPackagesRes() {}
+
+type SearchPackagesInternalServerError Problem
+
+func (*SearchPackagesInternalServerError) searchPackagesRes() {}
+
+type SearchPackagesOKApplicationJSON []PackageSearchResult
+
+func (*SearchPackagesOKApplicationJSON) searchPackagesRes() {}
+
 // Ref: #/components/schemas/ServiceInstallRequest
 type ServiceInstallRequest struct {
 	// Catalog where the package is taken from.
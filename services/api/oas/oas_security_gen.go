@@ -36,12 +36,20 @@ func findAuthorization(h http.Header, prefix string) (string, bool) {
 
 // operationRolesOidc is a private map storing roles per operation.
 var operationRolesOidc = map[string][]string{
-	GetMyCatalogsOperation:    {},
-	GetMyPackageOperation:     {},
-	GetPackageSchemaOperation: {},
-	InstallServiceOperation:   {},
-	WatchReleaseOperation:     {},
-	WatchResourcesOperation:   {},
+	GetCatalogsHealthOperation: {},
+	GetMyCatalogsOperation:     {},
+	GetMyPackageOperation:      {},
+	GetMyPresetsOperation:      {},
+	GetPackageIconOperation:    {},
+	GetPackageReadmeOperation:  {},
+	GetPackageSchemaOperation:  {},
+	GetPackageValuesOperation:  {},
+	InstallPresetOperation:     {},
+	InstallServiceOperation:    {},
+	RefreshCatalogOperation:    {},
+	SearchPackagesOperation:    {},
+	WatchReleaseOperation:      {},
+	WatchResourcesOperation:    {},
 }
 
 // GetRolesForOidc returns the required roles for the given operation.
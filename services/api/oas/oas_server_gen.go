@@ -8,19 +8,53 @@ import (
 
 // Handler handles operations described by OpenAPI v3 specification.
 type Handler interface {
+	// GetCatalogsHealth implements getCatalogsHealth operation.
+	//
+	// Reports, for each catalog, the last successful index refresh, the last error, the number of
+	// packages and the refresh latency. Restricted to users holding one of the configured admin roles.
+	//
+	// GET /api/services/admin/catalogs/health
+	GetCatalogsHealth(ctx context.Context) (GetCatalogsHealthRes, error)
 	// GetMyCatalogs implements getMyCatalogs operation.
 	//
 	// Returns the list of catalogs and packages available for the user. The list of packages is filtered
-	// by user permissions if the user is authenticated. Otherwise returns the public catalog.
+	// by user permissions if the user is authenticated. Otherwise returns the public catalog. When a
+	// project is given, only catalogs visible in project context are returned, otherwise only catalogs
+	// visible in user context. When the client asks for a language with the `lang` query parameter or
+	// the Accept-Language header, catalog names and descriptions are returned as plain strings in the
+	// best available language.
 	//
 	// GET /api/services/catalogs
-	GetMyCatalogs(ctx context.Context) (GetMyCatalogsRes, error)
+	GetMyCatalogs(ctx context.Context, params GetMyCatalogsParams) (GetMyCatalogsRes, error)
 	// GetMyPackage implements getMyPackage operation.
 	//
 	// Returns detailed information about a package in a catalog, including available versions.
 	//
 	// GET /api/services/catalogs/{catalogId}/packages/{packageName}
 	GetMyPackage(ctx context.Context, params GetMyPackageParams) (GetMyPackageRes, error)
+	// GetMyPresets implements getMyPresets operation.
+	//
+	// Returns the presets of the catalogs available to the user, with the same visibility rules as the
+	// catalog list. Presets whose values do not match the chart schema are left out.
+	//
+	// GET /api/services/presets
+	GetMyPresets(ctx context.Context, params GetMyPresetsParams) (GetMyPresetsRes, error)
+	// GetPackageIcon implements getPackageIcon operation.
+	//
+	// Serves the icon of a package, fetched from its upstream location and cached by the server. A
+	// default icon is returned when the package has no icon or it cannot be fetched. Catalog
+	// restrictions apply: icons of packages the user may not access are not found. Icons are served with
+	// a sandboxing Content-Security-Policy, so SVG icons run no script.
+	//
+	// GET /api/services/catalogs/{catalogId}/packages/{packageName}/icon
+	GetPackageIcon(ctx context.Context, params GetPackageIconParams) (GetPackageIconRes, error)
+	// GetPackageReadme implements getPackageReadme operation.
+	//
+	// Returns the README of the chart, as markdown. The chart is downloaded once and shared with the
+	// schema and default values endpoints.
+	//
+	// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/readme
+	GetPackageReadme(ctx context.Context, params GetPackageReadmeParams) (GetPackageReadmeRes, error)
 	// GetPackageSchema implements getPackageSchema operation.
 	//
 	// Returns the values.schema.json of a versioned package. The schema is enhanced by user permissions
@@ -28,13 +62,43 @@ type Handler interface {
 	//
 	// GET /api/services/schemas/{catalogId}/packageName/{packageName}/versions/{version}
 	GetPackageSchema(ctx context.Context, params GetPackageSchemaParams) (GetPackageSchemaRes, error)
+	// GetPackageValues implements getPackageValues operation.
+	//
+	// Returns the default values.yaml of the chart, converted to JSON.
+	//
+	// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/values
+	GetPackageValues(ctx context.Context, params GetPackageValuesParams) (GetPackageValuesRes, error)
+	// InstallPreset implements installPreset operation.
+	//
+	// Starts an install of the package of a catalog preset. The options of the request are merged over
+	// the preset values, nested objects being merged key by key. Responds like installService.
+	//
+	// PUT /api/services/{releaseId}/install-preset
+	InstallPreset(ctx context.Context, req *PresetInstallRequest, params InstallPresetParams) (InstallPresetRes, error)
 	// InstallService implements installService operation.
 	//
 	// Starts an install for the given releaseId. Returns 202 with URLs for SSE streams. Idempotent if
-	// the release already exists (returns 202 with the same event URLs).
+	// the release already exists (returns 202 with the same event URLs). Catalogs and packages the user
+	// may not access answer 404, like reads; 403 means the version is deprecated and only kept for
+	// running services.
 	//
 	// PUT /api/services/{releaseId}/install
 	InstallService(ctx context.Context, req *ServiceInstallRequest, params InstallServiceParams) (InstallServiceRes, error)
+	// RefreshCatalog implements refreshCatalog operation.
+	//
+	// Downloads the catalog index again, bypassing the cache, and returns the resulting status. A failed
+	// refresh is reported in lastError. Restricted to users holding one of the configured admin roles.
+	//
+	// POST /api/services/admin/catalogs/{catalogId}/refresh
+	RefreshCatalog(ctx context.Context, params RefreshCatalogParams) (RefreshCatalogRes, error)
+	// SearchPackages implements searchPackages operation.
+	//
+	// Searches package names, descriptions and keywords across every catalog the user may access (public
+	// catalogs only when unauthenticated), with the same visibility rules as the catalog list. Results
+	// are ranked by relevance, highlighted packages first on ties.
+	//
+	// GET /api/services/packages
+	SearchPackages(ctx context.Context, params SearchPackagesParams) (SearchPackagesRes, error)
 	// WatchRelease implements watchRelease operation.
 	//
 	// Server-Sent Events (text/event-stream). Emits: "status", "log" (optional), and "done".
@@ -13,13 +13,27 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// GetCatalogsHealth implements getCatalogsHealth operation.
+//
+// Reports, for each catalog, the last successful index refresh, the last error, the number of
+// packages and the refresh latency. Restricted to users holding one of the configured admin roles.
+//
+// GET /api/services/admin/catalogs/health
+func (UnimplementedHandler) GetCatalogsHealth(ctx context.Context) (r GetCatalogsHealthRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // GetMyCatalogs implements getMyCatalogs operation.
 //
 // Returns the list of catalogs and packages available for the user. The list of packages is filtered
-// by user permissions if the user is authenticated. Otherwise returns the public catalog.
+// by user permissions if the user is authenticated. Otherwise returns the public catalog. When a
+// project is given, only catalogs visible in project context are returned, otherwise only catalogs
+// visible in user context. When the client asks for a language with the `lang` query parameter or
+// the Accept-Language header, catalog names and descriptions are returned as plain strings in the
+// best available language.
 //
 // GET /api/services/catalogs
-func (UnimplementedHandler) GetMyCatalogs(ctx context.Context) (r GetMyCatalogsRes, _ error) {
+func (UnimplementedHandler) GetMyCatalogs(ctx context.Context, params GetMyCatalogsParams) (r GetMyCatalogsRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
@@ -32,6 +46,38 @@ func (UnimplementedHandler) GetMyPackage(ctx context.Context, params GetMyPackag
 	return r, ht.ErrNotImplemented
 }
 
+// GetMyPresets implements getMyPresets operation.
+//
+// Returns the presets of the catalogs available to the user, with the same visibility rules as the
+// catalog list. Presets whose values do not match the chart schema are left out.
+//
+// GET /api/services/presets
+func (UnimplementedHandler) GetMyPresets(ctx context.Context, params GetMyPresetsParams) (r GetMyPresetsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetPackageIcon implements getPackageIcon operation.
+//
+// Serves the icon of a package, fetched from its upstream location and cached by the server. A
+// default icon is returned when the package has no icon or it cannot be fetched. Catalog
+// restrictions apply: icons of packages the user may not access are not found. Icons are served with
+// a sandboxing Content-Security-Policy, so SVG icons run no script.
+//
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}/icon
+func (UnimplementedHandler) GetPackageIcon(ctx context.Context, params GetPackageIconParams) (r GetPackageIconRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetPackageReadme implements getPackageReadme operation.
+//
+// Returns the README of the chart, as markdown. The chart is downloaded once and shared with the
+// schema and default values endpoints.
+//
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/readme
+func (UnimplementedHandler) GetPackageReadme(ctx context.Context, params GetPackageReadmeParams) (r GetPackageReadmeRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // GetPackageSchema implements getPackageSchema operation.
 //
 // Returns the values.schema.json of a versioned package. The schema is enhanced by user permissions
@@ -42,16 +88,58 @@ func (UnimplementedHandler) GetPackageSchema(ctx context.Context, params GetPack
 	return r, ht.ErrNotImplemented
 }
 
+// GetPackageValues implements getPackageValues operation.
+//
+// Returns the default values.yaml of the chart, converted to JSON.
+//
+// GET /api/services/catalogs/{catalogId}/packages/{packageName}/versions/{version}/values
+func (UnimplementedHandler) GetPackageValues(ctx context.Context, params GetPackageValuesParams) (r GetPackageValuesRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// InstallPreset implements installPreset operation.
+//
+// Starts an install of the package of a catalog preset. The options of the request are merged over
+// the preset values, nested objects being merged key by key. Responds like installService.
+//
+// PUT /api/services/{releaseId}/install-preset
+func (UnimplementedHandler) InstallPreset(ctx context.Context, req *PresetInstallRequest, params InstallPresetParams) (r InstallPresetRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // InstallService implements installService operation.
 //
 // Starts an install for the given releaseId. Returns 202 with URLs for SSE streams. Idempotent if
-// the release already exists (returns 202 with the same event URLs).
+// the release already exists (returns 202 with the same event URLs). Catalogs and packages the user
+// may not access answer 404, like reads; 403 means the version is deprecated and only kept for
+// running services.
 //
 // PUT /api/services/{releaseId}/install
 func (UnimplementedHandler) InstallService(ctx context.Context, req *ServiceInstallRequest, params InstallServiceParams) (r InstallServiceRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
 
+// RefreshCatalog implements refreshCatalog operation.
+//
+// Downloads the catalog index again, bypassing the cache, and returns the resulting status. A failed
+// refresh is reported in lastError. Restricted to users holding one of the configured admin roles.
+//
+// POST /api/services/admin/catalogs/{catalogId}/refresh
+func (UnimplementedHandler) RefreshCatalog(ctx context.Context, params RefreshCatalogParams) (r RefreshCatalogRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SearchPackages implements searchPackages operation.
+//
+// Searches package names, descriptions and keywords across every catalog the user may access (public
+// catalogs only when unauthenticated), with the same visibility rules as the catalog list. Results
+// are ranked by relevance, highlighted packages first on ties.
+//
+// GET /api/services/packages
+func (UnimplementedHandler) SearchPackages(ctx context.Context, params SearchPackagesParams) (r SearchPackagesRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // WatchRelease implements watchRelease operation.
 //
 // Server-Sent Events (text/event-stream). Emits: "status", "log" (optional), and "done".
@@ -44,12 +44,41 @@ func (s *Catalog) Validate() error {
 			Error: err,
 		})
 	}
+	if err := func() error {
+		if value, ok := s.Error.Get(); ok {
+			if err := func() error {
+				if err := value.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		failures = append(failures, validate.FieldError{
+			Name:  "error",
+			Error: err,
+		})
+	}
 	if len(failures) > 0 {
 		return &validate.Error{Fields: failures}
 	}
 	return nil
 }
 
+func (s CatalogError) Validate() error {
+	switch s {
+	case "timeout":
+		return nil
+	case "unavailable":
+		return nil
+	default:
+		return errors.Errorf("invalid value: %v", s)
+	}
+}
+
 func (s CatalogStatus) Validate() error {
 	switch s {
 	case "PROD":
@@ -78,30 +107,64 @@ func (s *DetailedPackage) Validate() error {
 			Error: err,
 		})
 	}
+	if err := func() error {
+		if s.VersionDetails == nil {
+			return errors.New("nil is invalid value")
+		}
+		return nil
+	}(); err != nil {
+		failures = append(failures, validate.FieldError{
+			Name:  "versionDetails",
+			Error: err,
+		})
+	}
 	if len(failures) > 0 {
 		return &validate.Error{Fields: failures}
 	}
 	return nil
 }
 
-func (s GetMyCatalogsOKApplicationJSON) Validate() error {
-	alias := ([]Catalog)(s)
+func (s GetCatalogsHealthOKApplicationJSON) Validate() error {
+	alias := ([]CatalogHealth)(s)
 	if alias == nil {
 		return errors.New("nil is invalid value")
 	}
+	return nil
+}
+
+func (s *GetMyCatalogsOKHeaders) Validate() error {
+	if s == nil {
+		return validate.ErrNilPointer
+	}
+
 	var failures []validate.FieldError
-	for i, elem := range alias {
-		if err := func() error {
-			if err := elem.Validate(); err != nil {
-				return err
+	if err := func() error {
+		if s.Response == nil {
+			return errors.New("nil is invalid value")
+		}
+		var failures []validate.FieldError
+		for i, elem := range s.Response {
+			if err := func() error {
+				if err := elem.Validate(); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				failures = append(failures, validate.FieldError{
+					Name:  fmt.Sprintf("[%d]", i),
+					Error: err,
+				})
 			}
-			return nil
-		}(); err != nil {
-			failures = append(failures, validate.FieldError{
-				Name:  fmt.Sprintf("[%d]", i),
-				Error: err,
-			})
 		}
+		if len(failures) > 0 {
+			return &validate.Error{Fields: failures}
+		}
+		return nil
+	}(); err != nil {
+		failures = append(failures, validate.FieldError{
+			Name:  "Response",
+			Error: err,
+		})
 	}
 	if len(failures) > 0 {
 		return &validate.Error{Fields: failures}
@@ -109,6 +172,14 @@ func (s GetMyCatalogsOKApplicationJSON) Validate() error {
 	return nil
 }
 
+func (s GetMyPresetsOKApplicationJSON) Validate() error {
+	alias := ([]Preset)(s)
+	if alias == nil {
+		return errors.New("nil is invalid value")
+	}
+	return nil
+}
+
 func (s *Oidc) Validate() error {
 	if s == nil {
 		return validate.ErrNilPointer
@@ -131,3 +202,11 @@ func (s *Oidc) Validate() error {
 	}
 	return nil
 }
+
+func (s SearchPackagesOKApplicationJSON) Validate() error {
+	alias := ([]PackageSearchResult)(s)
+	if alias == nil {
+		return errors.New("nil is invalid value")
+	}
+	return nil
+}
@@ -7,18 +7,31 @@ import (
 	"github.com/onyxia-datalab/onyxia-backend/services/usecase"
 )
 
-func SetupCatalogController(app *bootstrap.Application) (*controller.CatalogController, error) {
+func SetupCatalogController(
+	app *bootstrap.Application,
+	pkgRepo *helm.HelmPackageRepository,
+	policy *usecase.CatalogPolicy,
+) *controller.CatalogController {
+	catalogUc := usecase.NewCatalogService(
+		app.Env.CatalogsConfig,
+		pkgRepo,
+		policy,
+		app.Env.CatalogsLoadTimeout,
+	)
 
-	pkgRepo, err := helm.NewPackageRepository(app.Env.CatalogsConfig, "")
-	if err != nil {
-		return nil, err
-	}
+	return controller.NewCatalogController(catalogUc, app.UserContextReader)
+}
 
-	catalogUc := usecase.NewCatalogService(
+func SetupCatalogHealthController(
+	app *bootstrap.Application,
+	pkgRepo *helm.HelmPackageRepository,
+) *controller.CatalogHealthController {
+	healthUc := usecase.NewCatalogHealthService(
 		app.Env.CatalogsConfig,
 		pkgRepo,
 		app.UserContextReader,
+		app.Env.Security.AdminRoles,
 	)
 
-	return controller.NewCatalogController(catalogUc, app.UserContextReader), nil
+	return controller.NewCatalogHealthController(healthUc)
 }
@@ -10,16 +10,20 @@ import (
 
 type Handler struct {
 	install  *controller.InstallController
+	presets  *controller.PresetController
 	catalogs *controller.CatalogController
+	health   *controller.CatalogHealthController
 }
 
 var _ api.Handler = (*Handler)(nil)
 
 func NewHandler(
 	install *controller.InstallController,
+	presets *controller.PresetController,
 	catalogs *controller.CatalogController,
+	health *controller.CatalogHealthController,
 ) *Handler {
-	return &Handler{install: install, catalogs: catalogs}
+	return &Handler{install: install, presets: presets, catalogs: catalogs, health: health}
 }
 
 func (h *Handler) InstallService(
@@ -30,8 +34,26 @@ func (h *Handler) InstallService(
 	return h.install.InstallService(ctx, req, p)
 }
 
-func (h *Handler) GetMyCatalogs(ctx context.Context) (api.GetMyCatalogsRes, error) {
-	return h.catalogs.GetMyCatalogs(ctx)
+func (h *Handler) InstallPreset(
+	ctx context.Context,
+	req *api.PresetInstallRequest,
+	p api.InstallPresetParams,
+) (api.InstallPresetRes, error) {
+	return h.presets.InstallPreset(ctx, req, p)
+}
+
+func (h *Handler) GetMyPresets(
+	ctx context.Context,
+	p api.GetMyPresetsParams,
+) (api.GetMyPresetsRes, error) {
+	return h.presets.GetMyPresets(ctx, p.XOnyxiaProject.Or(""))
+}
+
+func (h *Handler) GetMyCatalogs(
+	ctx context.Context,
+	p api.GetMyCatalogsParams,
+) (api.GetMyCatalogsRes, error) {
+	return h.catalogs.GetMyCatalogs(ctx, p.XOnyxiaProject.Or(""), p.IfNoneMatch.Or(""))
 }
 
 // Keep stubs explicit until implemented (or embed api.UnimplementedHandler if you prefer 501s)
@@ -55,9 +77,48 @@ func (h *Handler) GetMyPackage(
 ) (api.GetMyPackageRes, error) {
 	return h.catalogs.GetMyPackage(ctx, p.CatalogId, p.PackageName)
 }
+func (h *Handler) SearchPackages(
+	ctx context.Context,
+	p api.SearchPackagesParams,
+) (api.SearchPackagesRes, error) {
+	return h.catalogs.SearchPackages(ctx, p.XOnyxiaProject.Or(""), p.Q, p.Limit.Or(50))
+}
+
 func (h *Handler) GetPackageSchema(
 	ctx context.Context,
 	p api.GetPackageSchemaParams,
 ) (api.GetPackageSchemaRes, error) {
 	return h.catalogs.GetPackageSchema(ctx, p.CatalogId, p.PackageName, p.Version)
 }
+
+func (h *Handler) GetPackageReadme(
+	ctx context.Context,
+	p api.GetPackageReadmeParams,
+) (api.GetPackageReadmeRes, error) {
+	return h.catalogs.GetPackageReadme(ctx, p.CatalogId, p.PackageName, p.Version)
+}
+
+func (h *Handler) GetPackageValues(
+	ctx context.Context,
+	p api.GetPackageValuesParams,
+) (api.GetPackageValuesRes, error) {
+	return h.catalogs.GetPackageValues(ctx, p.CatalogId, p.PackageName, p.Version)
+}
+
+func (h *Handler) GetPackageIcon(
+	ctx context.Context,
+	p api.GetPackageIconParams,
+) (api.GetPackageIconRes, error) {
+	return h.catalogs.GetPackageIcon(ctx, p.CatalogId, p.PackageName)
+}
+
+func (h *Handler) GetCatalogsHealth(ctx context.Context) (api.GetCatalogsHealthRes, error) {
+	return h.health.GetCatalogsHealth(ctx)
+}
+
+func (h *Handler) RefreshCatalog(
+	ctx context.Context,
+	p api.RefreshCatalogParams,
+) (api.RefreshCatalogRes, error) {
+	return h.health.RefreshCatalog(ctx, p.CatalogId)
+}
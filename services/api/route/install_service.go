@@ -8,13 +8,18 @@ import (
 	"github.com/onyxia-datalab/onyxia-backend/services/adapters/k8s"
 	"github.com/onyxia-datalab/onyxia-backend/services/api/controller"
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
 	"github.com/onyxia-datalab/onyxia-backend/services/ports"
 	"github.com/onyxia-datalab/onyxia-backend/services/usecase"
 )
 
-func SetupInstallController(
+// SetupServiceLifecycle builds the lifecycle use case shared by the install
+// controllers.
+func SetupServiceLifecycle(
 	app *bootstrap.Application,
-) (*controller.InstallController, error) {
+	pkgRepo ports.PackageRepository,
+	policy *usecase.CatalogPolicy,
+) (domain.ServiceLifecycle, error) {
 
 	//TODO: pass callbacks properly
 	helmRealeaseGtw, err := helm.NewReleaseGtw(app.K8sClient.Config(), ports.HelmStartCallbacks{
@@ -43,19 +48,33 @@ func SetupInstallController(
 		return nil, fmt.Errorf("helm adapter: %w", err)
 	}
 
-	pkgRepo, err := helm.NewPackageRepository(app.Env.CatalogsConfig, "")
-	if err != nil {
-		return nil, err
-	}
-
-	serviceLifecycleUc := usecase.NewServiceLifecycle(
+	return usecase.NewServiceLifecycle(
 		k8s.NewOnyxiaSecretGtw(app.K8sClient.Clientset()),
 		helmRealeaseGtw,
 		pkgRepo,
-	)
+		policy,
+	), nil
+}
 
-	ctrl := controller.NewInstallController(serviceLifecycleUc, app.UserContextReader)
+func SetupInstallController(
+	app *bootstrap.Application,
+	lifecycle domain.ServiceLifecycle,
+) *controller.InstallController {
+	return controller.NewInstallController(lifecycle, app.UserContextReader)
+}
 
-	return ctrl, nil
+func SetupPresetService(
+	app *bootstrap.Application,
+	pkgRepo ports.PackageRepository,
+	policy *usecase.CatalogPolicy,
+	lifecycle domain.ServiceLifecycle,
+) *usecase.Presets {
+	return usecase.NewPresetService(app.Env.CatalogsConfig, pkgRepo, policy, lifecycle)
+}
 
+func SetupPresetController(
+	app *bootstrap.Application,
+	presets *usecase.Presets,
+) *controller.PresetController {
+	return controller.NewPresetController(presets, app.UserContextReader)
 }
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/onyxia-datalab/onyxia-backend/internal/httputil"
+	"github.com/onyxia-datalab/onyxia-backend/services/adapters/helm"
+	"github.com/onyxia-datalab/onyxia-backend/services/adapters/k8s"
 	middleware "github.com/onyxia-datalab/onyxia-backend/services/api/middleware"
 	oas "github.com/onyxia-datalab/onyxia-backend/services/api/oas"
 
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap"
+	"github.com/onyxia-datalab/onyxia-backend/services/usecase"
 )
 
 func Setup(ctx context.Context, app *bootstrap.Application) (http.Handler, error) {
@@ -23,19 +27,48 @@ func Setup(ctx context.Context, app *bootstrap.Application) (http.Handler, error
 		return nil, fmt.Errorf("failed to initialize OIDC middleware: %w", err)
 	}
 
-	installCtrl, err := SetupInstallController(app)
+	// Catalog indexes are cached in the repository, so every controller must
+	// share the same one.
+	pkgRepo, err := helm.NewPackageRepository(
+		app.Env.CatalogsConfig,
+		"",
+		app.Env.CatalogsRefreshInterval,
+		k8s.NewCatalogSecretReader(app.K8sClient.Clientset()),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup package repository: %w", err)
+	}
+
+	policy, err := usecase.NewCatalogPolicy(app.Env.CatalogsConfig, app.UserContextReader)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup install controller: %w", err)
+		return nil, fmt.Errorf("failed to setup catalog policy: %w", err)
 	}
 
-	catalogCtrl, err := SetupCatalogController(app)
+	lifecycle, err := SetupServiceLifecycle(app, pkgRepo, policy)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup catalog controller: %w", err)
+		return nil, fmt.Errorf("failed to setup service lifecycle: %w", err)
 	}
 
-	h := NewHandler(installCtrl, catalogCtrl)
+	presets := SetupPresetService(app, pkgRepo, policy, lifecycle)
+	pkgRepo.OnIndexChange(presets.CatalogChanged)
+
+	// Catalogs load and presets are checked in the background: the server
+	// starts even when a catalog is down, and configuration problems are
+	// reported right away.
+	go func() {
+		pkgRepo.LoadCatalogs(ctx)
+		presets.CheckCatalogs(ctx)
+	}()
+
+	installCtrl := SetupInstallController(app, lifecycle)
+	presetCtrl := SetupPresetController(app, presets)
+	catalogCtrl := SetupCatalogController(app, pkgRepo, policy)
+	healthCtrl := SetupCatalogHealthController(app, pkgRepo)
+
+	h := NewHandler(installCtrl, presetCtrl, catalogCtrl, healthCtrl)
 
 	srv, err := oas.NewServer(
 		h,
@@ -46,5 +79,5 @@ func Setup(ctx context.Context, app *bootstrap.Application) (http.Handler, error
 		return nil, fmt.Errorf("failed to create api server: %w", err)
 	}
 
-	return srv, nil
+	return httputil.Languages(app.Env.DefaultLanguage)(srv), nil
 }
@@ -0,0 +1,177 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+)
+
+// Rule is a compiled Restriction.
+type Rule interface {
+	Matches(u *usercontext.User) bool
+}
+
+type attributeRule struct {
+	key string
+	re  *regexp.Regexp
+}
+
+type groupRule struct{ re *regexp.Regexp }
+type roleRule struct{ re *regexp.Regexp }
+type allRule []Rule
+type anyRule []Rule
+type notRule struct{ r Rule }
+
+func (r attributeRule) Matches(u *usercontext.User) bool {
+	switch v := u.Attributes[r.key].(type) {
+	case string:
+		return r.re.MatchString(v)
+	case []string:
+		return matchAny(r.re, v)
+	case []any:
+		for _, s := range v {
+			if str, ok := s.(string); ok && r.re.MatchString(str) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r groupRule) Matches(u *usercontext.User) bool { return matchAny(r.re, u.Groups) }
+
+func (r roleRule) Matches(u *usercontext.User) bool { return matchAny(r.re, u.Roles) }
+
+func (r allRule) Matches(u *usercontext.User) bool {
+	for _, c := range r {
+		if !c.Matches(u) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r anyRule) Matches(u *usercontext.User) bool {
+	for _, c := range r {
+		if c.Matches(u) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r notRule) Matches(u *usercontext.User) bool { return !r.r.Matches(u) }
+
+func matchAny(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompileRestrictions compiles a restriction list into a single rule granting
+// access when any restriction matches. It returns nil for an empty list. It
+// is the only validation of restrictions, shared by the configuration
+// checks and the catalog policy.
+func CompileRestrictions(restrictions []Restriction) (Rule, error) {
+	if len(restrictions) == 0 {
+		return nil, nil
+	}
+	out := make(anyRule, 0, len(restrictions))
+	for _, r := range restrictions {
+		c, err := compileRestriction(r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// compileRestriction compiles r.
+func compileRestriction(r Restriction) (Rule, error) {
+	kinds := 0
+	for _, set := range []bool{
+		r.UserAttributeKey != "" || r.Match != "",
+		r.Group != "",
+		r.Role != "",
+		r.All != nil,
+		r.Any != nil,
+		r.Not != nil,
+	} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return nil, errors.New(
+			"restriction must set exactly one of userAttribute, group, role, all, any or not",
+		)
+	}
+
+	switch {
+	case r.UserAttributeKey != "" || r.Match != "":
+		if strings.TrimSpace(r.UserAttributeKey) == "" {
+			return nil, errors.New("restriction missing userAttribute.key")
+		}
+		if r.Match == "" {
+			// Kept for compatibility: an attribute rule without a pattern never matches.
+			return anyRule{}, nil
+		}
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid restriction regex for key %q: %w", r.UserAttributeKey, err)
+		}
+		return attributeRule{key: r.UserAttributeKey, re: re}, nil
+	case r.Group != "":
+		re, err := regexp.Compile(r.Group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group restriction regex: %w", err)
+		}
+		return groupRule{re: re}, nil
+	case r.Role != "":
+		re, err := regexp.Compile(r.Role)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role restriction regex: %w", err)
+		}
+		return roleRule{re: re}, nil
+	case r.All != nil:
+		children, err := compileChildren(r.All)
+		if err != nil {
+			return nil, err
+		}
+		return allRule(children), nil
+	case r.Any != nil:
+		children, err := compileChildren(r.Any)
+		if err != nil {
+			return nil, err
+		}
+		return anyRule(children), nil
+	default:
+		c, err := compileRestriction(*r.Not)
+		if err != nil {
+			return nil, err
+		}
+		return notRule{r: c}, nil
+	}
+}
+
+func compileChildren(restrictions []Restriction) ([]Rule, error) {
+	if len(restrictions) == 0 {
+		return nil, errors.New("restriction all/any must not be empty")
+	}
+	out := make([]Rule, 0, len(restrictions))
+	for _, r := range restrictions {
+		c, err := compileRestriction(r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
@@ -1,5 +1,7 @@
 package env
 
+import "time"
+
 type Server struct {
 	Port int `mapstructure:"port"        json:"port"`
 }
@@ -16,6 +18,8 @@ type OIDC struct {
 
 type Security struct {
 	CORSAllowedOrigins []string `mapstructure:"corsAllowedOrigins" json:"corsAllowedOrigins"`
+	// AdminRoles grants access to the admin endpoints.
+	AdminRoles []string `mapstructure:"adminRoles"         json:"adminRoles"`
 }
 
 type Kubernetes struct {
@@ -23,10 +27,14 @@ type Kubernetes struct {
 	GroupNamespacePrefix string `mapstructure:"groupNamespacePrefix" json:"groupNamespacePrefix"`
 }
 type Env struct {
-	AuthenticationMode string          `mapstructure:"authenticationMode" json:"authenticationMode"`
-	Server             Server          `mapstructure:"server"             json:"server"`
-	OIDC               OIDC            `mapstructure:"oidc"               json:"oidc"`
-	Security           Security        `mapstructure:"security"           json:"security"`
-	CatalogsConfig     []CatalogConfig `mapstructure:"catalogs"           json:"catalogs"`
-	Kubernetes         Kubernetes      `mapstructure:"kubernetes"         json:"kubernetes"`
+	AuthenticationMode      string          `mapstructure:"authenticationMode"      json:"authenticationMode"`
+	Server                  Server          `mapstructure:"server"                  json:"server"`
+	OIDC                    OIDC            `mapstructure:"oidc"                    json:"oidc"`
+	Security                Security        `mapstructure:"security"                json:"security"`
+	CatalogsConfig          []CatalogConfig `mapstructure:"catalogs"                json:"catalogs"`
+	CatalogsRefreshInterval time.Duration   `mapstructure:"catalogsRefreshInterval" json:"catalogsRefreshInterval"`
+	CatalogsLoadTimeout     time.Duration   `mapstructure:"catalogsLoadTimeout"     json:"catalogsLoadTimeout"`
+	Kubernetes              Kubernetes      `mapstructure:"kubernetes"              json:"kubernetes"`
+	// DefaultLanguage is the fallback of language negotiation.
+	DefaultLanguage string `mapstructure:"defaultLanguage" json:"defaultLanguage"`
 }
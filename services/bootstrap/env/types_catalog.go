@@ -1,7 +1,9 @@
 package env
 
+import "sigs.k8s.io/yaml"
+
 type CatalogConfig struct {
-	Type CatalogType `json:"type"` // "helm" or "oci"`
+	Type CatalogType `json:"type"` // "helm", "oci" or "directory"
 
 	// Common fields
 	ID            string            `mapstructure:"id"                json:"id"`
@@ -17,10 +19,24 @@ type CatalogConfig struct {
 	Restrictions  []Restriction     `mapstructure:"restrictions"      json:"restrictions"`
 	Username      *string           `mapstructure:"username"          json:"username"`
 	Password      *string           `mapstructure:"password"          json:"password"`
+	Credentials   *CredentialsRef   `mapstructure:"credentials"       json:"credentials,omitempty"`
 	Location      string            `mapstructure:"location"          json:"location"`
+	Visible       CatalogVisibility `mapstructure:"visible"           json:"visible"`
+
+	DeprecatedCharts DeprecatedChartsMode `mapstructure:"deprecatedCharts" json:"deprecatedCharts,omitempty"`
+
+	Verify  VerifyMode `mapstructure:"verify"  json:"verify,omitempty"`
+	Keyring *string    `mapstructure:"keyring" json:"keyring,omitempty"` // path to a GPG public keyring
+
+	PackageRestrictions []PackageRestriction `mapstructure:"packageRestrictions" json:"packageRestrictions,omitempty"`
+	PackageOverrides    []PackageOverride    `mapstructure:"packageOverrides"    json:"packageOverrides,omitempty"`
+	Presets             []Preset             `mapstructure:"presets"             json:"presets,omitempty"`
 
 	MultipleServicesMode MultipleServicesMode `mapstructure:"multipleServicesMode" json:"multipleServicesMode"`
 	MaxNumberOfVersions  *int                 `mapstructure:"maxNumberOfVersions"  json:"maxNumberOfVersions,omitempty"`
+	MaxNumberOfMinors    *int                 `mapstructure:"maxNumberOfMinors"    json:"maxNumberOfMinors,omitempty"`
+	HidePrereleases      bool                 `mapstructure:"hidePrereleases"      json:"hidePrereleases"`
+	ExcludedVersions     string               `mapstructure:"excludedVersions"     json:"excludedVersions,omitempty"` // semver constraint, e.g. "< 1.0.0"
 
 	// Specific to OCI
 	Packages []OCIPackage `json:"packages,omitempty"`
@@ -31,8 +47,9 @@ type CatalogConfig struct {
 type CatalogType string
 
 const (
-	CatalogTypeHelmRepo CatalogType = "helm"
-	CatalogTypeOCI      CatalogType = "oci"
+	CatalogTypeHelmRepo  CatalogType = "helm"
+	CatalogTypeOCI       CatalogType = "oci"
+	CatalogTypeDirectory CatalogType = "directory" // local folder of charts, for air-gapped setups
 )
 
 type CatalogStatus string
@@ -45,15 +62,126 @@ const (
 type MultipleServicesMode string
 
 const (
-	MultipleServicesAll         MultipleServicesMode = "all"
-	MultipleServicesLatest      MultipleServicesMode = "latest"
-	MultipleServicesSkipPatches MultipleServicesMode = "skipPatches"
-	MultipleServicesMaxNumber   MultipleServicesMode = "maxNumber"
+	MultipleServicesAll          MultipleServicesMode = "all"
+	MultipleServicesLatest       MultipleServicesMode = "latest"
+	MultipleServicesSkipPatches  MultipleServicesMode = "skipPatches"
+	MultipleServicesMaxNumber    MultipleServicesMode = "maxNumber"
+	MultipleServicesLatestMinors MultipleServicesMode = "latestMinors"
 )
 
+// DeprecatedChartsMode tells how charts and versions flagged deprecated in the
+// repository are exposed. Unset means DeprecatedChartsShow.
+type DeprecatedChartsMode string
+
+const (
+	// Listed and installable, flagged as deprecated.
+	DeprecatedChartsShow DeprecatedChartsMode = "show"
+	// Not listed, not found and not installable.
+	DeprecatedChartsHide DeprecatedChartsMode = "hide"
+	// Not listed and not installable, but details stay available for
+	// services that are already running.
+	DeprecatedChartsExistingOnly DeprecatedChartsMode = "existingOnly"
+)
+
+// VerifyMode tells how chart provenance is enforced. Unset means charts are
+// not verified.
+type VerifyMode string
+
+const (
+	// Unverified versions are hidden and cannot be installed.
+	VerifyStrict VerifyMode = "strict"
+	// Unverified versions are flagged and installs produce a warning.
+	VerifyWarn VerifyMode = "warn"
+)
+
+// CatalogVisibility tells in which context a catalog is offered.
+// Unset fields default to visible.
+type CatalogVisibility struct {
+	User    *bool `mapstructure:"user"    json:"user,omitempty"`
+	Project *bool `mapstructure:"project" json:"project,omitempty"`
+}
+
+func (v CatalogVisibility) InUser() bool {
+	return v.User == nil || *v.User
+}
+
+func (v CatalogVisibility) InProject() bool {
+	return v.Project == nil || *v.Project
+}
+
+// Restriction is a single access rule. Exactly one kind must be set:
+// a user attribute match, a group or role regex, or an all/any/not combinator.
 type Restriction struct {
-	UserAttributeKey string `mapstructure:"userAttribute.key"     json:"userAttributeKey"`
-	Match            string `mapstructure:"userAttribute.matches" json:"match"`
+	UserAttributeKey string `mapstructure:"userAttribute.key"     json:"userAttribute.key,omitempty"`
+	Match            string `mapstructure:"userAttribute.matches" json:"userAttribute.matches,omitempty"`
+
+	Group string `mapstructure:"group" json:"group,omitempty"` // regex on User.Groups
+	Role  string `mapstructure:"role"  json:"role,omitempty"`  // regex on User.Roles
+
+	All []Restriction `mapstructure:"all" json:"all,omitempty"`
+	Any []Restriction `mapstructure:"any" json:"any,omitempty"`
+	Not *Restriction  `mapstructure:"not" json:"not,omitempty"`
+}
+
+// PackageRestriction restricts a single package inside a catalog, on top of
+// the catalog restrictions.
+type PackageRestriction struct {
+	Name         string        `mapstructure:"name"         json:"name"`
+	Restrictions []Restriction `mapstructure:"restrictions" json:"restrictions"`
+}
+
+// PackageOverride adjusts how a package of the catalog is presented, on top
+// of what the chart itself declares. Unset fields keep the upstream value.
+type PackageOverride struct {
+	Name        string `mapstructure:"name"        json:"name"`
+	Icon        string `mapstructure:"icon"        json:"icon,omitempty"`
+	Description string `mapstructure:"description" json:"description,omitempty"`
+	Category    string `mapstructure:"category"    json:"category,omitempty"`
+	// HiddenVersions are not listed, but stay installable so running
+	// services are not affected.
+	HiddenVersions []string `mapstructure:"hiddenVersions" json:"hiddenVersions,omitempty"`
+}
+
+// Preset is a one-click launcher: a package of the catalog installed with a
+// given version and values.
+type Preset struct {
+	ID          string            `mapstructure:"id"          json:"id"`
+	Name        map[string]string `mapstructure:"name"        json:"name"`
+	Description map[string]string `mapstructure:"description" json:"description,omitempty"`
+	Package     string            `mapstructure:"package"     json:"package"`
+	Version     string            `mapstructure:"version"     json:"version,omitempty"` // empty for the latest version
+	// Values is a YAML document, like a Helm values file. It is kept as text
+	// because the configuration loader lowercases map keys.
+	Values string `mapstructure:"values" json:"values,omitempty"`
+}
+
+// ParseValues returns the values of the preset.
+func (p Preset) ParseValues() (map[string]any, error) {
+	values := map[string]any{}
+	if err := yaml.Unmarshal([]byte(p.Values), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// CredentialsRef points to catalog credentials kept out of the configuration.
+// Exactly one source must be set. Credentials are read again periodically, so
+// rotating them does not require a restart.
+type CredentialsRef struct {
+	// Secret holding either username/password keys or a .dockerconfigjson.
+	Secret *SecretRef `mapstructure:"secret" json:"secret,omitempty"`
+
+	// Mounted files holding the username and the password.
+	UsernameFile string `mapstructure:"usernameFile" json:"usernameFile,omitempty"`
+	PasswordFile string `mapstructure:"passwordFile" json:"passwordFile,omitempty"`
+
+	// Mounted docker config.json; the entry matching the catalog host is used.
+	DockerConfigFile string `mapstructure:"dockerConfigFile" json:"dockerConfigFile,omitempty"`
+}
+
+type SecretRef struct {
+	Namespace string `mapstructure:"namespace" json:"namespace"`
+	Name      string `mapstructure:"name"      json:"name"`
 }
 
 type OCIPackage struct {
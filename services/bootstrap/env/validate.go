@@ -3,8 +3,11 @@ package env
 import (
 	"errors"
 	"fmt"
-	"regexp"
+	"net/url"
+	"slices"
 	"strings"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 func ValidateCatalogsConfig(catalogs []CatalogConfig) error {
@@ -36,13 +39,18 @@ func ValidateCatalogConfig(c CatalogConfig) error {
 		if err := validateOCI(c); err != nil {
 			return err
 		}
+	case CatalogTypeDirectory:
+		if err := validateDirectory(c); err != nil {
+			return err
+		}
 
 	default:
 		return fmt.Errorf(
-			"catalog: invalid type %q (expected %q or %q)",
+			"catalog: invalid type %q (expected %q, %q or %q)",
 			c.Type,
 			CatalogTypeHelmRepo,
 			CatalogTypeOCI,
+			CatalogTypeDirectory,
 		)
 	}
 	return nil
@@ -72,6 +80,13 @@ func validateCommon(cc CatalogConfig) error {
 				cc.MultipleServicesMode,
 			)
 		}
+		if cc.MaxNumberOfMinors != nil {
+			return fmt.Errorf(
+				"catalog %q: maxNumberOfMinors must not be set when multipleServicesMode=%q",
+				cc.ID,
+				cc.MultipleServicesMode,
+			)
+		}
 	case MultipleServicesMaxNumber:
 		if cc.MaxNumberOfVersions == nil || *cc.MaxNumberOfVersions <= 0 {
 			return fmt.Errorf(
@@ -80,6 +95,14 @@ func validateCommon(cc CatalogConfig) error {
 				cc.MultipleServicesMode,
 			)
 		}
+	case MultipleServicesLatestMinors:
+		if cc.MaxNumberOfMinors == nil || *cc.MaxNumberOfMinors <= 0 {
+			return fmt.Errorf(
+				"catalog %q: maxNumberOfMinors must be > 0 when multipleServicesMode=%q",
+				cc.ID,
+				cc.MultipleServicesMode,
+			)
+		}
 	default:
 		return fmt.Errorf(
 			"catalog %q: invalid multipleServicesMode %q",
@@ -88,22 +111,186 @@ func validateCommon(cc CatalogConfig) error {
 		)
 	}
 
+	switch cc.DeprecatedCharts {
+	case "", DeprecatedChartsShow, DeprecatedChartsHide, DeprecatedChartsExistingOnly:
+		// ok
+	default:
+		return fmt.Errorf(
+			"catalog %q: invalid deprecatedCharts %q (expected %q, %q or %q)",
+			cc.ID,
+			cc.DeprecatedCharts,
+			DeprecatedChartsShow,
+			DeprecatedChartsHide,
+			DeprecatedChartsExistingOnly,
+		)
+	}
+
+	switch cc.Verify {
+	case "":
+		// ok
+	case VerifyStrict, VerifyWarn:
+		if cc.Keyring == nil || *cc.Keyring == "" {
+			return fmt.Errorf("catalog %q: keyring is required when verify=%q", cc.ID, cc.Verify)
+		}
+	default:
+		return fmt.Errorf(
+			"catalog %q: invalid verify %q (expected %q or %q)",
+			cc.ID,
+			cc.Verify,
+			VerifyStrict,
+			VerifyWarn,
+		)
+	}
+
+	if cc.Credentials != nil {
+		if err := validateCredentials(cc); err != nil {
+			return fmt.Errorf("catalog %q: %w", cc.ID, err)
+		}
+	}
+
+	if cc.ExcludedVersions != "" {
+		if _, err := semver.NewConstraint(cc.ExcludedVersions); err != nil {
+			return fmt.Errorf("catalog %q: invalid excludedVersions %q: %w", cc.ID, cc.ExcludedVersions, err)
+		}
+	}
+
 	if cc.ID == "" {
 		return fmt.Errorf("catalog: id is required")
 	}
 	if len(cc.Name) == 0 {
 		return fmt.Errorf("catalog %q: name is required", cc.ID)
 	}
+	if !cc.Visible.InUser() && !cc.Visible.InProject() {
+		return fmt.Errorf("catalog %q: must be visible in user or project context", cc.ID)
+	}
+
+	if _, err := CompileRestrictions(cc.Restrictions); err != nil {
+		return fmt.Errorf("catalog %q: %w", cc.ID, err)
+	}
+	for _, pr := range cc.PackageRestrictions {
+		if pr.Name == "" {
+			return fmt.Errorf("catalog %q: packageRestrictions entry missing name", cc.ID)
+		}
+		if len(pr.Restrictions) == 0 {
+			return fmt.Errorf("catalog %q: packageRestrictions %q has no restrictions", cc.ID, pr.Name)
+		}
+		if _, err := CompileRestrictions(pr.Restrictions); err != nil {
+			return fmt.Errorf("catalog %q: package %q: %w", cc.ID, pr.Name, err)
+		}
+	}
+
+	if err := validatePackageOverrides(cc); err != nil {
+		return fmt.Errorf("catalog %q: %w", cc.ID, err)
+	}
+
+	if err := validatePresets(cc); err != nil {
+		return fmt.Errorf("catalog %q: %w", cc.ID, err)
+	}
+
+	return nil
+}
+
+// validatePackageOverrides checks overrides on their own. Whether they name
+// existing packages can only be checked here for OCI catalogs, which list
+// their packages; see validateOCI.
+func validatePackageOverrides(cc CatalogConfig) error {
+	seen := make(map[string]struct{}, len(cc.PackageOverrides))
+	for _, o := range cc.PackageOverrides {
+		if o.Name == "" {
+			return errors.New("packageOverrides entry missing name")
+		}
+		if _, dup := seen[o.Name]; dup {
+			return fmt.Errorf("packageOverrides: duplicate package %q", o.Name)
+		}
+		seen[o.Name] = struct{}{}
+
+		if o.Icon != "" {
+			u, err := url.Parse(o.Icon)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("packageOverrides %q: icon must be an http(s) URL", o.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePresets checks presets on their own. Their values are checked
+// against the chart schema once the catalog is loaded.
+func validatePresets(cc CatalogConfig) error {
+	seen := make(map[string]struct{}, len(cc.Presets))
+	for _, p := range cc.Presets {
+		if p.ID == "" || strings.ContainsAny(p.ID, "/ ") {
+			return fmt.Errorf("presets: invalid id %q", p.ID)
+		}
+		if _, dup := seen[p.ID]; dup {
+			return fmt.Errorf("presets: duplicate id %q", p.ID)
+		}
+		seen[p.ID] = struct{}{}
+
+		if len(p.Name) == 0 {
+			return fmt.Errorf("preset %q: name is required", p.ID)
+		}
+		if p.Package == "" {
+			return fmt.Errorf("preset %q: package is required", p.ID)
+		}
+		if slices.Contains(cc.Excluded, p.Package) {
+			return fmt.Errorf("preset %q: package %q is excluded", p.ID, p.Package)
+		}
+		if p.Version != "" {
+			if _, err := semver.NewVersion(p.Version); err != nil {
+				return fmt.Errorf("preset %q: invalid version %q: %w", p.ID, p.Version, err)
+			}
+		}
+		if _, err := p.ParseValues(); err != nil {
+			return fmt.Errorf("preset %q: invalid values: %w", p.ID, err)
+		}
 
-	for _, r := range cc.Restrictions {
-		if strings.TrimSpace(r.UserAttributeKey) == "" {
-			return fmt.Errorf("catalog %q: restriction missing userAttribute.key", cc.ID)
+		if cc.Type == CatalogTypeOCI {
+			i := slices.IndexFunc(cc.Packages, func(o OCIPackage) bool { return o.Name == p.Package })
+			if i < 0 {
+				return fmt.Errorf("preset %q: unknown package %q", p.ID, p.Package)
+			}
+			versions := cc.Packages[i].Versions
+			if p.Version != "" && len(versions) > 0 && !slices.Contains(versions, p.Version) {
+				return fmt.Errorf("preset %q: unknown version %q of package %q", p.ID, p.Version, p.Package)
+			}
 		}
-		if _, err := regexp.Compile(r.Match); err != nil {
-			return fmt.Errorf("catalog %q: invalid restriction regex for key %q: %w", cc.ID, r.UserAttributeKey, err)
+	}
+	return nil
+}
+
+func validateCredentials(cc CatalogConfig) error {
+	if cc.Username != nil || cc.Password != nil {
+		return errors.New("credentials must not be combined with username/password")
+	}
+
+	ref := cc.Credentials
+	sources := 0
+	for _, set := range []bool{
+		ref.Secret != nil,
+		ref.UsernameFile != "" || ref.PasswordFile != "",
+		ref.DockerConfigFile != "",
+	} {
+		if set {
+			sources++
 		}
 	}
+	if sources != 1 {
+		return errors.New(
+			"credentials must set exactly one of secret, usernameFile/passwordFile or dockerConfigFile",
+		)
+	}
 
+	switch {
+	case ref.Secret != nil:
+		if ref.Secret.Namespace == "" || ref.Secret.Name == "" {
+			return errors.New("credentials secret requires a namespace and a name")
+		}
+	case ref.UsernameFile != "" || ref.PasswordFile != "":
+		if ref.UsernameFile == "" || ref.PasswordFile == "" {
+			return errors.New("credentials usernameFile and passwordFile must be set together")
+		}
+	}
 	return nil
 }
 
@@ -115,6 +302,26 @@ func validateOCI(o CatalogConfig) error {
 		if p.Name == "" {
 			return fmt.Errorf("catalog %q: oci.package name is required", o.ID)
 		}
+		for _, v := range p.Versions {
+			if _, err := semver.NewVersion(v); err != nil {
+				return fmt.Errorf("catalog %q: oci.package %q: invalid version %q: %w", o.ID, p.Name, v, err)
+			}
+		}
+	}
+	for _, ov := range o.PackageOverrides {
+		if !slices.ContainsFunc(o.Packages, func(p OCIPackage) bool { return p.Name == ov.Name }) {
+			return fmt.Errorf("catalog %q: packageOverrides names unknown package %q", o.ID, ov.Name)
+		}
+	}
+	return nil
+}
+
+func validateDirectory(d CatalogConfig) error {
+	if d.Location == "" {
+		return fmt.Errorf("catalog %q: directory catalog requires a location", d.ID)
+	}
+	if d.Packages != nil {
+		return fmt.Errorf("catalog %q: directory catalog should not have packages", d.ID)
 	}
 	return nil
 }
@@ -0,0 +1,65 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validCatalog() CatalogConfig {
+	return CatalogConfig{
+		ID:                   "ide",
+		Type:                 CatalogTypeHelmRepo,
+		Location:             "https://charts.example.org",
+		Name:                 map[string]string{"en": "IDE"},
+		Status:               StatusProd,
+		MultipleServicesMode: MultipleServicesAll,
+	}
+}
+
+// ✅ Well-formed restrictions pass.
+func TestValidateCatalogsConfig_Restrictions(t *testing.T) {
+	c := validCatalog()
+	c.Restrictions = []Restriction{{UserAttributeKey: "groups", Match: "^dev$"}}
+	c.PackageRestrictions = []PackageRestriction{
+		{Name: "vscode", Restrictions: []Restriction{{Not: &Restriction{Role: "^guest$"}}}},
+	}
+
+	assert.NoError(t, ValidateCatalogsConfig([]CatalogConfig{c}))
+}
+
+// ❌ Malformed restrictions are rejected by the policy compiler.
+func TestValidateCatalogsConfig_InvalidRestrictions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		restrictions []Restriction
+		packages     []PackageRestriction
+		err          string
+	}{
+		"no kind": {
+			restrictions: []Restriction{{}},
+			err:          "exactly one of",
+		},
+		"bad regex": {
+			restrictions: []Restriction{{Group: "("}},
+			err:          "invalid group restriction regex",
+		},
+		"empty any": {
+			restrictions: []Restriction{{Any: []Restriction{}}},
+			err:          "must not be empty",
+		},
+		"package rule": {
+			packages: []PackageRestriction{
+				{Name: "vscode", Restrictions: []Restriction{{Match: "^dev$"}}},
+			},
+			err: `package "vscode": restriction missing userAttribute.key`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := validCatalog()
+			c.Restrictions = tc.restrictions
+			c.PackageRestrictions = tc.packages
+
+			assert.ErrorContains(t, ValidateCatalogsConfig([]CatalogConfig{c}), tc.err)
+		})
+	}
+}
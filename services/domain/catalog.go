@@ -12,7 +12,27 @@ type Catalog struct {
 	Description         tools.LocalizedString
 	Status              CatalogStatus
 	HighlightedPackages []string
+	Visible             CatalogVisibility
 	Packages            []Package
+	// Error is set when the packages of the catalog could not be loaded.
+	Error CatalogError
+}
+
+// CatalogError tells users why a catalog could not be loaded. Catalogs are
+// listed anonymously, so the underlying error, which may name internal hosts,
+// is only logged and reported by the catalog health.
+type CatalogError string
+
+const (
+	CatalogErrorTimeout     CatalogError = "timeout"
+	CatalogErrorUnavailable CatalogError = "unavailable"
+)
+
+// CatalogVisibility tells whether a catalog should be offered in user and/or
+// project context.
+type CatalogVisibility struct {
+	User    bool
+	Project bool
 }
 
 type CatalogStatus string
@@ -23,13 +43,48 @@ const (
 )
 
 type CatalogService interface {
-	ListPublicCatalogs(ctx context.Context) ([]Catalog, error)
-	ListUserCatalogs(ctx context.Context) ([]Catalog, error)
+	// ListPublicCatalogs and ListUserCatalogs also return the ETag of the
+	// catalogs, computed from the revisions their packages were loaded at. It
+	// is empty when a revision is unavailable.
+	ListPublicCatalogs(ctx context.Context) ([]Catalog, string, error)
+	// ListUserCatalogs lists the catalogs the user may access that are visible
+	// in project context when project is set, or in user context otherwise.
+	ListUserCatalogs(ctx context.Context, project string) ([]Catalog, string, error)
+	// PublicCatalogsETag and UserCatalogsETag identify what ListPublicCatalogs
+	// and ListUserCatalogs would return, without building the catalogs.
+	PublicCatalogsETag(ctx context.Context) (string, error)
+	UserCatalogsETag(ctx context.Context, project string) (string, error)
 	GetPackage(ctx context.Context, catalogID string, packageName string) (*PackageRef, error)
+	// SearchPackages returns at most limit packages matching query across the
+	// catalogs listed by ListUserCatalogs for project, most relevant first.
+	SearchPackages(
+		ctx context.Context,
+		project string,
+		query string,
+		limit int,
+	) ([]PackageSearchResult, error)
 	GetPackageSchema(
 		ctx context.Context,
 		catalogID string,
 		packageName string,
 		version string,
 	) ([]byte, error)
+	// GetPackageReadme returns the README of a package version, as markdown.
+	GetPackageReadme(
+		ctx context.Context,
+		catalogID string,
+		packageName string,
+		version string,
+	) (string, error)
+	// GetPackageValues returns the default values of a package version, as
+	// JSON.
+	GetPackageValues(
+		ctx context.Context,
+		catalogID string,
+		packageName string,
+		version string,
+	) ([]byte, error)
+	// GetPackageIcon returns the icon of a package the user may access, or a
+	// default icon when the package has none or it cannot be fetched.
+	GetPackageIcon(ctx context.Context, catalogID string, packageName string) (Icon, error)
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CatalogHealth is the refresh status of a catalog index. Timestamps are zero
+// when the event never happened.
+type CatalogHealth struct {
+	CatalogID      string
+	Type           string
+	Packages       int
+	LastRefresh    time.Time
+	LastError      string
+	LastErrorAt    time.Time
+	RefreshLatency time.Duration // duration of the last refresh attempt
+}
+
+// CatalogHealthService lets operators diagnose catalog refresh issues.
+type CatalogHealthService interface {
+	ListCatalogHealth(ctx context.Context) ([]CatalogHealth, error)
+	// RefreshCatalog refreshes the index of a catalog now, bypassing the cache.
+	RefreshCatalog(ctx context.Context, catalogID string) (CatalogHealth, error)
+}
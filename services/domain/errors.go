@@ -7,4 +7,7 @@ var (
 	ErrForbidden     = errors.New("forbidden")      // business rule denial
 	ErrAlreadyExists = errors.New("already exists") // idempotency/conflict
 	ErrNotFound      = errors.New("not found")
+	// ErrNotInstallable refuses installing a package version that exists,
+	// such as a deprecated one that is only kept for running services.
+	ErrNotInstallable = errors.New("not installable")
 )
@@ -4,27 +4,104 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type Package struct {
 	CatalogID   string
 	Name        string
 	Description string
+	Keywords    []string
 	HomeUrl     url.URL
 	IconUrl     url.URL
+	Deprecated  bool
+	// Category is set by the catalog configuration, charts do not have one.
+	Category string
+	// Restricted is set for packages only some users may access.
+	Restricted bool
 }
 
+// PackageRef is a package with the metadata of its latest visible version and
+// the list of visible versions, newest first.
 type PackageRef struct {
 	Package
-	Versions []string
+	AppVersion  string
+	Maintainers []Maintainer
+	Sources     []string
+	Versions    []VersionInfo
+}
+
+// VersionNames returns the version strings of r, in the same order.
+func (r PackageRef) VersionNames() []string {
+	out := make([]string, 0, len(r.Versions))
+	for _, v := range r.Versions {
+		out = append(out, v.Version)
+	}
+	return out
+}
+
+type Maintainer struct {
+	Name  string
+	Email string
+	URL   string
+}
+
+// VersionInfo describes a single published version of a package.
+// Created and Digest are only known for Helm repositories.
+type VersionInfo struct {
+	Version    string
+	AppVersion string
+	Created    time.Time
+	Digest     string
+	Deprecated bool
+	// Unverified is set when the catalog checks provenance and this version
+	// failed the check.
+	Unverified bool
+}
+
+// Icon is an image served on behalf of a package.
+type Icon struct {
+	ContentType string
+	Data        []byte
+	// Restricted is set for icons of packages only some users may access,
+	// which shared caches must not keep.
+	Restricted bool
+}
+
+// PackageSearchResult is a package matching a search query, with its relevance.
+type PackageSearchResult struct {
+	Package
+	Highlighted bool
+	Score       int
 }
 
 type PackageVersion struct {
 	Package
 	Version string
 	RepoURL string
+	// LocalPath is set for charts read from a local directory and takes
+	// precedence over RepoURL.
+	LocalPath string
+	// Keyring, when set, is the keyring the chart provenance must be checked
+	// against when it is installed.
+	Keyring string
+	// Unverified is set when the chart failed a non-blocking provenance check.
+	Unverified bool
+	// Credentials authenticate against the repository the chart is pulled
+	// from. They must never be logged.
+	Credentials Credentials
+}
+
+// Credentials are the basic auth credentials of a catalog repository or
+// registry.
+type Credentials struct {
+	Username string
+	Password string
 }
 
 func (r PackageVersion) ChartRef() string {
+	if r.LocalPath != "" {
+		return r.LocalPath
+	}
 	return fmt.Sprintf("%s/%s", strings.TrimSuffix(r.RepoURL, "/"), r.Name)
 }
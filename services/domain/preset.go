@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
+)
+
+// Preset is a one-click launcher defined by a catalog: a package installed
+// with a given version and values.
+type Preset struct {
+	ID          string
+	CatalogID   string
+	Name        tools.LocalizedString
+	Description tools.LocalizedString
+	PackageName string
+	// Version is empty when the preset follows the latest version.
+	Version string
+	Values  map[string]any
+}
+
+type PresetService interface {
+	// ListPresets lists the valid presets of the catalogs the user may access
+	// that are visible in project context when project is set, or in user
+	// context otherwise.
+	ListPresets(ctx context.Context, project string) ([]Preset, error)
+	// InstallPreset starts req from a preset. The values of req are merged
+	// over the preset values; its catalog, package and version are ignored.
+	InstallPreset(ctx context.Context, catalogID, presetID string, req StartRequest) (StartResponse, error)
+}
@@ -19,6 +19,9 @@ type StartRequest struct {
 }
 
 type StartResponse struct {
+	// Warnings are non-blocking remarks about the install, such as the use
+	// of a deprecated package.
+	Warnings []string
 }
 
 type ServiceLifecycle interface {
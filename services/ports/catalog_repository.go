@@ -10,5 +10,30 @@ type PackageRepository interface {
 	ListPackages(ctx context.Context, catalogID string) ([]domain.Package, error)
 	GetPackage(ctx context.Context, catalogID string, name string) (*domain.PackageRef, error)
 	GetPackageSchema(ctx context.Context, catalogID string, packageName string, version string) ([]byte, error)
+	// GetPackageReadme returns the README of a chart version, as markdown.
+	GetPackageReadme(ctx context.Context, catalogID string, packageName string, version string) (string, error)
+	// GetPackageValues returns the default values of a chart version, as JSON.
+	GetPackageValues(ctx context.Context, catalogID string, packageName string, version string) ([]byte, error)
 	ResolvePackage(ctx context.Context, catalogID string, packageName string, version string) (domain.PackageVersion, error)
+	// ValidateValues checks values against the values schema of a chart
+	// version, failing with domain.ErrInvalidInput when they do not match.
+	ValidateValues(ctx context.Context, catalogID string, packageName string, version string, values map[string]any) error
+	// CatalogRevision identifies the content of a catalog: it changes whenever
+	// ListPackages would return different packages.
+	CatalogRevision(ctx context.Context, catalogID string) (string, error)
+	// GetPackageIcon fetches the upstream icon of a package, whose URL is taken
+	// from the catalog listing. A package without icon yields a zero Icon.
+	GetPackageIcon(ctx context.Context, catalogID string, packageName string) (domain.Icon, error)
+}
+
+// CatalogSecretReader reads the Kubernetes Secrets catalog credentials are
+// kept in.
+type CatalogSecretReader interface {
+	ReadSecret(ctx context.Context, namespace, name string) (map[string][]byte, error)
+}
+
+// CatalogMonitor reports and refreshes the indexes behind catalogs.
+type CatalogMonitor interface {
+	CatalogHealth(ctx context.Context, catalogID string) (domain.CatalogHealth, error)
+	RefreshCatalog(ctx context.Context, catalogID string) (domain.CatalogHealth, error)
 }
@@ -2,94 +2,65 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
+	"log/slog"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
-	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
 	"github.com/onyxia-datalab/onyxia-backend/services/domain"
 	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+	"golang.org/x/sync/singleflight"
 )
 
 // Catalog implements domain.CatalogService
 type Catalog struct {
 	envCatalogConfig []env.CatalogConfig
 	pkgRepo          ports.PackageRepository
-	userReader       usercontext.Reader
+	policy           *CatalogPolicy
+	loadTimeout      time.Duration
+
+	// loads runs one load per catalog at a time, shared by the requests
+	// waiting for it.
+	loads singleflight.Group
 }
 
 var _ domain.CatalogService = (*Catalog)(nil)
 
-// Constructor
+// Constructor. loadTimeout bounds how long listing the packages of a single
+// catalog may take; zero means no limit.
 func NewCatalogService(
 	envCatalogConfig []env.CatalogConfig,
 	pkgRepo ports.PackageRepository,
-	userReader usercontext.Reader,
+	policy *CatalogPolicy,
+	loadTimeout time.Duration,
 ) *Catalog {
 	return &Catalog{
 		envCatalogConfig: envCatalogConfig,
 		pkgRepo:          pkgRepo,
-		userReader:       userReader,
+		policy:           policy,
+		loadTimeout:      loadTimeout,
 	}
 }
 
-func (uc *Catalog) ListPublicCatalogs(ctx context.Context) ([]domain.Catalog, error) {
-	return uc.buildCatalogs(ctx, func(c env.CatalogConfig) bool {
-		return len(c.Restrictions) == 0
-	})
+func (uc *Catalog) ListPublicCatalogs(ctx context.Context) ([]domain.Catalog, string, error) {
+	return uc.buildCatalogs(ctx, uc.policy.IsPublic)
 }
 
 func (uc *Catalog) ListUserCatalogs(
 	ctx context.Context,
-) ([]domain.Catalog, error) {
-	return uc.buildCatalogs(ctx, func(c env.CatalogConfig) bool {
-		if len(c.Restrictions) == 0 {
-			return true
-		}
-
-		attrs, ok := uc.userReader.GetAttributes(ctx)
-		if !ok {
-			return false
-		}
-
-		for _, r := range c.Restrictions {
-			if r.UserAttributeKey == "" || r.Match == "" {
-				continue
-			}
-
-			val, ok := attrs[r.UserAttributeKey]
-			if !ok {
-				continue
-			}
-
-			re, err := regexp.Compile(r.Match)
-			if err != nil {
-				continue
-			}
-
-			switch v := val.(type) {
-			case string:
-				if re.MatchString(v) {
-					return true
-				}
-			case []string:
-				for _, s := range v {
-					if re.MatchString(s) {
-						return true
-					}
-				}
-			case []any:
-				for _, s := range v {
-					if str, ok := s.(string); ok && re.MatchString(str) {
-						return true
-					}
-				}
-			}
-		}
+	project string,
+) ([]domain.Catalog, string, error) {
+	return uc.buildCatalogs(ctx, uc.userCatalogs(ctx, project))
+}
 
-		return false
-	})
+// userCatalogs selects the catalogs the user in ctx may access that are
+// visible in project context when project is set, or in user context otherwise.
+func (uc *Catalog) userCatalogs(ctx context.Context, project string) func(env.CatalogConfig) bool {
+	return func(c env.CatalogConfig) bool {
+		return uc.policy.Offers(ctx, c, project)
+	}
 }
 
 func (uc *Catalog) GetPackageSchema(
@@ -98,19 +69,34 @@ func (uc *Catalog) GetPackageSchema(
 	packageName string,
 	version string,
 ) ([]byte, error) {
-	if _, err := uc.findCatalog(catalogID); err != nil {
+	if _, err := uc.policy.AuthorizePackage(ctx, catalogID, packageName); err != nil {
 		return nil, err
 	}
 	return uc.pkgRepo.GetPackageSchema(ctx, catalogID, packageName, version)
 }
 
-func (uc *Catalog) findCatalog(catalogID string) (*env.CatalogConfig, error) {
-	for i := range uc.envCatalogConfig {
-		if uc.envCatalogConfig[i].ID == catalogID {
-			return &uc.envCatalogConfig[i], nil
-		}
+func (uc *Catalog) GetPackageReadme(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) (string, error) {
+	if _, err := uc.policy.AuthorizePackage(ctx, catalogID, packageName); err != nil {
+		return "", err
 	}
-	return nil, fmt.Errorf("catalog %q: %w", catalogID, domain.ErrNotFound)
+	return uc.pkgRepo.GetPackageReadme(ctx, catalogID, packageName, version)
+}
+
+func (uc *Catalog) GetPackageValues(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) ([]byte, error) {
+	if _, err := uc.policy.AuthorizePackage(ctx, catalogID, packageName); err != nil {
+		return nil, err
+	}
+	return uc.pkgRepo.GetPackageValues(ctx, catalogID, packageName, version)
 }
 
 func (uc *Catalog) GetPackage(
@@ -118,7 +104,7 @@ func (uc *Catalog) GetPackage(
 	catalogID string,
 	packageName string,
 ) (*domain.PackageRef, error) {
-	if _, err := uc.findCatalog(catalogID); err != nil {
+	if _, err := uc.policy.AuthorizePackage(ctx, catalogID, packageName); err != nil {
 		return nil, err
 	}
 
@@ -135,24 +121,41 @@ func (uc *Catalog) GetPackage(
 		)
 	}
 
-	return pkg, nil
+	ref := *pkg
+	ref.Restricted = !uc.policy.IsPublicPackage(catalogID, packageName)
+	return &ref, nil
 }
 
+// buildCatalogs lists the catalogs selected by include, with their ETag.
 func (uc *Catalog) buildCatalogs(
 	ctx context.Context,
 	include func(env.CatalogConfig) bool,
-) ([]domain.Catalog, error) {
-	out := make([]domain.Catalog, 0)
-
+) ([]domain.Catalog, string, error) {
+	included := make([]env.CatalogConfig, 0, len(uc.envCatalogConfig))
 	for _, cfg := range uc.envCatalogConfig {
-		if !include(cfg) {
-			continue
+		if include(cfg) {
+			included = append(included, cfg)
 		}
+	}
 
-		pkgs, err := uc.pkgRepo.ListPackages(ctx, cfg.ID)
-		if err != nil {
-			return nil, fmt.Errorf("catalog %q: list packages: %w", cfg.ID, err)
+	out := make([]domain.Catalog, 0, len(included))
+	loadedCatalogs := uc.loadPackages(ctx, included)
+	revisions := make([]catalogRevision, 0, len(loadedCatalogs))
+
+	// A catalog that fails to load is reported with its error rather than
+	// failing the whole list.
+	for _, loaded := range loadedCatalogs {
+		revisions = append(revisions, loaded.revision)
+		cfg := loaded.cfg
+
+		var loadErr domain.CatalogError
+		switch {
+		case errors.Is(loaded.err, context.DeadlineExceeded):
+			loadErr = domain.CatalogErrorTimeout
+		case loaded.err != nil:
+			loadErr = domain.CatalogErrorUnavailable
 		}
+		pkgs := uc.policy.FilterPackages(ctx, cfg.ID, loaded.pkgs)
 
 		name, err := tools.NewLocalizedString(cfg.Name)
 		if err != nil {
@@ -170,9 +173,18 @@ func (uc *Catalog) buildCatalogs(
 			Description:         desc,
 			Status:              domain.CatalogStatus(cfg.Status),
 			HighlightedPackages: append([]string(nil), cfg.Highlighted...),
-			Packages:            pkgs,
+			Visible: domain.CatalogVisibility{
+				User:    cfg.Visible.InUser(),
+				Project: cfg.Visible.InProject(),
+			},
+			Packages: pkgs,
+			Error:    loadErr,
 		})
 	}
 
-	return out, nil
+	etag, err := uc.revisionsETag(ctx, revisions)
+	if err != nil {
+		slog.DebugContext(ctx, "Catalogs served without ETag", slog.Any("error", err))
+	}
+	return out, etag, nil
 }
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+)
+
+func (uc *Catalog) PublicCatalogsETag(ctx context.Context) (string, error) {
+	return uc.catalogsETag(ctx, uc.policy.IsPublic)
+}
+
+func (uc *Catalog) UserCatalogsETag(ctx context.Context, project string) (string, error) {
+	return uc.catalogsETag(ctx, uc.userCatalogs(ctx, project))
+}
+
+// catalogsETag fingerprints what buildCatalogs would return for include,
+// without listing packages. It fails when a revision is unavailable, in which
+// case the response must not be cached.
+func (uc *Catalog) catalogsETag(
+	ctx context.Context,
+	include func(env.CatalogConfig) bool,
+) (string, error) {
+	included := make([]env.CatalogConfig, 0, len(uc.envCatalogConfig))
+	for _, cfg := range uc.envCatalogConfig {
+		if include(cfg) {
+			included = append(included, cfg)
+		}
+	}
+	return uc.revisionsETag(ctx, uc.loadRevisions(ctx, included))
+}
+
+// revisionsETag fingerprints catalogs at revisions: the configuration and
+// index revision of each catalog, and the packages the user may not see in
+// it. It fails when a revision is unavailable.
+func (uc *Catalog) revisionsETag(ctx context.Context, revisions []catalogRevision) (string, error) {
+	sum := sha256.New()
+	for _, r := range revisions {
+		if r.err != nil {
+			return "", fmt.Errorf("catalog %q: %w", r.cfg.ID, r.err)
+		}
+		if err := writeConfig(sum, r.cfg); err != nil {
+			return "", fmt.Errorf("catalog %q: %w", r.cfg.ID, err)
+		}
+		fmt.Fprintf(sum, "%s\x00", r.revision)
+		for _, name := range uc.policy.DeniedPackages(ctx, r.cfg.ID) {
+			fmt.Fprintf(sum, "%s\x00", name)
+		}
+		sum.Write([]byte{'\n'})
+	}
+	return `"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`, nil
+}
+
+// writeConfig adds the catalog configuration to sum, so a changed
+// configuration yields a new ETag even when indexes did not change.
+func writeConfig(sum hash.Hash, cfg env.CatalogConfig) error {
+	// Credentials are left out of the hash on purpose.
+	cfg.Username, cfg.Password, cfg.Credentials = nil, nil, nil
+	return json.NewEncoder(sum).Encode(cfg)
+}
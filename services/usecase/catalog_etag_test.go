@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ✅ The ETag is stable and changes with the index revision.
+func TestPublicCatalogsETag_FollowsRevision(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "ide"}})
+
+	revision := repo.On("CatalogRevision", mock.Anything, "ide").Return("rev-1", nil)
+
+	first, err := uc.PublicCatalogsETag(ctx)
+	require.NoError(t, err)
+	again, err := uc.PublicCatalogsETag(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Regexp(t, `^"[0-9a-f]+"$`, first)
+
+	revision.Unset()
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("rev-2", nil)
+
+	changed, err := uc.PublicCatalogsETag(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
+
+// ✅ Users seeing different packages get different ETags.
+func TestUserCatalogsETag_RestrictionFingerprint(t *testing.T) {
+	cfgs := []env.CatalogConfig{{
+		ID: "ide",
+		PackageRestrictions: []env.PackageRestriction{{
+			Name:         "admin-tools",
+			Restrictions: []env.Restriction{{Group: "^sspcloud-admin$"}},
+		}},
+	}}
+	admin := &usercontext.User{Username: "admin", Groups: []string{"sspcloud-admin"}}
+	dev := &usercontext.User{Username: "dev", Groups: []string{"sspcloud-dev"}}
+	otherDev := &usercontext.User{Username: "other", Groups: []string{"sspcloud-dev"}}
+
+	etagFor := func(user *usercontext.User) string {
+		uc, ctx, repo := setupCatalogUsecase(t, user, cfgs)
+		repo.On("CatalogRevision", mock.Anything, "ide").Return("rev-1", nil)
+		etag, err := uc.UserCatalogsETag(ctx, "")
+		require.NoError(t, err)
+		return etag
+	}
+
+	assert.NotEqual(t, etagFor(admin), etagFor(dev))
+	assert.Equal(t, etagFor(dev), etagFor(otherDev))
+}
+
+// ❌ Without a revision, no ETag can be computed.
+func TestPublicCatalogsETag_RevisionError(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "ide"}})
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("", errors.New("repository down"))
+
+	etag, err := uc.PublicCatalogsETag(ctx)
+
+	assert.ErrorContains(t, err, "repository down")
+	assert.Empty(t, etag)
+}
+
+// ✅ The list comes with the ETag of the revisions its packages were loaded at.
+func TestListPublicCatalogs_ETag(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "ide"}})
+	repo.On("ListPackages", mock.Anything, "ide").Return([]domain.Package{{Name: "jupyter"}}, nil)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("rev-1", nil)
+
+	_, etag, err := uc.ListPublicCatalogs(ctx)
+	require.NoError(t, err)
+	expected, err := uc.PublicCatalogsETag(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected, etag)
+
+	// A revision read after listing, the index having been refreshed
+	// meanwhile, is the one the ETag is built from.
+	repo.ExpectedCalls = nil
+	repo.On("ListPackages", mock.Anything, "ide").Return([]domain.Package{{Name: "vscode"}}, nil)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("rev-2", nil)
+
+	_, refreshed, err := uc.ListPublicCatalogs(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, etag, refreshed)
+}
+
+// ✅ Revisions are read in parallel, each bounded by the load timeout.
+func TestPublicCatalogsETag_RevisionsInParallel(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	uc, ctx, repo := setupCatalogUsecase(t, nil, cfgs)
+	uc.loadTimeout = 50 * time.Millisecond
+	repo.On("CatalogRevision", mock.Anything, mock.Anything).After(time.Second).Return("late", nil)
+
+	start := time.Now()
+	_, err := uc.PublicCatalogsETag(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+)
+
+// CatalogHealth implements domain.CatalogHealthService. It is restricted to
+// users holding one of adminRoles.
+type CatalogHealth struct {
+	envCatalogConfig []env.CatalogConfig
+	monitor          ports.CatalogMonitor
+	userReader       usercontext.Reader
+	adminRoles       []string
+}
+
+var _ domain.CatalogHealthService = (*CatalogHealth)(nil)
+
+func NewCatalogHealthService(
+	envCatalogConfig []env.CatalogConfig,
+	monitor ports.CatalogMonitor,
+	userReader usercontext.Reader,
+	adminRoles []string,
+) *CatalogHealth {
+	return &CatalogHealth{
+		envCatalogConfig: envCatalogConfig,
+		monitor:          monitor,
+		userReader:       userReader,
+		adminRoles:       adminRoles,
+	}
+}
+
+func (uc *CatalogHealth) ListCatalogHealth(ctx context.Context) ([]domain.CatalogHealth, error) {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.CatalogHealth, 0, len(uc.envCatalogConfig))
+	for _, cfg := range uc.envCatalogConfig {
+		health, err := uc.monitor.CatalogHealth(ctx, cfg.ID)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %q: %w", cfg.ID, err)
+		}
+		out = append(out, health)
+	}
+	return out, nil
+}
+
+func (uc *CatalogHealth) RefreshCatalog(
+	ctx context.Context,
+	catalogID string,
+) (domain.CatalogHealth, error) {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return domain.CatalogHealth{}, err
+	}
+	return uc.monitor.RefreshCatalog(ctx, catalogID)
+}
+
+func (uc *CatalogHealth) requireAdmin(ctx context.Context) error {
+	user, ok := uc.userReader.GetUser(ctx)
+	if !ok || user == nil {
+		return fmt.Errorf("catalog administration: %w", domain.ErrForbidden)
+	}
+	for _, role := range user.Roles {
+		if slices.Contains(uc.adminRoles, role) {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %q is not a catalog administrator: %w", user.Username, domain.ErrForbidden)
+}
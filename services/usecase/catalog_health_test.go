@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------- Mock Monitor ----------
+
+type MockCatalogMonitor struct{ mock.Mock }
+
+var _ ports.CatalogMonitor = (*MockCatalogMonitor)(nil)
+
+func (m *MockCatalogMonitor) CatalogHealth(
+	ctx context.Context,
+	catalogID string,
+) (domain.CatalogHealth, error) {
+	args := m.Called(ctx, catalogID)
+	return args.Get(0).(domain.CatalogHealth), args.Error(1)
+}
+
+func (m *MockCatalogMonitor) RefreshCatalog(
+	ctx context.Context,
+	catalogID string,
+) (domain.CatalogHealth, error) {
+	args := m.Called(ctx, catalogID)
+	return args.Get(0).(domain.CatalogHealth), args.Error(1)
+}
+
+func setupCatalogHealth(
+	t *testing.T,
+	user *usercontext.User,
+) (*CatalogHealth, context.Context, *MockCatalogMonitor) {
+	t.Helper()
+
+	ctx, reader, _ := usercontext.NewTestUserContext(user)
+	monitor := new(MockCatalogMonitor)
+	cfgs := []env.CatalogConfig{{ID: "ide"}, {ID: "databases"}}
+
+	return NewCatalogHealthService(cfgs, monitor, reader, []string{"onyxia-admin"}), ctx, monitor
+}
+
+func adminUser() *usercontext.User {
+	return &usercontext.User{Username: "admin", Roles: []string{"onyxia-admin"}}
+}
+
+// ---------- Tests ----------
+
+// ✅ Admins get the health of every catalog, in configuration order.
+func TestListCatalogHealth(t *testing.T) {
+	uc, ctx, monitor := setupCatalogHealth(t, adminUser())
+	refreshed := time.Now()
+
+	monitor.On("CatalogHealth", mock.Anything, "ide").
+		Return(domain.CatalogHealth{CatalogID: "ide", Packages: 12, LastRefresh: refreshed}, nil)
+	monitor.On("CatalogHealth", mock.Anything, "databases").
+		Return(domain.CatalogHealth{CatalogID: "databases", LastError: "connection refused"}, nil)
+
+	healths, err := uc.ListCatalogHealth(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, healths, 2)
+	assert.Equal(t, 12, healths[0].Packages)
+	assert.Equal(t, "connection refused", healths[1].LastError)
+}
+
+// ✅ Admins can refresh a catalog.
+func TestRefreshCatalog(t *testing.T) {
+	uc, ctx, monitor := setupCatalogHealth(t, adminUser())
+
+	monitor.On("RefreshCatalog", mock.Anything, "ide").
+		Return(domain.CatalogHealth{CatalogID: "ide", Packages: 3}, nil)
+
+	health, err := uc.RefreshCatalog(ctx, "ide")
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, health.Packages)
+}
+
+// ❌ Users without an admin role are forbidden.
+func TestCatalogHealth_NotAdmin(t *testing.T) {
+	uc, ctx, monitor := setupCatalogHealth(t, usercontext.DefaultTestUser())
+
+	_, err := uc.ListCatalogHealth(ctx)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	_, err = uc.RefreshCatalog(ctx, "ide")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	monitor.AssertNotCalled(t, "RefreshCatalog", mock.Anything, mock.Anything)
+}
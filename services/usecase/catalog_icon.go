@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+)
+
+// defaultIcon is served for packages without a usable icon.
+var defaultIcon = domain.Icon{
+	ContentType: "image/svg+xml",
+	Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">` +
+		`<path fill="#9e9e9e" d="M12 2 3 7v10l9 5 9-5V7l-9-5zm0 2.3 6.7 3.7L12 11.7 5.3 8 12 4.3z` +
+		`M5 9.7l6 3.3v6.6l-6-3.3V9.7zm8 9.9V13l6-3.3v6.6l-6 3.3z"/></svg>`),
+}
+
+// GetPackageIcon applies catalog restrictions like the other package
+// endpoints, reporting denied packages as missing so that restricted ones
+// cannot be enumerated.
+func (uc *Catalog) GetPackageIcon(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+) (domain.Icon, error) {
+	if _, err := uc.policy.AuthorizePackage(ctx, catalogID, packageName); err != nil {
+		if errors.Is(err, domain.ErrForbidden) {
+			return domain.Icon{}, fmt.Errorf(
+				"package %q in catalog %q: %w",
+				packageName,
+				catalogID,
+				domain.ErrNotFound,
+			)
+		}
+		return domain.Icon{}, err
+	}
+
+	icon, err := uc.pkgRepo.GetPackageIcon(ctx, catalogID, packageName)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Icon{}, err
+	case err != nil:
+		slog.WarnContext(ctx, "Serving default package icon",
+			slog.String("catalog", catalogID),
+			slog.String("package", packageName),
+			slog.Any("error", err),
+		)
+		icon = defaultIcon
+	case len(icon.Data) == 0:
+		icon = defaultIcon
+	}
+	icon.Restricted = !uc.policy.IsPublicPackage(catalogID, packageName)
+	return icon, nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ✅ The upstream icon is returned when available.
+func TestGetPackageIcon_Upstream(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "ide"}})
+	icon := domain.Icon{ContentType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}}
+
+	repo.On("GetPackageIcon", mock.Anything, "ide", "jupyter").Return(icon, nil)
+
+	got, err := uc.GetPackageIcon(ctx, "ide", "jupyter")
+
+	require.NoError(t, err)
+	assert.Equal(t, icon, got)
+}
+
+// ✅ Missing or broken icons fall back to the default icon.
+func TestGetPackageIcon_DefaultIcon(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "ide"}})
+
+	repo.On("GetPackageIcon", mock.Anything, "ide", "no-icon").Return(domain.Icon{}, nil)
+	repo.On("GetPackageIcon", mock.Anything, "ide", "broken").
+		Return(domain.Icon{}, errors.New("icon is larger than 262144 bytes"))
+
+	for _, name := range []string{"no-icon", "broken"} {
+		got, err := uc.GetPackageIcon(ctx, "ide", name)
+
+		require.NoError(t, err)
+		assert.Equal(t, defaultIcon, got)
+	}
+}
+
+// ❌ Icons of restricted catalogs and packages are not found for other users.
+func TestGetPackageIcon_Restricted(t *testing.T) {
+	cfgs := []env.CatalogConfig{
+		{ID: "admin", Restrictions: []env.Restriction{{Group: "^admins$"}}},
+		{ID: "ide", PackageRestrictions: []env.PackageRestriction{{
+			Name:         "gpu",
+			Restrictions: []env.Restriction{{Group: "^admins$"}},
+		}}},
+	}
+
+	for _, ctxUser := range []*usercontext.User{nil, usercontext.DefaultTestUser()} {
+		uc, ctx, repo := setupCatalogUsecase(t, ctxUser, cfgs)
+
+		_, err := uc.GetPackageIcon(ctx, "admin", "tool")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = uc.GetPackageIcon(ctx, "ide", "gpu")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		repo.AssertNotCalled(t, "GetPackageIcon", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+// ✅ Icons of restricted packages are served to allowed users, marked restricted.
+func TestGetPackageIcon_RestrictedAllowed(t *testing.T) {
+	cfgs := []env.CatalogConfig{
+		{ID: "admin", Restrictions: []env.Restriction{{Group: "^admins$"}}},
+		{ID: "ide"},
+	}
+	admin := &usercontext.User{Username: "alice", Groups: []string{"admins"}}
+	uc, ctx, repo := setupCatalogUsecase(t, admin, cfgs)
+
+	repo.On("GetPackageIcon", mock.Anything, mock.Anything, mock.Anything).Return(domain.Icon{}, nil)
+
+	icon, err := uc.GetPackageIcon(ctx, "admin", "tool")
+	require.NoError(t, err)
+	assert.True(t, icon.Restricted)
+
+	icon, err = uc.GetPackageIcon(ctx, "ide", "jupyter")
+	require.NoError(t, err)
+	assert.False(t, icon.Restricted)
+}
+
+// ❌ Unknown catalogs and packages are not found.
+func TestGetPackageIcon_NotFound(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "ide"}})
+
+	repo.On("GetPackageIcon", mock.Anything, "ide", "unknown").
+		Return(domain.Icon{}, domain.ErrNotFound)
+
+	_, err := uc.GetPackageIcon(ctx, "ide", "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = uc.GetPackageIcon(ctx, "nope", "jupyter")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	repo.AssertNotCalled(t, "GetPackageIcon", mock.Anything, "nope", mock.Anything)
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxConcurrentCatalogLoads bounds the number of catalogs loaded at once.
+const maxConcurrentCatalogLoads = 8
+
+// catalogPackages is the outcome of loading the packages of one catalog.
+type catalogPackages struct {
+	cfg  env.CatalogConfig
+	pkgs []domain.Package
+	err  error
+	// revision is read after the packages, so that it matches them.
+	revision catalogRevision
+}
+
+// catalogRevision is the outcome of reading the index revision of one catalog.
+type catalogRevision struct {
+	cfg      env.CatalogConfig
+	revision string
+	err      error
+}
+
+// loadPackages lists the packages of cfgs in parallel. Each catalog gets its
+// own timeout, and a failing catalog does not prevent loading the others.
+// Results are in the same order as cfgs; packages are not yet filtered by
+// the policy.
+func (uc *Catalog) loadPackages(ctx context.Context, cfgs []env.CatalogConfig) []catalogPackages {
+	results := make([]catalogPackages, len(cfgs))
+	forEachCatalog(cfgs, func(i int, cfg env.CatalogConfig) {
+		pkgs, err := uc.listPackages(ctx, cfg.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to load catalog",
+				slog.String("catalog", cfg.ID),
+				slog.Any("error", err),
+			)
+			results[i] = catalogPackages{
+				cfg:      cfg,
+				err:      err,
+				revision: catalogRevision{cfg: cfg, err: err},
+			}
+			return
+		}
+		// Listing refreshes the index when needed, so the revision read
+		// afterwards is the one pkgs come from.
+		results[i] = catalogPackages{cfg: cfg, pkgs: pkgs, revision: uc.catalogRevision(ctx, cfg)}
+	})
+	return results
+}
+
+// loadRevisions reads the index revisions of cfgs in parallel, the way
+// loadPackages lists their packages. Results are in the same order as cfgs.
+func (uc *Catalog) loadRevisions(ctx context.Context, cfgs []env.CatalogConfig) []catalogRevision {
+	results := make([]catalogRevision, len(cfgs))
+	forEachCatalog(cfgs, func(i int, cfg env.CatalogConfig) {
+		results[i] = uc.catalogRevision(ctx, cfg)
+	})
+	return results
+}
+
+// forEachCatalog calls load for each of cfgs, at most
+// maxConcurrentCatalogLoads at once, and waits for them all.
+func forEachCatalog(cfgs []env.CatalogConfig, load func(i int, cfg env.CatalogConfig)) {
+	sem := make(chan struct{}, maxConcurrentCatalogLoads)
+
+	var wg sync.WaitGroup
+	for i, cfg := range cfgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			load(i, cfg)
+		}()
+	}
+	wg.Wait()
+}
+
+// catalogRevision reads the index revision of a catalog, giving up after the
+// load timeout.
+func (uc *Catalog) catalogRevision(ctx context.Context, cfg env.CatalogConfig) catalogRevision {
+	revision, err := withLoadTimeout(ctx, &uc.loads, "revision/"+cfg.ID, uc.loadTimeout,
+		func(ctx context.Context) (string, error) {
+			return uc.pkgRepo.CatalogRevision(ctx, cfg.ID)
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("revision: %w", err)
+	}
+	return catalogRevision{cfg: cfg, revision: revision, err: err}
+}
+
+// listPackages lists the packages of a catalog, giving up after the load
+// timeout.
+func (uc *Catalog) listPackages(ctx context.Context, catalogID string) ([]domain.Package, error) {
+	pkgs, err := withLoadTimeout(ctx, &uc.loads, "packages/"+catalogID, uc.loadTimeout,
+		func(ctx context.Context) ([]domain.Package, error) {
+			return uc.pkgRepo.ListPackages(ctx, catalogID)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list packages: %w", err)
+	}
+	return pkgs, nil
+}
+
+// withLoadTimeout calls load, giving up after timeout; zero means no limit.
+// Callers sharing key while a load runs wait for that load instead of
+// starting another one, so an unreachable catalog is hit once at a time
+// however many requests time out on it. load is detached from ctx and left to
+// finish in the background so its result still lands in the repository cache.
+func withLoadTimeout[T any](
+	ctx context.Context,
+	loads *singleflight.Group,
+	key string,
+	timeout time.Duration,
+	load func(context.Context) (T, error),
+) (T, error) {
+	done := loads.DoChan(key, func() (any, error) {
+		return load(context.WithoutCancel(ctx))
+	})
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var zero T
+	select {
+	case r := <-done:
+		if r.Err != nil {
+			return zero, r.Err
+		}
+		return r.Val.(T), nil
+	case <-expired:
+		return zero, context.DeadlineExceeded
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
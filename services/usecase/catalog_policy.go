@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+)
+
+// CatalogPolicy decides which catalogs and packages the current user may
+// access, based on the catalog restrictions. It is shared by every
+// catalog-facing use case. Restrictions are compiled once at construction.
+type CatalogPolicy struct {
+	catalogs   map[string]*compiledCatalog
+	userReader usercontext.Reader
+}
+
+type compiledCatalog struct {
+	cfg      *env.CatalogConfig
+	rule     env.Rule            // nil when the catalog is unrestricted
+	packages map[string]env.Rule // package-level rules, on top of rule
+}
+
+func NewCatalogPolicy(
+	catalogs []env.CatalogConfig,
+	userReader usercontext.Reader,
+) (*CatalogPolicy, error) {
+	compiled := make(map[string]*compiledCatalog, len(catalogs))
+
+	for i := range catalogs {
+		cfg := &catalogs[i]
+
+		r, err := env.CompileRestrictions(cfg.Restrictions)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %q: %w", cfg.ID, err)
+		}
+
+		packages := make(map[string]env.Rule, len(cfg.PackageRestrictions))
+		for _, pr := range cfg.PackageRestrictions {
+			pkgRule, err := env.CompileRestrictions(pr.Restrictions)
+			if err != nil {
+				return nil, fmt.Errorf("catalog %q: package %q: %w", cfg.ID, pr.Name, err)
+			}
+			if pkgRule != nil {
+				packages[pr.Name] = pkgRule
+			}
+		}
+
+		compiled[cfg.ID] = &compiledCatalog{cfg: cfg, rule: r, packages: packages}
+	}
+
+	return &CatalogPolicy{catalogs: compiled, userReader: userReader}, nil
+}
+
+// IsPublic reports whether cfg can be accessed without authentication.
+func (p *CatalogPolicy) IsPublic(cfg env.CatalogConfig) bool {
+	c, ok := p.catalogs[cfg.ID]
+	return ok && c.rule == nil
+}
+
+// IsPublicPackage reports whether packageName of catalogID can be accessed
+// without authentication.
+func (p *CatalogPolicy) IsPublicPackage(catalogID, packageName string) bool {
+	c, ok := p.catalogs[catalogID]
+	return ok && c.rule == nil && c.packages[packageName] == nil
+}
+
+// CanAccess reports whether the user in ctx may access cfg.
+func (p *CatalogPolicy) CanAccess(ctx context.Context, cfg env.CatalogConfig) bool {
+	c, ok := p.catalogs[cfg.ID]
+	if !ok {
+		return false
+	}
+	return p.allows(ctx, c.rule)
+}
+
+// Offers reports whether cfg is offered to the user in ctx: the user may
+// access it and it is visible in project context when project is set, or in
+// user context otherwise.
+func (p *CatalogPolicy) Offers(ctx context.Context, cfg env.CatalogConfig, project string) bool {
+	if project != "" && !cfg.Visible.InProject() {
+		return false
+	}
+	if project == "" && !cfg.Visible.InUser() {
+		return false
+	}
+	return p.CanAccess(ctx, cfg)
+}
+
+// CanAccessPackage reports whether the user in ctx may access packageName in
+// an accessible catalog. Packages without their own restrictions inherit the
+// catalog decision.
+func (p *CatalogPolicy) CanAccessPackage(ctx context.Context, catalogID, packageName string) bool {
+	c, ok := p.catalogs[catalogID]
+	if !ok {
+		return false
+	}
+	return p.allows(ctx, c.rule) && p.allows(ctx, c.packages[packageName])
+}
+
+// FilterPackages drops the packages of catalogID the user in ctx may not
+// access and marks the others that are not public as restricted.
+func (p *CatalogPolicy) FilterPackages(
+	ctx context.Context,
+	catalogID string,
+	pkgs []domain.Package,
+) []domain.Package {
+	c, ok := p.catalogs[catalogID]
+	if !ok {
+		return pkgs
+	}
+	out := make([]domain.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		rule := c.packages[pkg.Name]
+		if p.allows(ctx, rule) {
+			pkg.Restricted = c.rule != nil || rule != nil
+			out = append(out, pkg)
+		}
+	}
+	return out
+}
+
+// DeniedPackages returns, sorted, the packages of catalogID the user in ctx
+// may not access because of package-level restrictions.
+func (p *CatalogPolicy) DeniedPackages(ctx context.Context, catalogID string) []string {
+	c, ok := p.catalogs[catalogID]
+	if !ok {
+		return nil
+	}
+	var denied []string
+	for name, r := range c.packages {
+		if !p.allows(ctx, r) {
+			denied = append(denied, name)
+		}
+	}
+	sort.Strings(denied)
+	return denied
+}
+
+// Authorize returns the catalog config for catalogID if the user in ctx may
+// access it. It fails with domain.ErrNotFound for unknown catalogs and
+// domain.ErrForbidden when the restrictions deny access.
+func (p *CatalogPolicy) Authorize(ctx context.Context, catalogID string) (*env.CatalogConfig, error) {
+	c, ok := p.catalogs[catalogID]
+	if !ok {
+		return nil, fmt.Errorf("catalog %q: %w", catalogID, domain.ErrNotFound)
+	}
+	if !p.allows(ctx, c.rule) {
+		return nil, fmt.Errorf("catalog %q: %w", catalogID, domain.ErrForbidden)
+	}
+	return c.cfg, nil
+}
+
+// AuthorizePackage is Authorize, additionally applying the package-level
+// restrictions of packageName.
+func (p *CatalogPolicy) AuthorizePackage(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+) (*env.CatalogConfig, error) {
+	cfg, err := p.Authorize(ctx, catalogID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.allows(ctx, p.catalogs[catalogID].packages[packageName]) {
+		return nil, fmt.Errorf(
+			"package %q in catalog %q: %w",
+			packageName,
+			catalogID,
+			domain.ErrForbidden,
+		)
+	}
+	return cfg, nil
+}
+
+// allows reports whether the user in ctx satisfies r. A nil rule allows
+// everyone, including anonymous users.
+func (p *CatalogPolicy) allows(ctx context.Context, r env.Rule) bool {
+	if r == nil {
+		return true
+	}
+	u, ok := p.userReader.GetUser(ctx)
+	if !ok {
+		return false
+	}
+	return r.Matches(u)
+}
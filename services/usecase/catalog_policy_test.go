@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func restrictedCatalog(id string, restrictions ...env.Restriction) env.CatalogConfig {
+	return env.CatalogConfig{ID: id, Restrictions: restrictions}
+}
+
+func newTestPolicy(
+	t *testing.T,
+	user *usercontext.User,
+	cfgs ...env.CatalogConfig,
+) (*CatalogPolicy, context.Context) {
+	t.Helper()
+	ctx, reader, _ := usercontext.NewTestUserContext(user)
+	policy, err := NewCatalogPolicy(cfgs, reader)
+	require.NoError(t, err)
+	return policy, ctx
+}
+
+// ✅ Unrestricted catalogs are public and accessible to anyone.
+func TestCatalogPolicy_PublicCatalog(t *testing.T) {
+	reader, _ := usercontext.NewUserContext()
+	cfg := env.CatalogConfig{ID: "public"}
+	policy, err := NewCatalogPolicy([]env.CatalogConfig{cfg}, reader)
+	require.NoError(t, err)
+
+	assert.True(t, policy.IsPublic(cfg))
+	assert.True(t, policy.CanAccess(context.Background(), cfg))
+}
+
+// ✅ Attribute values may be a string, a []string or a []any.
+func TestCatalogPolicy_CanAccess_AttributeTypes(t *testing.T) {
+	cfg := restrictedCatalog("restricted",
+		env.Restriction{UserAttributeKey: "groups", Match: "^sspcloud-dev$"},
+	)
+
+	for name, val := range map[string]any{
+		"string":       "sspcloud-dev",
+		"string slice": []string{"users", "sspcloud-dev"},
+		"any slice":    []any{"users", "sspcloud-dev"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy, ctx := newTestPolicy(t, &usercontext.User{
+				Username:   "alice",
+				Attributes: map[string]any{"groups": val},
+			}, cfg)
+
+			assert.True(t, policy.CanAccess(ctx, cfg))
+		})
+	}
+}
+
+// ✅ Group, role and combinator rules.
+func TestCatalogPolicy_CanAccess_Rules(t *testing.T) {
+	user := &usercontext.User{
+		Username: "alice",
+		Groups:   []string{"sspcloud-dev"},
+		Roles:    []string{"trainer"},
+	}
+
+	tests := map[string]struct {
+		restriction env.Restriction
+		want        bool
+	}{
+		"group matches":  {env.Restriction{Group: "^sspcloud-"}, true},
+		"group no match": {env.Restriction{Group: "^ops$"}, false},
+		"role matches":   {env.Restriction{Role: "trainer"}, true},
+		"all matches": {env.Restriction{All: []env.Restriction{
+			{Group: "sspcloud-dev"}, {Role: "trainer"},
+		}}, true},
+		"all partial": {env.Restriction{All: []env.Restriction{
+			{Group: "sspcloud-dev"}, {Role: "admin"},
+		}}, false},
+		"any partial": {env.Restriction{Any: []env.Restriction{
+			{Group: "ops"}, {Role: "trainer"},
+		}}, true},
+		"not": {env.Restriction{All: []env.Restriction{
+			{Group: "sspcloud-"}, {Not: &env.Restriction{Role: "trainer"}},
+		}}, false},
+		"attribute without pattern": {
+			env.Restriction{UserAttributeKey: "groups"}, false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := restrictedCatalog("restricted", tt.restriction)
+			policy, ctx := newTestPolicy(t, user, cfg)
+
+			assert.Equal(t, tt.want, policy.CanAccess(ctx, cfg))
+		})
+	}
+}
+
+// ❌ Anonymous users cannot access restricted catalogs.
+func TestCatalogPolicy_CanAccess_Anonymous(t *testing.T) {
+	reader, _ := usercontext.NewUserContext()
+	cfg := restrictedCatalog("restricted", env.Restriction{Group: "sspcloud-dev"})
+	policy, err := NewCatalogPolicy([]env.CatalogConfig{cfg}, reader)
+	require.NoError(t, err)
+
+	assert.False(t, policy.IsPublic(cfg))
+	assert.False(t, policy.CanAccess(context.Background(), cfg))
+}
+
+// ❌ Invalid regexes are rejected when the policy is built.
+func TestNewCatalogPolicy_InvalidRegex(t *testing.T) {
+	reader, _ := usercontext.NewUserContext()
+	_, err := NewCatalogPolicy([]env.CatalogConfig{
+		restrictedCatalog("bad", env.Restriction{Role: "("}),
+	}, reader)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `catalog "bad"`)
+}
+
+// ❌ Malformed restrictions are rejected when the policy is built.
+func TestNewCatalogPolicy_InvalidRestriction(t *testing.T) {
+	reader, _ := usercontext.NewUserContext()
+	for name, r := range map[string]env.Restriction{
+		"several kinds": {Group: "a", Role: "b"},
+		"no kind":       {},
+		"missing key":   {Match: "^a$"},
+		"empty all":     {All: []env.Restriction{}},
+		"nested":        {Not: &env.Restriction{Any: []env.Restriction{{Group: "("}}}},
+	} {
+		_, err := NewCatalogPolicy([]env.CatalogConfig{restrictedCatalog("bad", r)}, reader)
+		assert.Error(t, err, name)
+	}
+}
+
+// ✅ Authorize returns the catalog config when access is granted.
+func TestCatalogPolicy_Authorize_Granted(t *testing.T) {
+	policy, ctx := newTestPolicy(t,
+		&usercontext.User{Username: "alice", Groups: []string{"sspcloud-dev"}},
+		restrictedCatalog("restricted", env.Restriction{Group: "sspcloud-dev"}),
+	)
+
+	cfg, err := policy.Authorize(ctx, "restricted")
+
+	require.NoError(t, err)
+	assert.Equal(t, "restricted", cfg.ID)
+}
+
+// ❌ Authorize fails with ErrForbidden or ErrNotFound.
+func TestCatalogPolicy_Authorize_Denied(t *testing.T) {
+	policy, ctx := newTestPolicy(t, usercontext.DefaultTestUser(),
+		restrictedCatalog("restricted", env.Restriction{Group: "sspcloud-admin"}),
+	)
+
+	_, err := policy.Authorize(ctx, "restricted")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	_, err = policy.Authorize(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// ✅ Package restrictions apply on top of the catalog decision.
+func TestCatalogPolicy_PackageRestrictions(t *testing.T) {
+	cfg := env.CatalogConfig{
+		ID: "ide",
+		PackageRestrictions: []env.PackageRestriction{{
+			Name:         "vscode-gpu",
+			Restrictions: []env.Restriction{{Role: "gpu-user"}},
+		}},
+	}
+	policy, ctx := newTestPolicy(t, usercontext.DefaultTestUser(), cfg)
+
+	assert.True(t, policy.CanAccessPackage(ctx, "ide", "jupyter"))
+	assert.False(t, policy.CanAccessPackage(ctx, "ide", "vscode-gpu"))
+
+	_, err := policy.AuthorizePackage(ctx, "ide", "vscode-gpu")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	filtered := policy.FilterPackages(ctx, "ide", []domain.Package{
+		{Name: "jupyter"}, {Name: "vscode-gpu"},
+	})
+	assert.Equal(t, []domain.Package{{Name: "jupyter"}}, filtered)
+}
+
+// ✅ Packages behind a catalog or package restriction are marked restricted.
+func TestCatalogPolicy_FilterPackages_MarksRestricted(t *testing.T) {
+	user := usercontext.DefaultTestUser()
+	user.Roles = []string{"gpu-user"}
+	open := env.CatalogConfig{
+		ID: "ide",
+		PackageRestrictions: []env.PackageRestriction{{
+			Name:         "vscode-gpu",
+			Restrictions: []env.Restriction{{Role: "gpu-user"}},
+		}},
+	}
+	restricted := env.CatalogConfig{
+		ID:           "private",
+		Restrictions: []env.Restriction{{Role: "gpu-user"}},
+	}
+	policy, ctx := newTestPolicy(t, user, open, restricted)
+
+	pkgs := []domain.Package{{Name: "jupyter"}, {Name: "vscode-gpu"}}
+	assert.Equal(t,
+		[]domain.Package{{Name: "jupyter"}, {Name: "vscode-gpu", Restricted: true}},
+		policy.FilterPackages(ctx, "ide", pkgs),
+	)
+	assert.Equal(t,
+		[]domain.Package{{Name: "jupyter", Restricted: true}, {Name: "vscode-gpu", Restricted: true}},
+		policy.FilterPackages(ctx, "private", pkgs),
+	)
+	assert.False(t, pkgs[1].Restricted)
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+)
+
+// Relevance weights of a search term, by where it matches.
+const (
+	scoreNameExact       = 100
+	scoreNamePrefix      = 50
+	scoreNameContains    = 30
+	scoreKeywordExact    = 20
+	scoreKeywordContains = 10
+	scoreDescription     = 5
+	scoreHighlighted     = 15
+)
+
+func (uc *Catalog) SearchPackages(
+	ctx context.Context,
+	project string,
+	query string,
+	limit int,
+) ([]domain.PackageSearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty search query: %w", domain.ErrInvalidInput)
+	}
+
+	results := make([]domain.PackageSearchResult, 0)
+
+	accessible := make([]env.CatalogConfig, 0, len(uc.envCatalogConfig))
+	for _, cfg := range uc.envCatalogConfig {
+		if uc.policy.Offers(ctx, cfg, project) {
+			accessible = append(accessible, cfg)
+		}
+	}
+
+	for _, loaded := range uc.loadPackages(ctx, accessible) {
+		// One broken catalog should not prevent searching the others.
+		if loaded.err != nil {
+			continue
+		}
+		cfg := loaded.cfg
+
+		for _, pkg := range uc.policy.FilterPackages(ctx, cfg.ID, loaded.pkgs) {
+			score := scorePackage(pkg, terms)
+			if score == 0 {
+				continue
+			}
+			highlighted := slices.Contains(cfg.Highlighted, pkg.Name)
+			if highlighted {
+				score += scoreHighlighted
+			}
+			results = append(results, domain.PackageSearchResult{
+				Package:     pkg,
+				Highlighted: highlighted,
+				Score:       score,
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Highlighted != results[j].Highlighted {
+			return results[i].Highlighted
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// scorePackage sums the relevance of every term against pkg. It returns 0 when
+// any term does not match at all.
+func scorePackage(pkg domain.Package, terms []string) int {
+	name := strings.ToLower(pkg.Name)
+	description := strings.ToLower(pkg.Description)
+
+	total := 0
+	for _, term := range terms {
+		best := 0
+		switch {
+		case name == term:
+			best = scoreNameExact
+		case strings.HasPrefix(name, term):
+			best = scoreNamePrefix
+		case strings.Contains(name, term):
+			best = scoreNameContains
+		}
+
+		for _, kw := range pkg.Keywords {
+			kw = strings.ToLower(kw)
+			switch {
+			case kw == term:
+				best = max(best, scoreKeywordExact)
+			case strings.Contains(kw, term):
+				best = max(best, scoreKeywordContains)
+			}
+		}
+
+		if best == 0 && strings.Contains(description, term) {
+			best = scoreDescription
+		}
+
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
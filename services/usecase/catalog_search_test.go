@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func resultNames(results []domain.PackageSearchResult) []string {
+	out := make([]string, 0, len(results))
+	for _, r := range results {
+		out = append(out, r.Name)
+	}
+	return out
+}
+
+// ✅ Results are ranked by relevance across catalogs.
+func TestSearchPackages_RankedAcrossCatalogs(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "ide"}, {ID: "databases"}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	repo.On("ListPackages", mock.Anything, "ide").Return([]domain.Package{
+		{CatalogID: "ide", Name: "jupyter-python", Description: "Notebooks"},
+		{CatalogID: "ide", Name: "rstudio", Description: "R IDE"},
+		{CatalogID: "ide", Name: "vscode-python", Keywords: []string{"python", "ide"}},
+	}, nil)
+	repo.On("ListPackages", mock.Anything, "databases").Return([]domain.Package{
+		{CatalogID: "databases", Name: "postgresql", Description: "SQL database, python friendly"},
+		{CatalogID: "databases", Name: "python", Description: "Exact name"},
+	}, nil)
+
+	results, err := uc.SearchPackages(ctx, "", "Python", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"python", "jupyter-python", "vscode-python", "postgresql"},
+		resultNames(results),
+	)
+	assert.Equal(t, "databases", results[0].CatalogID)
+}
+
+// ✅ Every term must match; highlighted packages get a boost.
+func TestSearchPackages_AllTermsAndHighlightBoost(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "ide", Highlighted: []string{"vscode-gpu"}}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	repo.On("ListPackages", mock.Anything, "ide").Return([]domain.Package{
+		{Name: "jupyter-gpu", Description: "Notebooks on GPU"},
+		{Name: "vscode-gpu", Description: "Notebooks and code on GPU"},
+		{Name: "jupyter", Description: "Notebooks"},
+	}, nil)
+
+	results, err := uc.SearchPackages(ctx, "", "notebook gpu", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vscode-gpu", "jupyter-gpu"}, resultNames(results))
+	assert.True(t, results[0].Highlighted)
+}
+
+// ✅ Restricted catalogs are skipped and failing catalogs do not break the search.
+func TestSearchPackages_SkipsForbiddenAndFailingCatalogs(t *testing.T) {
+	cfgs := []env.CatalogConfig{
+		{ID: "public"},
+		{ID: "broken"},
+		{ID: "admin", Restrictions: []env.Restriction{{Group: "admins"}}},
+	}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	repo.On("ListPackages", mock.Anything, "public").
+		Return([]domain.Package{{Name: "spark"}}, nil)
+	repo.On("ListPackages", mock.Anything, "broken").
+		Return(nil, errors.New("index unavailable"))
+
+	results, err := uc.SearchPackages(ctx, "", "spark", 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spark"}, resultNames(results))
+	repo.AssertNotCalled(t, "ListPackages", mock.Anything, "admin")
+}
+
+// ✅ Catalogs hidden in the user or project context are not searched.
+func TestSearchPackages_Visibility(t *testing.T) {
+	no := false
+	cfgs := []env.CatalogConfig{
+		{ID: "user-only", Visible: env.CatalogVisibility{Project: &no}},
+		{ID: "project-only", Visible: env.CatalogVisibility{User: &no}},
+	}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	repo.On("ListPackages", mock.Anything, "user-only").
+		Return([]domain.Package{{Name: "spark-user"}}, nil)
+	repo.On("ListPackages", mock.Anything, "project-only").
+		Return([]domain.Package{{Name: "spark-project"}}, nil)
+
+	results, err := uc.SearchPackages(ctx, "", "spark", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spark-user"}, resultNames(results))
+
+	results, err = uc.SearchPackages(ctx, "project-a", "spark", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spark-project"}, resultNames(results))
+}
+
+// ✅ Results are truncated to limit.
+func TestSearchPackages_Limit(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "ide"}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	repo.On("ListPackages", mock.Anything, "ide").Return([]domain.Package{
+		{Name: "app-a"}, {Name: "app-b"}, {Name: "app-c"},
+	}, nil)
+
+	results, err := uc.SearchPackages(ctx, "", "app", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app-a", "app-b"}, resultNames(results))
+}
+
+// ❌ Blank queries are rejected.
+func TestSearchPackages_EmptyQuery(t *testing.T) {
+	uc, ctx, _ := setupCatalogUsecase(t, usercontext.DefaultTestUser(), []env.CatalogConfig{{ID: "ide"}})
+
+	_, err := uc.SearchPackages(ctx, "", "   ", 10)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
 	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
@@ -11,6 +13,7 @@ import (
 	"github.com/onyxia-datalab/onyxia-backend/services/ports"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // ---------- Mock Repository ----------
@@ -56,6 +59,57 @@ func (m *MockCatalogRepository) GetPackageSchema(
 	return nil, args.Error(1)
 }
 
+func (m *MockCatalogRepository) GetPackageReadme(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) (string, error) {
+	args := m.Called(ctx, catalogID, packageName, version)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetPackageValues(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+) ([]byte, error) {
+	args := m.Called(ctx, catalogID, packageName, version)
+	if res := args.Get(0); res != nil {
+		return res.([]byte), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// CatalogRevision serves a fixed revision to tests that do not expect calls
+// to it, since listing catalogs reads revisions too.
+func (m *MockCatalogRepository) CatalogRevision(
+	ctx context.Context,
+	catalogID string,
+) (string, error) {
+	expected := slices.ContainsFunc(m.ExpectedCalls, func(c *mock.Call) bool {
+		return c.Method == "CatalogRevision"
+	})
+	if !expected {
+		return "rev", nil
+	}
+	args := m.Called(ctx, catalogID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetPackageIcon(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+) (domain.Icon, error) {
+	args := m.Called(ctx, catalogID, packageName)
+	if res := args.Get(0); res != nil {
+		return res.(domain.Icon), args.Error(1)
+	}
+	return domain.Icon{}, args.Error(1)
+}
+
 func (m *MockCatalogRepository) ResolvePackage(
 	ctx context.Context,
 	catalogID string,
@@ -69,6 +123,17 @@ func (m *MockCatalogRepository) ResolvePackage(
 	return domain.PackageVersion{}, args.Error(1)
 }
 
+func (m *MockCatalogRepository) ValidateValues(
+	ctx context.Context,
+	catalogID string,
+	packageName string,
+	version string,
+	values map[string]any,
+) error {
+	args := m.Called(ctx, catalogID, packageName, version, values)
+	return args.Error(0)
+}
+
 // ---------- Setup Helper ----------
 
 // setupCatalogUsecase abstracts away mocks, readers, and context initialization.
@@ -93,7 +158,10 @@ func setupCatalogUsecase(
 		}
 	}
 
-	uc := NewCatalogService(cfgs, repo, reader)
+	policy, err := NewCatalogPolicy(cfgs, reader)
+	require.NoError(t, err)
+
+	uc := NewCatalogService(cfgs, repo, policy, time.Second)
 	return uc, ctx, repo
 }
 
@@ -116,7 +184,7 @@ func TestListPublicCatalogs(t *testing.T) {
 	repo.On("ListPackages", mock.Anything, cfgs[0].ID).
 		Return([]domain.Package{{Name: "chart"}}, nil)
 
-	catalogs, err := uc.ListPublicCatalogs(ctx)
+	catalogs, _, err := uc.ListPublicCatalogs(ctx)
 
 	assert.NoError(t, err)
 	assert.Len(t, catalogs, 1)
@@ -154,7 +222,7 @@ func TestListUserCatalogs_Match(t *testing.T) {
 	repo.On("ListPackages", mock.Anything, cfgs[0].ID).
 		Return([]domain.Package{{Name: "chart"}}, nil)
 
-	result, err := uc.ListUserCatalogs(ctx)
+	result, _, err := uc.ListUserCatalogs(ctx, "")
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
@@ -185,14 +253,14 @@ func TestListUserCatalogs_NoMatch(t *testing.T) {
 	repo.On("ListPackages", mock.Anything, mock.Anything).
 		Return([]domain.Package{{Name: "chart"}}, nil)
 
-	result, err := uc.ListUserCatalogs(ctx)
+	result, _, err := uc.ListUserCatalogs(ctx, "")
 
 	assert.NoError(t, err)
 	assert.Empty(t, result)
 	repo.AssertNotCalled(t, "ListPackages", mock.Anything, cfgs[0].ID)
 }
 
-// ❌ Repository returns an error — should propagate up.
+// ❌ Repository returns an error — the catalog is reported with its error.
 func TestListUserCatalogs_RepoError(t *testing.T) {
 	user := &usercontext.User{
 		Username: "dev",
@@ -214,14 +282,77 @@ func TestListUserCatalogs_RepoError(t *testing.T) {
 	repo.On("ListPackages", mock.Anything, cfgs[0].ID).
 		Return(nil, errors.New("failed to fetch"))
 
-	result, err := uc.ListUserCatalogs(ctx)
+	result, _, err := uc.ListUserCatalogs(ctx, "")
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to fetch")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "restricted-dev", result[0].ID)
+	assert.Empty(t, result[0].Packages)
+	assert.Equal(t, domain.CatalogErrorUnavailable, result[0].Error)
 	repo.AssertCalled(t, "ListPackages", mock.Anything, cfgs[0].ID)
 }
 
+// ✅ One failing catalog does not prevent serving the others, in order.
+func TestListPublicCatalogs_PartialFailure(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "broken"}, {ID: "working"}}
+
+	uc, ctx, repo := setupCatalogUsecase(t, nil, cfgs)
+	repo.On("ListPackages", mock.Anything, "broken").
+		Return(nil, errors.New("connection refused"))
+	repo.On("ListPackages", mock.Anything, "working").
+		Return([]domain.Package{{Name: "jupyter"}}, nil)
+
+	result, _, err := uc.ListPublicCatalogs(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "broken", result[0].ID)
+	assert.Equal(t, domain.CatalogErrorUnavailable, result[0].Error)
+	assert.Equal(t, "working", result[1].ID)
+	assert.Empty(t, result[1].Error)
+	assert.Len(t, result[1].Packages, 1)
+}
+
+// ❌ A catalog slower than the load timeout is reported as failed.
+func TestListPublicCatalogs_Timeout(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "slow"}, {ID: "fast"}}
+
+	uc, ctx, repo := setupCatalogUsecase(t, nil, cfgs)
+	uc.loadTimeout = 20 * time.Millisecond
+	repo.On("ListPackages", mock.Anything, "slow").
+		After(time.Second).
+		Return([]domain.Package{{Name: "late"}}, nil)
+	repo.On("ListPackages", mock.Anything, "fast").
+		Return([]domain.Package{{Name: "jupyter"}}, nil)
+
+	start := time.Now()
+	result, _, err := uc.ListPublicCatalogs(ctx)
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Len(t, result, 2)
+	assert.Equal(t, domain.CatalogErrorTimeout, result[0].Error)
+	assert.Empty(t, result[0].Packages)
+	assert.Len(t, result[1].Packages, 1)
+}
+
+// ✅ Requests timing out on a slow catalog share one load instead of piling up.
+func TestListPublicCatalogs_TimeoutSharesLoad(t *testing.T) {
+	uc, ctx, repo := setupCatalogUsecase(t, nil, []env.CatalogConfig{{ID: "slow"}})
+	uc.loadTimeout = 10 * time.Millisecond
+	repo.On("ListPackages", mock.Anything, "slow").
+		After(300*time.Millisecond).
+		Return([]domain.Package{{Name: "late"}}, nil)
+
+	for range 5 {
+		result, _, err := uc.ListPublicCatalogs(ctx)
+		require.NoError(t, err)
+		assert.NotEmpty(t, result[0].Error)
+	}
+
+	repo.AssertNumberOfCalls(t, "ListPackages", 1)
+}
+
 // ✅ GetPackage returns the package when found.
 func TestGetPackage_Found(t *testing.T) {
 	cfgs := []env.CatalogConfig{{ID: "my-catalog"}}
@@ -229,7 +360,7 @@ func TestGetPackage_Found(t *testing.T) {
 
 	expected := &domain.PackageRef{
 		Package:  domain.Package{Name: "my-chart", CatalogID: "my-catalog"},
-		Versions: []string{"1.0.0", "0.9.0"},
+		Versions: []domain.VersionInfo{{Version: "1.0.0"}, {Version: "0.9.0"}},
 	}
 	repo.On("GetPackage", mock.Anything, cfgs[0].ID, "my-chart").Return(expected, nil)
 
@@ -305,3 +436,113 @@ func TestGetPackageSchema_RepoError(t *testing.T) {
 	assert.Contains(t, err.Error(), "schema fetch failed")
 	assert.Nil(t, result)
 }
+
+// ❌ GetPackage — restricted catalog the user may not see.
+func TestGetPackage_Forbidden(t *testing.T) {
+	cfgs := []env.CatalogConfig{{
+		ID: "restricted",
+		Restrictions: []env.Restriction{
+			{UserAttributeKey: "groups", Match: "sspcloud-admin"},
+		},
+	}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	result, err := uc.GetPackage(ctx, "restricted", "my-chart")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Nil(t, result)
+	repo.AssertNotCalled(t, "GetPackage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ❌ GetPackageSchema — restricted catalog the user may not see.
+func TestGetPackageSchema_Forbidden(t *testing.T) {
+	cfgs := []env.CatalogConfig{{
+		ID: "restricted",
+		Restrictions: []env.Restriction{
+			{UserAttributeKey: "groups", Match: "sspcloud-admin"},
+		},
+	}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	result, err := uc.GetPackageSchema(ctx, "restricted", "my-chart", "1.0.0")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Nil(t, result)
+	repo.AssertNotCalled(
+		t, "GetPackageSchema", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	)
+}
+
+// ✅ GetPackageReadme and GetPackageValues return the chart documents.
+func TestGetPackageDocuments_Found(t *testing.T) {
+	cfgs := []env.CatalogConfig{{ID: "my-catalog"}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	values := []byte(`{"replicas":1}`)
+	repo.On("GetPackageReadme", mock.Anything, cfgs[0].ID, "my-chart", "1.0.0").
+		Return("# My chart", nil)
+	repo.On("GetPackageValues", mock.Anything, cfgs[0].ID, "my-chart", "1.0.0").
+		Return(values, nil)
+
+	readme, err := uc.GetPackageReadme(ctx, "my-catalog", "my-chart", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "# My chart", readme)
+
+	result, err := uc.GetPackageValues(ctx, "my-catalog", "my-chart", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, values, result)
+}
+
+// ❌ GetPackageReadme and GetPackageValues — restricted catalog.
+func TestGetPackageDocuments_Forbidden(t *testing.T) {
+	cfgs := []env.CatalogConfig{{
+		ID: "restricted",
+		Restrictions: []env.Restriction{
+			{UserAttributeKey: "groups", Match: "sspcloud-admin"},
+		},
+	}}
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+
+	_, err := uc.GetPackageReadme(ctx, "restricted", "my-chart", "1.0.0")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	_, err = uc.GetPackageValues(ctx, "restricted", "my-chart", "1.0.0")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	repo.AssertNotCalled(
+		t, "GetPackageReadme", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	)
+	repo.AssertNotCalled(
+		t, "GetPackageValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	)
+}
+
+// ✅ Catalogs are filtered by the user or project context.
+func TestListUserCatalogs_Visibility(t *testing.T) {
+	no := false
+	cfgs := []env.CatalogConfig{
+		{ID: "everywhere"},
+		{ID: "user-only", Visible: env.CatalogVisibility{Project: &no}},
+		{ID: "project-only", Visible: env.CatalogVisibility{User: &no}},
+	}
+
+	uc, ctx, repo := setupCatalogUsecase(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("ListPackages", mock.Anything, mock.Anything).Return([]domain.Package{}, nil)
+
+	ids := func(catalogs []domain.Catalog) []string {
+		out := make([]string, 0, len(catalogs))
+		for _, c := range catalogs {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	userCatalogs, _, err := uc.ListUserCatalogs(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"everywhere", "user-only"}, ids(userCatalogs))
+	assert.Equal(t, domain.CatalogVisibility{User: true, Project: false}, userCatalogs[1].Visible)
+
+	projectCatalogs, _, err := uc.ListUserCatalogs(ctx, "my-project")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"everywhere", "project-only"}, ids(projectCatalogs))
+}
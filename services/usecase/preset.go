@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/tools"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/onyxia-datalab/onyxia-backend/services/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+// Presets implements domain.PresetService. Presets are checked against the
+// chart schema at startup and whenever the revision of their catalog changes,
+// in the background; invalid presets are neither listed nor installable.
+type Presets struct {
+	catalogs  []env.CatalogConfig
+	pkgRepo   ports.PackageRepository
+	policy    *CatalogPolicy
+	lifecycle domain.ServiceLifecycle
+
+	// retryInterval is how long a check that could not complete is reused.
+	retryInterval time.Duration
+
+	mu       sync.Mutex
+	checks   map[string]presetCheck // by catalog id
+	checking singleflight.Group
+}
+
+// presetCheck is the outcome of checking the presets of a catalog revision.
+type presetCheck struct {
+	revision  string
+	invalid   map[string]error // by preset id
+	complete  bool
+	checkedAt time.Time
+}
+
+// Preset checks download charts: a catalog is checked by a single request at
+// a time, a few presets at once, and a check that could not complete is only
+// retried after presetRetryInterval.
+const (
+	maxConcurrentPresetChecks = 4
+	presetRetryInterval       = time.Minute
+)
+
+var _ domain.PresetService = (*Presets)(nil)
+
+func NewPresetService(
+	catalogs []env.CatalogConfig,
+	pkgRepo ports.PackageRepository,
+	policy *CatalogPolicy,
+	lifecycle domain.ServiceLifecycle,
+) *Presets {
+	return &Presets{
+		catalogs:      catalogs,
+		pkgRepo:       pkgRepo,
+		policy:        policy,
+		lifecycle:     lifecycle,
+		retryInterval: presetRetryInterval,
+		checks:        make(map[string]presetCheck),
+	}
+}
+
+func (uc *Presets) ListPresets(ctx context.Context, project string) ([]domain.Preset, error) {
+	var presets []domain.Preset
+	for _, cfg := range uc.catalogs {
+		if len(cfg.Presets) == 0 || !uc.policy.Offers(ctx, cfg, project) {
+			continue
+		}
+
+		invalid, err := uc.checked(ctx, cfg)
+		if err != nil {
+			slog.WarnContext(ctx, "Unable to check catalog presets",
+				slog.String("catalog", cfg.ID),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		for _, p := range cfg.Presets {
+			if invalid[p.ID] != nil || !uc.policy.CanAccessPackage(ctx, cfg.ID, p.Package) {
+				continue
+			}
+			preset, err := toDomainPreset(cfg.ID, p)
+			if err != nil {
+				return nil, err
+			}
+			presets = append(presets, preset)
+		}
+	}
+	return presets, nil
+}
+
+func (uc *Presets) InstallPreset(
+	ctx context.Context,
+	catalogID, presetID string,
+	req domain.StartRequest,
+) (domain.StartResponse, error) {
+	cfg, err := uc.policy.Authorize(ctx, catalogID)
+	if err != nil {
+		return domain.StartResponse{}, err
+	}
+	i := slices.IndexFunc(cfg.Presets, func(p env.Preset) bool { return p.ID == presetID })
+	if i < 0 {
+		return domain.StartResponse{}, fmt.Errorf(
+			"%w: preset %q not found in catalog %q",
+			domain.ErrNotFound, presetID, catalogID,
+		)
+	}
+	p := cfg.Presets[i]
+	if _, err := uc.policy.AuthorizePackage(ctx, cfg.ID, p.Package); err != nil {
+		return domain.StartResponse{}, err
+	}
+
+	invalid, err := uc.checked(ctx, *cfg)
+	if err != nil {
+		return domain.StartResponse{}, fmt.Errorf("check presets: %w", err)
+	}
+	if err := invalid[p.ID]; err != nil {
+		return domain.StartResponse{}, fmt.Errorf("preset %q: %w", p.ID, err)
+	}
+
+	version, err := uc.presetVersion(ctx, cfg.ID, p)
+	if err != nil {
+		return domain.StartResponse{}, fmt.Errorf("preset %q: %w", p.ID, err)
+	}
+	values, err := p.ParseValues()
+	if err != nil {
+		return domain.StartResponse{}, fmt.Errorf("preset %q: %w", p.ID, err)
+	}
+
+	req.CatalogID = cfg.ID
+	req.PackageName = p.Package
+	req.Version = version
+	req.Values = mergeValues(values, req.Values)
+	return uc.lifecycle.Start(ctx, req)
+}
+
+// CheckCatalogs checks the presets of every catalog, so that requests find
+// them checked. It is meant to run once, in the background, at startup.
+func (uc *Presets) CheckCatalogs(ctx context.Context) {
+	for _, cfg := range uc.catalogs {
+		if len(cfg.Presets) > 0 {
+			uc.recheck(ctx, cfg)
+		}
+	}
+}
+
+// CatalogChanged checks again, in the background, the presets of a catalog
+// whose index changed.
+func (uc *Presets) CatalogChanged(ctx context.Context, catalogID string) {
+	i := slices.IndexFunc(uc.catalogs, func(c env.CatalogConfig) bool { return c.ID == catalogID })
+	if i < 0 || len(uc.catalogs[i].Presets) == 0 {
+		return
+	}
+	go uc.recheck(ctx, uc.catalogs[i])
+}
+
+// checked returns the last check of the presets of cfg. A check of an older
+// revision, or one due for a retry, is still served while cfg is checked
+// again in the background; requests only wait when the catalog was never
+// checked, for instance while the startup check runs.
+func (uc *Presets) checked(ctx context.Context, cfg env.CatalogConfig) (map[string]error, error) {
+	revision, err := uc.pkgRepo.CatalogRevision(ctx, cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	if invalid, ok := uc.cachedCheck(cfg.ID, revision); ok {
+		return invalid, nil
+	}
+
+	uc.mu.Lock()
+	last, ok := uc.checks[cfg.ID]
+	uc.mu.Unlock()
+	if !ok {
+		return uc.checkRevision(ctx, cfg, revision)
+	}
+	go uc.checkRevision(context.WithoutCancel(ctx), cfg, revision)
+	return last.invalid, nil
+}
+
+// recheck runs check, logging its failure.
+func (uc *Presets) recheck(ctx context.Context, cfg env.CatalogConfig) {
+	if _, err := uc.check(ctx, cfg); err != nil {
+		slog.WarnContext(ctx, "Unable to check catalog presets",
+			slog.String("catalog", cfg.ID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// check returns the presets of cfg that failed validation against the
+// current revision of the catalog, with the reason. Presets that could not be
+// checked, for instance because a chart could not be downloaded, are
+// reported too, and checked again after the retry interval.
+func (uc *Presets) check(ctx context.Context, cfg env.CatalogConfig) (map[string]error, error) {
+	revision, err := uc.pkgRepo.CatalogRevision(ctx, cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.checkRevision(ctx, cfg, revision)
+}
+
+// checkRevision is check for a known revision of cfg.
+func (uc *Presets) checkRevision(
+	ctx context.Context,
+	cfg env.CatalogConfig,
+	revision string,
+) (map[string]error, error) {
+	if invalid, ok := uc.cachedCheck(cfg.ID, revision); ok {
+		return invalid, nil
+	}
+
+	ch := uc.checking.DoChan(cfg.ID+"@"+revision, func() (any, error) {
+		if invalid, ok := uc.cachedCheck(cfg.ID, revision); ok {
+			return invalid, nil
+		}
+		c := uc.checkPresets(context.WithoutCancel(ctx), cfg, revision)
+		uc.mu.Lock()
+		uc.checks[cfg.ID] = c
+		uc.mu.Unlock()
+		return c.invalid, nil
+	})
+	select {
+	case res := <-ch:
+		return res.Val.(map[string]error), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// cachedCheck returns the check of a catalog revision, unless it could not
+// complete and is due for a retry.
+func (uc *Presets) cachedCheck(catalogID, revision string) (map[string]error, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	c, ok := uc.checks[catalogID]
+	if !ok || c.revision != revision {
+		return nil, false
+	}
+	if !c.complete && time.Since(c.checkedAt) >= uc.retryInterval {
+		return nil, false
+	}
+	return c.invalid, true
+}
+
+// checkPresets validates the presets of cfg, a few at a time.
+func (uc *Presets) checkPresets(ctx context.Context, cfg env.CatalogConfig, revision string) presetCheck {
+	errs := make([]error, len(cfg.Presets))
+	sem := make(chan struct{}, maxConcurrentPresetChecks)
+	var wg sync.WaitGroup
+	for i, p := range cfg.Presets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = uc.validate(ctx, cfg.ID, p)
+		}()
+	}
+	wg.Wait()
+
+	c := presetCheck{
+		revision:  revision,
+		invalid:   make(map[string]error),
+		complete:  true,
+		checkedAt: time.Now(),
+	}
+	for i, p := range cfg.Presets {
+		err := errs[i]
+		if err == nil {
+			continue
+		}
+		slog.WarnContext(ctx, "Invalid catalog preset",
+			slog.String("catalog", cfg.ID),
+			slog.String("preset", p.ID),
+			slog.Any("error", err),
+		)
+		c.invalid[p.ID] = err
+		if !errors.Is(err, domain.ErrInvalidInput) && !errors.Is(err, domain.ErrNotFound) {
+			c.complete = false
+		}
+	}
+	return c
+}
+
+// validate checks the values of p against the schema of the chart version
+// it installs.
+func (uc *Presets) validate(ctx context.Context, catalogID string, p env.Preset) error {
+	version, err := uc.presetVersion(ctx, catalogID, p)
+	if err != nil {
+		return err
+	}
+	values, err := p.ParseValues()
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	return uc.pkgRepo.ValidateValues(ctx, catalogID, p.Package, version, values)
+}
+
+// presetVersion returns the version p installs: its own, or the latest
+// visible version of its package.
+func (uc *Presets) presetVersion(ctx context.Context, catalogID string, p env.Preset) (string, error) {
+	if p.Version != "" {
+		return p.Version, nil
+	}
+	pkg, err := uc.pkgRepo.GetPackage(ctx, catalogID, p.Package)
+	if err != nil {
+		return "", err
+	}
+	if pkg == nil || len(pkg.Versions) == 0 {
+		return "", fmt.Errorf("%w: package %q has no version", domain.ErrNotFound, p.Package)
+	}
+	return pkg.Versions[0].Version, nil
+}
+
+func toDomainPreset(catalogID string, p env.Preset) (domain.Preset, error) {
+	name, err := tools.NewLocalizedString(p.Name)
+	if err != nil {
+		return domain.Preset{}, fmt.Errorf("preset %q name: %w", p.ID, err)
+	}
+	var description tools.LocalizedString
+	if len(p.Description) > 0 {
+		if description, err = tools.NewLocalizedString(p.Description); err != nil {
+			return domain.Preset{}, fmt.Errorf("preset %q description: %w", p.ID, err)
+		}
+	}
+	values, err := p.ParseValues()
+	if err != nil {
+		return domain.Preset{}, fmt.Errorf("preset %q values: %w", p.ID, err)
+	}
+	return domain.Preset{
+		ID:          p.ID,
+		CatalogID:   catalogID,
+		Name:        name,
+		Description: description,
+		PackageName: p.Package,
+		Version:     p.Version,
+		Values:      values,
+	}, nil
+}
+
+// mergeValues returns override merged over base: nested maps are merged,
+// any other value of override replaces the one of base.
+func mergeValues(base, override map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		if vm, ok := v.(map[string]any); ok {
+			if bm, ok := out[k].(map[string]any); ok {
+				out[k] = mergeValues(bm, vm)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
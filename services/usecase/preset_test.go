@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
+	"github.com/onyxia-datalab/onyxia-backend/services/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockServiceLifecycle struct{ mock.Mock }
+
+func (m *MockServiceLifecycle) Start(
+	ctx context.Context,
+	req domain.StartRequest,
+) (domain.StartResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(domain.StartResponse), args.Error(1)
+}
+
+func (m *MockServiceLifecycle) Resume(ctx context.Context) error { return m.Called(ctx).Error(0) }
+func (m *MockServiceLifecycle) Delete(ctx context.Context) error { return m.Called(ctx).Error(0) }
+func (m *MockServiceLifecycle) Rename(ctx context.Context) error { return m.Called(ctx).Error(0) }
+func (m *MockServiceLifecycle) Share(ctx context.Context) error  { return m.Called(ctx).Error(0) }
+
+func setupPresets(
+	t *testing.T,
+	user *usercontext.User,
+	cfgs []env.CatalogConfig,
+) (*Presets, context.Context, *MockCatalogRepository, *MockServiceLifecycle) {
+	t.Helper()
+	ctx, reader, _ := usercontext.NewTestUserContext(user)
+	policy, err := NewCatalogPolicy(cfgs, reader)
+	require.NoError(t, err)
+
+	repo := &MockCatalogRepository{}
+	lifecycle := &MockServiceLifecycle{}
+	return NewPresetService(cfgs, repo, policy, lifecycle), ctx, repo, lifecycle
+}
+
+func presetCatalog(id string, presets ...env.Preset) env.CatalogConfig {
+	return env.CatalogConfig{ID: id, Presets: presets}
+}
+
+var gpuPreset = env.Preset{
+	ID:      "gpu",
+	Name:    map[string]string{"en": "Jupyter with GPU", "fr": "Jupyter avec GPU"},
+	Package: "jupyter",
+	Version: "1.2.0",
+	Values:  "resources:\n  limits:\n    nvidia.com/gpu: 1\n    memory: 8Gi\n",
+}
+
+// ✅ Valid presets of accessible catalogs are listed.
+func TestListPresets(t *testing.T) {
+	cfgs := []env.CatalogConfig{
+		presetCatalog("ide", gpuPreset),
+		{
+			ID:           "restricted",
+			Restrictions: []env.Restriction{{UserAttributeKey: "groups", Match: "^admins$"}},
+			Presets:      []env.Preset{{ID: "secret", Name: map[string]string{"en": "Secret"}, Package: "vault"}},
+		},
+	}
+	uc, ctx, repo, _ := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).Return(nil)
+
+	presets, err := uc.ListPresets(ctx, "")
+
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	assert.Equal(t, "gpu", presets[0].ID)
+	assert.Equal(t, "ide", presets[0].CatalogID)
+	assert.Equal(t, "jupyter", presets[0].PackageName)
+	assert.Equal(t, map[string]any{
+		"limits": map[string]any{"nvidia.com/gpu": float64(1), "memory": "8Gi"},
+	}, presets[0].Values["resources"])
+	repo.AssertNotCalled(t, "CatalogRevision", mock.Anything, "restricted")
+}
+
+// ❌ Presets rejected by the chart schema are not listed, until the catalog
+// changes.
+func TestListPresets_SkipsInvalid(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, ctx, repo, _ := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil).Once()
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Return(domain.ErrInvalidInput).Once()
+
+	presets, err := uc.ListPresets(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+
+	// Checked once per revision.
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil).Once()
+	presets, err = uc.ListPresets(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+
+	// The last check is served while the new revision is checked.
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r2", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Return(nil).Once()
+	presets, err = uc.ListPresets(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+	assert.Eventually(t, func() bool {
+		presets, err := uc.ListPresets(ctx, "")
+		return err == nil && len(presets) == 1
+	}, time.Second, 10*time.Millisecond)
+	repo.AssertNumberOfCalls(t, "ValidateValues", 2)
+}
+
+// ❌ Presets that could not be checked are checked again after the retry
+// interval.
+func TestListPresets_RetriesTransientErrors(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, ctx, repo, _ := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Return(errors.New("registry unavailable")).Once()
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Return(nil).Once()
+
+	presets, err := uc.ListPresets(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+
+	// The failed check is reused until the retry interval elapses.
+	presets, err = uc.ListPresets(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+	repo.AssertNumberOfCalls(t, "ValidateValues", 1)
+
+	uc.retryInterval = 0
+	assert.Eventually(t, func() bool {
+		presets, err := uc.ListPresets(ctx, "")
+		return err == nil && len(presets) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+// ✅ Presets checked at startup are listed without checking them again.
+func TestCheckCatalogs(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset), presetCatalog("empty")}
+	uc, ctx, repo, _ := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).Return(nil)
+
+	uc.CheckCatalogs(context.Background())
+	repo.AssertNumberOfCalls(t, "ValidateValues", 1)
+	repo.AssertNotCalled(t, "CatalogRevision", mock.Anything, "empty")
+
+	presets, err := uc.ListPresets(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, presets, 1)
+	repo.AssertNumberOfCalls(t, "ValidateValues", 1)
+}
+
+// ✅ A catalog whose index changed is checked again in the background.
+func TestCatalogChanged(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, _, repo, _ := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	checked := make(chan struct{})
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Run(func(mock.Arguments) { close(checked) }).
+		Return(nil)
+
+	uc.CatalogChanged(context.Background(), "unknown")
+	uc.CatalogChanged(context.Background(), "ide")
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("presets were not checked")
+	}
+}
+
+// ✅ Concurrent requests share a single check of the catalog.
+func TestListPresets_SharesCheck(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, ctx, repo, _ := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	started, release := make(chan struct{}), make(chan struct{})
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		}).
+		Return(nil)
+
+	const requests = 5
+	var wg sync.WaitGroup
+	results := make([][]domain.Preset, requests)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = uc.ListPresets(ctx, "")
+		}()
+	}
+	<-started
+	close(release)
+	wg.Wait()
+
+	repo.AssertNumberOfCalls(t, "ValidateValues", 1)
+	for _, presets := range results {
+		assert.Len(t, presets, 1)
+	}
+}
+
+// ✅ Installing a preset merges the user options over the preset values.
+func TestInstallPreset(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, ctx, repo, lifecycle := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).Return(nil)
+	lifecycle.On("Start", mock.Anything, mock.Anything).
+		Return(domain.StartResponse{Warnings: []string{"deprecated"}}, nil)
+
+	res, err := uc.InstallPreset(ctx, "ide", "gpu", domain.StartRequest{
+		Username:    "alice",
+		ReleaseID:   "jupyter-1",
+		PackageName: "ignored",
+		Values: map[string]any{
+			"resources": map[string]any{"limits": map[string]any{"memory": "16Gi"}},
+			"init":      map[string]any{"script": "setup.sh"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deprecated"}, res.Warnings)
+	lifecycle.AssertCalled(t, "Start", mock.Anything, domain.StartRequest{
+		Username:    "alice",
+		ReleaseID:   "jupyter-1",
+		CatalogID:   "ide",
+		PackageName: "jupyter",
+		Version:     "1.2.0",
+		Values: map[string]any{
+			"resources": map[string]any{"limits": map[string]any{
+				"nvidia.com/gpu": float64(1),
+				"memory":         "16Gi",
+			}},
+			"init": map[string]any{"script": "setup.sh"},
+		},
+	})
+}
+
+// ✅ Presets without a version install the latest visible one.
+func TestInstallPreset_LatestVersion(t *testing.T) {
+	preset := gpuPreset
+	preset.Version = ""
+	cfgs := []env.CatalogConfig{presetCatalog("ide", preset)}
+	uc, ctx, repo, lifecycle := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("GetPackage", mock.Anything, "ide", "jupyter").Return(&domain.PackageRef{
+		Versions: []domain.VersionInfo{{Version: "2.0.0"}, {Version: "1.2.0"}},
+	}, nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "2.0.0", mock.Anything).Return(nil)
+	lifecycle.On("Start", mock.Anything, mock.MatchedBy(func(req domain.StartRequest) bool {
+		return req.Version == "2.0.0"
+	})).Return(domain.StartResponse{}, nil)
+
+	_, err := uc.InstallPreset(ctx, "ide", "gpu", domain.StartRequest{})
+
+	require.NoError(t, err)
+	lifecycle.AssertNumberOfCalls(t, "Start", 1)
+}
+
+// ❌ Unknown presets are not found.
+func TestInstallPreset_NotFound(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, ctx, _, lifecycle := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+
+	_, err := uc.InstallPreset(ctx, "ide", "cpu", domain.StartRequest{})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	lifecycle.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+}
+
+// ❌ Invalid presets cannot be installed.
+func TestInstallPreset_Invalid(t *testing.T) {
+	cfgs := []env.CatalogConfig{presetCatalog("ide", gpuPreset)}
+	uc, ctx, repo, lifecycle := setupPresets(t, usercontext.DefaultTestUser(), cfgs)
+	repo.On("CatalogRevision", mock.Anything, "ide").Return("r1", nil)
+	repo.On("ValidateValues", mock.Anything, "ide", "jupyter", "1.2.0", mock.Anything).
+		Return(domain.ErrInvalidInput)
+
+	_, err := uc.InstallPreset(ctx, "ide", "gpu", domain.StartRequest{})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	lifecycle.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+}
+
+// ❌ Presets of restricted packages are not checked for users without access.
+func TestInstallPreset_RestrictedPackage(t *testing.T) {
+	cfg := presetCatalog("ide", gpuPreset)
+	cfg.PackageRestrictions = []env.PackageRestriction{
+		{Name: "jupyter", Restrictions: []env.Restriction{{Role: "^admin$"}}},
+	}
+	uc, ctx, repo, lifecycle := setupPresets(t, usercontext.DefaultTestUser(), []env.CatalogConfig{cfg})
+
+	_, err := uc.InstallPreset(ctx, "ide", "gpu", domain.StartRequest{})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	repo.AssertNotCalled(t, "CatalogRevision", mock.Anything, mock.Anything)
+	lifecycle.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+}
+
+// ❌ Presets of restricted catalogs require access to the catalog.
+func TestInstallPreset_Forbidden(t *testing.T) {
+	cfg := presetCatalog("restricted", gpuPreset)
+	cfg.Restrictions = []env.Restriction{{UserAttributeKey: "groups", Match: "^admins$"}}
+	uc, ctx, _, lifecycle := setupPresets(t, usercontext.DefaultTestUser(), []env.CatalogConfig{cfg})
+
+	_, err := uc.InstallPreset(ctx, "restricted", "gpu", domain.StartRequest{})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	lifecycle.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+}
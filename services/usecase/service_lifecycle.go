@@ -15,6 +15,7 @@ type ServiceLifecycle struct {
 	secrets ports.OnyxiaSecretGateway
 	helm    ports.HelmReleasesGateway
 	pkgRepo ports.PackageRepository
+	policy  *CatalogPolicy
 }
 
 var _ domain.ServiceLifecycle = (*ServiceLifecycle)(nil)
@@ -23,8 +24,9 @@ func NewServiceLifecycle(
 	secrets ports.OnyxiaSecretGateway,
 	helm ports.HelmReleasesGateway,
 	pkgRepo ports.PackageRepository,
+	policy *CatalogPolicy,
 ) *ServiceLifecycle {
-	return &ServiceLifecycle{secrets: secrets, helm: helm, pkgRepo: pkgRepo}
+	return &ServiceLifecycle{secrets: secrets, helm: helm, pkgRepo: pkgRepo, policy: policy}
 }
 
 func (uc *ServiceLifecycle) Start(
@@ -32,14 +34,44 @@ func (uc *ServiceLifecycle) Start(
 	req domain.StartRequest,
 ) (domain.StartResponse, error) {
 
-	// 1) Get the package from catalog + packageName + packageVersion
+	// 1) Check the user may install from this catalog
+	if _, err := uc.policy.AuthorizePackage(ctx, req.CatalogID, req.PackageName); err != nil {
+		return domain.StartResponse{}, err
+	}
+
+	// 2) Get the package from catalog + packageName + packageVersion
 	pkg, err := uc.pkgRepo.ResolvePackage(ctx, req.CatalogID, req.PackageName, req.Version)
 
 	if err != nil {
 		return domain.StartResponse{}, fmt.Errorf("resolve package: %w", err)
 	}
 
-	// 2) Create the  Secret Onyxia
+	var warnings []string
+	if pkg.Deprecated {
+		slog.WarnContext(ctx, "Installing a deprecated package",
+			slog.String("catalog", req.CatalogID),
+			slog.String("package", req.PackageName),
+			slog.String("version", pkg.Version),
+		)
+		warnings = append(warnings, fmt.Sprintf(
+			"package %q version %q is deprecated in catalog %q",
+			req.PackageName, pkg.Version, req.CatalogID,
+		))
+	}
+
+	if pkg.Unverified {
+		slog.WarnContext(ctx, "Installing a package that failed provenance verification",
+			slog.String("catalog", req.CatalogID),
+			slog.String("package", req.PackageName),
+			slog.String("version", pkg.Version),
+		)
+		warnings = append(warnings, fmt.Sprintf(
+			"package %q version %q could not be verified against the catalog keyring",
+			req.PackageName, pkg.Version,
+		))
+	}
+
+	// 3) Create the  Secret Onyxia
 
 	secretData := map[string][]byte{
 		"catalog":      []byte(req.CatalogID),
@@ -52,7 +84,7 @@ func (uc *ServiceLifecycle) Start(
 		return domain.StartResponse{}, fmt.Errorf("create onyxia secret: %w", err)
 	}
 
-	// 3) Start the helm install
+	// 4) Start the helm install
 	opts := ports.HelmStartOptions{
 		Callbacks: ports.HelmStartCallbacks{
 			OnStart: func(release, chart string) {
@@ -84,7 +116,7 @@ func (uc *ServiceLifecycle) Start(
 		return domain.StartResponse{}, fmt.Errorf("helm start: %w", err)
 	}
 
-	return domain.StartResponse{}, nil
+	return domain.StartResponse{Warnings: warnings}, nil
 }
 
 func (uc *ServiceLifecycle) Resume(ctx context.Context) error {
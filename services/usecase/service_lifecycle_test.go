@@ -5,6 +5,8 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/onyxia-datalab/onyxia-backend/internal/usercontext"
+	"github.com/onyxia-datalab/onyxia-backend/services/bootstrap/env"
 	"github.com/onyxia-datalab/onyxia-backend/services/domain"
 	"github.com/onyxia-datalab/onyxia-backend/services/ports"
 	"github.com/stretchr/testify/assert"
@@ -65,13 +67,25 @@ type serviceLifecycleMocks struct {
 
 func setupServiceLifecycle(t *testing.T) (*ServiceLifecycle, context.Context, serviceLifecycleMocks) {
 	t.Helper()
+	return setupServiceLifecycleWithCatalogs(t, []env.CatalogConfig{{ID: "my-catalog"}})
+}
+
+func setupServiceLifecycleWithCatalogs(
+	t *testing.T,
+	cfgs []env.CatalogConfig,
+) (*ServiceLifecycle, context.Context, serviceLifecycleMocks) {
+	t.Helper()
+	ctx, reader, _ := usercontext.NewTestUserContext(usercontext.DefaultTestUser())
 	mocks := serviceLifecycleMocks{
 		helm:    new(MockHelmReleasesGateway),
 		secrets: new(MockOnyxiaSecretGateway),
 		pkgRepo: new(MockCatalogRepository),
 	}
-	uc := NewServiceLifecycle(mocks.secrets, mocks.helm, mocks.pkgRepo)
-	return uc, context.Background(), mocks
+	policy, err := NewCatalogPolicy(cfgs, reader)
+	require.NoError(t, err)
+
+	uc := NewServiceLifecycle(mocks.secrets, mocks.helm, mocks.pkgRepo, policy)
+	return uc, ctx, mocks
 }
 
 func baseRequest() domain.StartRequest {
@@ -115,14 +129,57 @@ func TestStart_Success(t *testing.T) {
 	m.helm.On("StartInstall", ctx, req.Name, pkg, req.Values, mock.Anything).
 		Return(nil)
 
-	_, err := uc.Start(ctx, req)
+	res, err := uc.Start(ctx, req)
 
 	require.NoError(t, err)
+	assert.Empty(t, res.Warnings)
 	m.pkgRepo.AssertExpectations(t)
 	m.secrets.AssertExpectations(t)
 	m.helm.AssertExpectations(t)
 }
 
+// ✅ Installing a deprecated package succeeds with a warning.
+func TestStart_DeprecatedPackageWarns(t *testing.T) {
+	uc, ctx, m := setupServiceLifecycle(t)
+	req := baseRequest()
+	pkg := resolvedPkg(req)
+	pkg.Deprecated = true
+
+	m.pkgRepo.On("ResolvePackage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(pkg, nil)
+	m.secrets.On("EnsureOnyxiaSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	m.helm.On("StartInstall", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	res, err := uc.Start(ctx, req)
+
+	require.NoError(t, err)
+	require.Len(t, res.Warnings, 1)
+	assert.Contains(t, res.Warnings[0], "deprecated")
+}
+
+// ✅ Installing a package that failed provenance verification warns.
+func TestStart_UnverifiedPackageWarns(t *testing.T) {
+	uc, ctx, m := setupServiceLifecycle(t)
+	req := baseRequest()
+	pkg := resolvedPkg(req)
+	pkg.Unverified = true
+
+	m.pkgRepo.On("ResolvePackage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(pkg, nil)
+	m.secrets.On("EnsureOnyxiaSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	m.helm.On("StartInstall", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	res, err := uc.Start(ctx, req)
+
+	require.NoError(t, err)
+	require.Len(t, res.Warnings, 1)
+	assert.Contains(t, res.Warnings[0], "could not be verified")
+}
+
 // ✅ Secret data contains the expected fields.
 func TestStart_SecretDataIsCorrect(t *testing.T) {
 	uc, ctx, m := setupServiceLifecycle(t)
@@ -213,3 +270,33 @@ func TestStart_HelmError(t *testing.T) {
 
 	assert.ErrorContains(t, err, "invalid release name")
 }
+
+// ❌ Catalog restricted to other users → ErrForbidden, nothing resolved or installed.
+func TestStart_CatalogForbidden(t *testing.T) {
+	uc, ctx, m := setupServiceLifecycleWithCatalogs(t, []env.CatalogConfig{{
+		ID: "my-catalog",
+		Restrictions: []env.Restriction{
+			{UserAttributeKey: "groups", Match: "sspcloud-admin"},
+		},
+	}})
+	req := baseRequest()
+
+	_, err := uc.Start(ctx, req)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	m.pkgRepo.AssertNotCalled(t, "ResolvePackage")
+	m.secrets.AssertNotCalled(t, "EnsureOnyxiaSecret")
+	m.helm.AssertNotCalled(t, "StartInstall")
+}
+
+// ❌ Unknown catalog → ErrNotFound.
+func TestStart_CatalogNotFound(t *testing.T) {
+	uc, ctx, m := setupServiceLifecycle(t)
+	req := baseRequest()
+	req.CatalogID = "unknown"
+
+	_, err := uc.Start(ctx, req)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	m.pkgRepo.AssertNotCalled(t, "ResolvePackage")
+}